@@ -1,34 +1,78 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"github.com/artifakt-io/demo-controller/internal/config"
 	"github.com/artifakt-io/demo-controller/internal/controller"
+	"github.com/artifakt-io/demo-controller/internal/health"
+	"github.com/artifakt-io/demo-controller/internal/leaderelection"
+	"github.com/artifakt-io/demo-controller/internal/metrics"
+	"github.com/artifakt-io/demo-controller/internal/notification"
+	"github.com/artifakt-io/demo-controller/internal/tracing"
+	configv1alpha1 "github.com/artifakt-io/demo-controller/pkg/apis/config/v1alpha1"
 	clientset "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
 	informers "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions"
+	informersv1 "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/application/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 	"k8s.io/sample-controller/pkg/signals"
-	"time"
+	"os"
+	"strings"
 )
 
 var (
-	masterURL  string
-	kubeconfig string
+	configFile          string
+	masterURL           string
+	kubeconfig          string
+	healthAddr          string
+	enablePprof         bool
+	dryRun              bool
+	qualifyImageNames   bool
+	namespaces          string
+	applicationSelector string
+	notificationsAddr   string
+	notificationsSecret string
+	applicationPolicies bool
+	clusterTemplates    bool
+	leaderElection      leaderelection.Config
+	tracingConfig       tracing.Config
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "webhook":
+			runWebhook(os.Args[2:])
+			return
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		}
+	}
+
 	klog.InitFlags(nil)
 	flag.Parse()
 
 	// set up signals so we handle the first shutdown signal gracefully
 	stopCh := signals.SetupSignalHandler()
 
+	controllerConfig, err := config.Load(configFile)
+	if err != nil {
+		klog.Fatalf("Error loading configuration: %s", err.Error())
+	}
+
 	cfg, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
 	if err != nil {
 		klog.Fatalf("Error building kubeconfig: %s", err.Error())
 	}
+	cfg.QPS = controllerConfig.ClientConnection.QPS
+	cfg.Burst = int(controllerConfig.ClientConnection.Burst)
+	cfg.Wrap(metrics.InstrumentTransport)
 
 	kubeClient, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
@@ -40,24 +84,175 @@ func main() {
 		klog.Fatalf("Error building example clientset: %s", err.Error())
 	}
 
-	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Second*30)
-	applicationInformerFactory := informers.NewSharedInformerFactory(applicationClient, time.Second*30)
+	if _, err := labels.Parse(applicationSelector); err != nil {
+		klog.Fatalf("Error parsing --application-selector: %s", err.Error())
+	}
+
+	var scopes []controller.Informers
+	var factories []interface{ Start(<-chan struct{}) }
+	for _, namespace := range watchedNamespaces() {
+		kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, controllerConfig.ResyncPeriod.Duration,
+			kubeinformers.WithNamespace(namespace))
+		// Revisions, quotas and templates are not labeled like the
+		// applications, so they are watched through a factory ignoring the
+		// application selector, as are the applications counted by quotas.
+		applicationInformerFactory := informers.NewSharedInformerFactoryWithOptions(applicationClient, controllerConfig.ResyncPeriod.Duration,
+			informers.WithNamespace(namespace),
+			informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.LabelSelector = applicationSelector
+			}))
+		revisionInformerFactory := informers.NewSharedInformerFactoryWithOptions(applicationClient, controllerConfig.ResyncPeriod.Duration,
+			informers.WithNamespace(namespace))
 
-	applicationController := controller.NewController(
-		kubeClient,
-		applicationClient,
-		kubeInformerFactory.Apps().V1().Deployments(),
-		applicationInformerFactory.Cloudest().V1().Applications())
+		scope := controller.Informers{
+			Namespace:            namespace,
+			Deployments:          kubeInformerFactory.Apps().V1().Deployments(),
+			Pods:                 kubeInformerFactory.Core().V1().Pods(),
+			Services:             kubeInformerFactory.Core().V1().Services(),
+			Secrets:              kubeInformerFactory.Core().V1().Secrets(),
+			Applications:         applicationInformerFactory.Cloudest().V1().Applications(),
+			ApplicationRevisions: revisionInformerFactory.Cloudest().V1().ApplicationRevisions(),
+			ApplicationQuotas:    revisionInformerFactory.Cloudest().V1().ApplicationQuotas(),
+			ApplicationTemplates: revisionInformerFactory.Cloudest().V1().ApplicationTemplates(),
+		}
+		if applicationSelector != "" {
+			// The quotas count the applications of every controller sharing
+			// the namespace.
+			scope.QuotaApplications = revisionInformerFactory.Cloudest().V1().Applications()
+		}
+		scopes = append(scopes, scope)
+		factories = append(factories, kubeInformerFactory, applicationInformerFactory, revisionInformerFactory)
+	}
 
-	kubeInformerFactory.Start(stopCh)
-	applicationInformerFactory.Start(stopCh)
+	applicationController := controller.NewScopedController(kubeClient, applicationClient,
+		config.NewRateLimiter(controllerConfig.Backoff), scopes...)
+	applicationController.AutoRollback = controllerConfig.FeatureGates[configv1alpha1.AutoRollback]
+	applicationController.ReconcileTimeout = controllerConfig.ReconcileTimeout.Duration
+	applicationController.ShutdownGracePeriod = controllerConfig.ShutdownGracePeriod.Duration
+	applicationController.Defaulting.QualifyImageName = qualifyImageNames
+	applicationController.WatchImages(scopes...)
+	applicationController.PinImageDigests = controllerConfig.FeatureGates[configv1alpha1.PinImageDigests]
+	// ApplicationPolicies and ClusterApplicationTemplates are cluster-scoped,
+	// whatever the watched namespaces. The factory only starts the informers
+	// of the enabled features.
+	policyInformerFactory := informers.NewSharedInformerFactory(applicationClient, controllerConfig.ResyncPeriod.Duration)
+	if applicationPolicies {
+		applicationController.WatchPolicies(policyInformerFactory.Cloudest().V1().ApplicationPolicies())
+	}
+	var clusterTemplateInformer informersv1.ClusterApplicationTemplateInformer
+	if clusterTemplates {
+		clusterTemplateInformer = policyInformerFactory.Cloudest().V1().ClusterApplicationTemplates()
+	}
+	applicationController.WatchTemplates(clusterTemplateInformer, scopes...)
+	applicationController.WatchQuotas(scopes...)
+	factories = append(factories, policyInformerFactory)
+	var dryRunReport *controller.DryRunReport
+	if dryRun {
+		klog.Info("Running in dry-run mode, no change is persisted")
+		dryRunReport = applicationController.EnableDryRun()
+	}
+
+	leader := &leaderelection.State{}
+	metrics.Registry.MustRegister(
+		metrics.ApplicationCollector{Lister: applicationController.ApplicationsLister},
+		metrics.LeaderCollector{State: leader},
+	)
+
+	// Informers run on standbys too so that a new leader starts from warm caches.
+	for _, factory := range factories {
+		factory.Start(stopCh)
+	}
 
-	if err = applicationController.Run(2, stopCh); err != nil {
-		klog.Fatalf("Error running controller: %s", err.Error())
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracingConfig)
+	if err != nil {
+		klog.Fatalf("Error setting up tracing: %s", err.Error())
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			klog.Errorf("Error flushing traces: %s", err.Error())
+		}
+	}()
+
+	if healthAddr != "" {
+		healthServer := health.NewServer(healthAddr)
+		healthServer.Liveness = []health.Check{{Name: "workqueue", Check: applicationController.Healthy}}
+		healthServer.Readiness = []health.Check{{Name: "controller", Check: applicationController.Ready}}
+		healthServer.Handle("/metrics", metrics.Handler())
+		if enablePprof {
+			healthServer.EnablePprof()
+		}
+		if dryRunReport != nil {
+			healthServer.Handle("/dry-run", dryRunReport)
+		}
+		go func() {
+			if err := healthServer.Run(ctx); err != nil {
+				klog.Fatalf("Error running health server: %s", err.Error())
+			}
+		}()
+	}
+
+	if notificationsAddr != "" {
+		secret, err := os.ReadFile(notificationsSecret)
+		if err != nil {
+			klog.Fatalf("Error reading --registry-notifications-secret-file: %s", err.Error())
+		}
+		if secret = []byte(strings.TrimSpace(string(secret))); len(secret) == 0 {
+			klog.Fatal("--registry-notifications-secret-file is empty")
+		}
+		receiver := notification.NewReceiver(notificationsAddr, secret, applicationController.HandlePush)
+		receiver.Leading = leader.IsLeader
+		go func() {
+			if err := receiver.Run(ctx); err != nil {
+				klog.Fatalf("Error running notification receiver: %s", err.Error())
+			}
+		}()
+	}
+
+	err = leaderelection.Run(ctx, leaderElection, kubeClient, leader, func(ctx context.Context) {
+		if err := applicationController.Run(ctx, int(controllerConfig.Workers)); err != nil {
+			klog.Fatalf("Error running controller: %s", err.Error())
+		}
+	})
+	if err != nil {
+		klog.Fatalf("Error running leader election: %s", err.Error())
+	}
+}
+
+// watchedNamespaces returns the namespaces set with --namespaces, or the
+// whole cluster.
+func watchedNamespaces() []string {
+	var watched []string
+	for _, namespace := range strings.Split(namespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			watched = append(watched, namespace)
+		}
+	}
+	if len(watched) == 0 {
+		return []string{metav1.NamespaceAll}
 	}
+	return watched
 }
 
 func init() {
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	flag.StringVar(&configFile, "config", "", "Path to a ControllerConfiguration file. The default configuration is used when empty.")
+	flag.StringVar(&namespaces, "namespaces", "", "Comma-separated list of the namespaces watched by the controller. All namespaces are watched when empty.")
+	flag.StringVar(&applicationSelector, "application-selector", "", "Label selector restricting the applications reconciled by the controller, such as tenant=a.")
+	flag.StringVar(&healthAddr, "health-addr", ":8081", "The address serving the /healthz and /readyz probes and /metrics. Empty disables it.")
+	flag.BoolVar(&enablePprof, "enable-pprof", false, "Serve the pprof profiles on /debug/pprof of --health-addr. They are unauthenticated, only enable them while debugging.")
+	flag.StringVar(&notificationsAddr, "registry-notifications-addr", "", "The address receiving the push notifications of registries, which trigger the sync of the applications running the pushed images. Empty disables it.")
+	flag.StringVar(&notificationsSecret, "registry-notifications-secret-file", "", "Path to the secret of the HMAC-SHA256 signing the registry notifications.")
+	flag.BoolVar(&qualifyImageNames, "qualify-image-names", false, "Reconcile the applications with their imageName in its fully qualified registry/repository:tag form, as the defaulting webhook started with the same flag stores it.")
+	flag.BoolVar(&applicationPolicies, "application-policies", true, "Enforce the cluster-scoped ApplicationPolicies, which requires cluster-wide list and watch RBAC on applicationpolicies.")
+	flag.BoolVar(&clusterTemplates, "cluster-templates", true, "Apply the ClusterApplicationTemplates the applications refer to, which requires cluster-wide list and watch RBAC on clusterapplicationtemplates.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Compute the changes of each reconcile with server-side dry-run requests without persisting them. Pending changes are logged and served on /dry-run of --health-addr.")
+	leaderElection.AddFlags(flag.CommandLine)
+	tracingConfig.AddFlags(flag.CommandLine)
 	flag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
 }
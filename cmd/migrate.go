@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/artifakt-io/demo-controller/internal/migration"
+	clientset "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"k8s.io/sample-controller/pkg/signals"
+)
+
+// runMigrate runs the migrate subcommand, rewriting the stored Applications
+// in the storage version of the CRD.
+func runMigrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	klog.InitFlags(flags)
+	flags.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	flags.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	_ = flags.Parse(args)
+
+	stopCh := signals.SetupSignalHandler()
+
+	cfg, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
+	if err != nil {
+		klog.Fatalf("Error building kubeconfig: %s", err.Error())
+	}
+	applicationClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building example clientset: %s", err.Error())
+	}
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building dynamic client: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	if err := migration.Migrate(ctx, applicationClient, dynamicClient); err != nil {
+		klog.Fatalf("Error migrating applications: %s", err.Error())
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/artifakt-io/demo-controller/internal/controller"
+	"github.com/artifakt-io/demo-controller/internal/health"
+	"github.com/artifakt-io/demo-controller/internal/policy"
+	"github.com/artifakt-io/demo-controller/internal/quota"
+	"github.com/artifakt-io/demo-controller/internal/template"
+	"github.com/artifakt-io/demo-controller/internal/webhook"
+	clientset "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
+	informers "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"k8s.io/sample-controller/pkg/signals"
+)
+
+// runWebhook runs the webhook subcommand, serving the admission webhooks of
+// Applications.
+func runWebhook(args []string) {
+	var webhookConfig webhook.Config
+	flags := flag.NewFlagSet("webhook", flag.ExitOnError)
+	klog.InitFlags(flags)
+	flags.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	flags.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	flags.StringVar(&healthAddr, "health-addr", ":8081", "The address serving the /healthz and /readyz probes. Empty disables it.")
+	webhookConfig.AddFlags(flags)
+	_ = flags.Parse(args)
+
+	if err := webhookConfig.Validate(); err != nil {
+		klog.Fatalf("Invalid flags: %s", err.Error())
+	}
+
+	stopCh := signals.SetupSignalHandler()
+
+	cfg, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
+	if err != nil {
+		klog.Fatalf("Error building kubeconfig: %s", err.Error())
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building kubernetes clientset: %s", err.Error())
+	}
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building dynamic client: %s", err.Error())
+	}
+	applicationClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building application clientset: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	informerFactory := informers.NewSharedInformerFactory(applicationClient, 0)
+	policies := informerFactory.Cloudest().V1().ApplicationPolicies()
+	policyEvaluator := policy.NewEvaluator(policies.Lister())
+	// Quotas are checked against the usage of the other Applications of
+	// the namespace.
+	applications := informerFactory.Cloudest().V1().Applications()
+	quotas := informerFactory.Cloudest().V1().ApplicationQuotas()
+	// Policies and quotas see the Applications with their template applied.
+	templates := informerFactory.Cloudest().V1().ApplicationTemplates()
+	clusterTemplates := informerFactory.Cloudest().V1().ClusterApplicationTemplates()
+	templateResolver := template.NewResolver(templates.Lister(), clusterTemplates.Lister())
+	quotaChecker := quota.NewChecker(quotas.Lister(), applications.Lister(), controller.NewDeployment)
+	quotaChecker.Defaulting.QualifyImageName = webhookConfig.QualifyImageNames
+	quotaChecker.Templates = templateResolver
+	informerFactory.Start(stopCh)
+
+	certificates := webhook.NewCertManager(webhookConfig.DNSNames(), webhook.PublishAll(
+		webhook.PublishCABundle(kubeClient, webhookConfig.ValidatingWebhookConfiguration, webhookConfig.MutatingWebhookConfiguration),
+		webhook.PublishConversionCABundle(dynamicClient, webhookConfig.CustomResourceDefinition)))
+	certificates.Validity = webhookConfig.CertValidity
+	certificates.RenewBefore = webhookConfig.CertRenewBefore
+	go certificates.Run(ctx)
+
+	if healthAddr != "" {
+		healthServer := health.NewServer(healthAddr)
+		healthServer.Readiness = []health.Check{
+			{Name: "certificate", Check: certificates.Ready},
+			{Name: "policies", Check: func() error {
+				if !policies.Informer().HasSynced() {
+					return fmt.Errorf("ApplicationPolicies not synced")
+				}
+				return nil
+			}},
+			{Name: "quotas", Check: func() error {
+				if !applications.Informer().HasSynced() || !quotas.Informer().HasSynced() {
+					return fmt.Errorf("Applications and ApplicationQuotas not synced")
+				}
+				return nil
+			}},
+			{Name: "templates", Check: func() error {
+				if !templates.Informer().HasSynced() || !clusterTemplates.Informer().HasSynced() {
+					return fmt.Errorf("ApplicationTemplates and ClusterApplicationTemplates not synced")
+				}
+				return nil
+			}},
+		}
+		go func() {
+			if err := healthServer.Run(ctx); err != nil {
+				klog.Fatalf("Error running health server: %s", err.Error())
+			}
+		}()
+	}
+
+	server := webhook.NewServer(webhookConfig.Addr, certificates)
+	server.Defaulting.QualifyImageName = webhookConfig.QualifyImageNames
+	server.Policies = policyEvaluator
+	server.Quotas = quotaChecker
+	server.Templates = templateResolver
+	if err := server.Run(ctx); err != nil {
+		klog.Fatalf("Error running webhook server: %s", err.Error())
+	}
+}
@@ -3,10 +3,28 @@ module github.com/artifakt-io/demo-controller
 go 1.16
 
 require (
-	k8s.io/api v0.22.2
-	k8s.io/apimachinery v0.22.2
-	k8s.io/client-go v0.22.2
-	k8s.io/code-generator v0.22.2
-	k8s.io/klog/v2 v2.9.0
+	github.com/blang/semver/v4 v4.0.0
+	github.com/evanphx/json-patch v4.12.0+incompatible
+	github.com/golang/protobuf v1.5.2
+	github.com/google/cel-go v0.10.1
+	github.com/google/go-containerregistry v0.6.0
+	github.com/google/gofuzz v1.1.0
+	github.com/prometheus/client_golang v1.12.1
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
+	go.opentelemetry.io/proto/otlp v0.9.0
+	golang.org/x/time v0.0.0-20220210224613-90d013bbcef8
+	golang.org/x/tools v0.1.10-0.20220218145154-897bd77cd717
+	k8s.io/api v0.24.0
+	k8s.io/apiextensions-apiserver v0.24.0
+	k8s.io/apimachinery v0.24.0
+	k8s.io/client-go v0.24.0
+	k8s.io/code-generator v0.24.0
+	k8s.io/klog/v2 v2.60.1
 	k8s.io/sample-controller v0.22.2
+	k8s.io/utils v0.0.0-20220210201930-3a6ce19ff2f9
+	sigs.k8s.io/controller-tools v0.9.2
+	sigs.k8s.io/yaml v1.3.0
 )
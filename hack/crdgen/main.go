@@ -0,0 +1,19 @@
+// Command crdgen writes the CustomResourceDefinitions generated from the types
+// of pkg/apis to manifests/crd.yaml.
+package main
+
+import (
+	"github.com/artifakt-io/demo-controller/internal/crd"
+	"io/ioutil"
+	"log"
+)
+
+func main() {
+	manifest, err := crd.Generate(crd.Packages)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("manifests/crd.yaml", manifest, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -24,5 +24,18 @@ CODEGEN_PKG=${CODEGEN_PKG:-$(cd "${SCRIPT_ROOT}"; ls -d -1 ./vendor/k8s.io/code-
 bash "${CODEGEN_PKG}"/generate-groups.sh all \
   github.com/artifakt-io/demo-controller/pkg/client \
   github.com/artifakt-io/demo-controller/pkg/apis  \
-  "application:v1" \
+  "application:v1,v2" \
+  --go-header-file "${SCRIPT_ROOT}"/hack/custom-boilerplate.go.txt
+
+bash "${CODEGEN_PKG}"/generate-internal-groups.sh conversion \
+  github.com/artifakt-io/demo-controller/pkg/client \
+  github.com/artifakt-io/demo-controller/pkg/apis \
+  github.com/artifakt-io/demo-controller/pkg/apis \
+  "application:v2" \
+  --go-header-file "${SCRIPT_ROOT}"/hack/custom-boilerplate.go.txt
+
+bash "${CODEGEN_PKG}"/generate-groups.sh deepcopy \
+  github.com/artifakt-io/demo-controller/pkg/client \
+  github.com/artifakt-io/demo-controller/pkg/apis  \
+  "config:v1alpha1" \
   --go-header-file "${SCRIPT_ROOT}"/hack/custom-boilerplate.go.txt
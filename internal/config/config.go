@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"github.com/artifakt-io/demo-controller/pkg/apis/config/v1alpha1"
+	"golang.org/x/time/rate"
+	"io/ioutil"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme, serializer.EnableStrict)
+)
+
+func init() {
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+}
+
+// Load reads the configuration file at path, defaults and validates it. The
+// default configuration is returned when path is empty.
+func Load(path string) (*v1alpha1.ControllerConfiguration, error) {
+	if path == "" {
+		config := &v1alpha1.ControllerConfiguration{}
+		scheme.Default(config)
+		return config, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	return Decode(data)
+}
+
+// Decode decodes a YAML or JSON configuration, defaults and validates it.
+// Unknown fields are rejected.
+func Decode(data []byte) (*v1alpha1.ControllerConfiguration, error) {
+	obj, gvk, err := codecs.UniversalDecoder(v1alpha1.SchemeGroupVersion).Decode(data, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the configuration: %w", err)
+	}
+	config, ok := obj.(*v1alpha1.ControllerConfiguration)
+	if !ok {
+		return nil, fmt.Errorf("unexpected configuration kind %s", gvk)
+	}
+	if errs := v1alpha1.ValidateControllerConfiguration(config); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration: %w", errs.ToAggregate())
+	}
+	return config, nil
+}
+
+// NewRateLimiter returns the rate limiter of the workqueue configured by
+// backoff, as workqueue.DefaultControllerRateLimiter does with its defaults.
+func NewRateLimiter(backoff v1alpha1.BackoffConfiguration) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(backoff.BaseDelay.Duration, backoff.MaxDelay.Duration),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(backoff.QPS), int(backoff.Burst))},
+	)
+}
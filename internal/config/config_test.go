@@ -0,0 +1,99 @@
+package config_test
+
+import (
+	"github.com/artifakt-io/demo-controller/internal/config"
+	"github.com/artifakt-io/demo-controller/pkg/apis/config/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	c, err := config.Load("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &v1alpha1.ControllerConfiguration{
+		Workers:             2,
+		ResyncPeriod:        metav1.Duration{Duration: 30 * time.Second},
+		ReconcileTimeout:    metav1.Duration{Duration: time.Minute},
+		ShutdownGracePeriod: metav1.Duration{Duration: 30 * time.Second},
+		Backoff: v1alpha1.BackoffConfiguration{
+			BaseDelay: metav1.Duration{Duration: 5 * time.Millisecond},
+			MaxDelay:  metav1.Duration{Duration: 1000 * time.Second},
+			QPS:       10,
+			Burst:     100,
+		},
+		ClientConnection: v1alpha1.ClientConnectionConfiguration{QPS: 20, Burst: 30},
+		FeatureGates:     map[string]bool{v1alpha1.AutoRollback: true, v1alpha1.PinImageDigests: true},
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("expected %+v, got %+v", expected, c)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	c, err := config.Decode([]byte(`
+apiVersion: config.cloudest.artifakt.io/v1alpha1
+kind: ControllerConfiguration
+workers: 8
+resyncPeriod: 5m
+clientConnection:
+  qps: 50
+featureGates:
+  AutoRollback: false
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Workers != 8 || c.ResyncPeriod.Duration != 5*time.Minute {
+		t.Errorf("unexpected workers or resync period: %+v", c)
+	}
+	if c.ClientConnection.QPS != 50 || c.ClientConnection.Burst != 30 {
+		t.Errorf("expected the client connection to be partially defaulted, got %+v", c.ClientConnection)
+	}
+	if c.FeatureGates[v1alpha1.AutoRollback] {
+		t.Errorf("expected AutoRollback to be disabled")
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "missing kind",
+			config: "workers: 2",
+			err:    "Object 'Kind' is missing",
+		},
+		{
+			name: "unknown field",
+			config: `apiVersion: config.cloudest.artifakt.io/v1alpha1
+kind: ControllerConfiguration
+worker: 2`,
+			err: `unknown field "worker"`,
+		},
+		{
+			name: "invalid values",
+			config: `apiVersion: config.cloudest.artifakt.io/v1alpha1
+kind: ControllerConfiguration
+workers: -1
+backoff:
+  baseDelay: 1m
+  maxDelay: 1s
+featureGates:
+  Unknown: true`,
+			err: "invalid configuration: [workers: Invalid value: -1: must be at least 1, backoff.maxDelay: Invalid value: \"1s\": must not be less than baseDelay, featureGates[Unknown]: Unsupported value: \"Unknown\": supported values: \"AutoRollback\", \"PinImageDigests\"]",
+		},
+	}
+	for _, test := range tests {
+		_, err := config.Decode([]byte(test.config))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: expected error containing %q, got %v", test.name, test.err, err)
+		}
+	}
+}
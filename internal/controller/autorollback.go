@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+const reasonCrashLoopBackOff = "CrashLoopBackOff"
+
+// isRolledBack reports whether the workload of app was rolled back for the
+// current generation of its spec. The spec image is not retried until the
+// spec changes again.
+func isRolledBack(app *v1.Application) bool {
+	condition := meta.FindStatusCondition(app.Status.Conditions, v1.ApplicationRolledBack)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == app.Generation
+}
+
+// workloadImage returns the image the workload of app must run.
+func workloadImage(app *v1.Application) string {
+	if isRolledBack(app) {
+		return app.Status.LastReadyImage
+	}
+	return app.Spec.ImageName
+}
+
+// autoRollback reverts deployment to the last image which reached Ready when
+// the rollout of the spec image exceeded its progress deadline or its pods
+// are crash-looping. It reports whether a rollback was started.
+func (c *Controller) autoRollback(ctx context.Context, app *v1.Application, status *v1.ApplicationStatus, deployment *appsv1.Deployment) (bool, error) {
+	if condition := meta.FindStatusCondition(status.Conditions, v1.ApplicationRolledBack); condition != nil {
+		if condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == app.Generation {
+			return false, nil
+		}
+		// The spec changed since the rollback: its image is tried again.
+		meta.RemoveStatusCondition(&status.Conditions, v1.ApplicationRolledBack)
+	}
+
+	image := mainContainerFromDeploymentTemplate(deployment).Image
+	if image != app.Spec.ImageName {
+		return false, nil
+	}
+	if meta.IsStatusConditionTrue(status.Conditions, v1.ApplicationReady) {
+		status.LastReadyImage = image
+		return false, nil
+	}
+	if status.LastReadyImage == "" || status.LastReadyImage == image {
+		return false, nil
+	}
+
+	reason := ""
+	if meta.IsStatusConditionTrue(status.Conditions, v1.ApplicationFailed) {
+		reason = reasonProgressDeadlineExceeded
+	} else {
+		crashLooping, err := c.crashLooping(ctx, app, image)
+		if err != nil {
+			return false, err
+		}
+		if crashLooping {
+			reason = reasonCrashLoopBackOff
+		}
+	}
+	if reason == "" {
+		return false, nil
+	}
+
+	klog.V(4).Infof("Rolling back application %s/%s from image %s to %s", app.Namespace, app.Name, image, status.LastReadyImage)
+	_, err := c.updateDeployment(ctx, rollbackDeployment(app, deployment, status.LastReadyImage))
+	if err != nil {
+		return false, err
+	}
+
+	message := fmt.Sprintf(MessageRolledBack, image, reason, status.LastReadyImage)
+	c.setCondition(status, metav1.Condition{
+		Type:               v1.ApplicationRolledBack,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: app.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	c.Recorder.Event(app, corev1.EventTypeWarning, ErrRolledBack, message)
+	return true, nil
+}
+
+// rollbackDeployment returns deployment, which serves app, running image. The
+// active color of a blue/green application keeps its color and replicas.
+func rollbackDeployment(app *v1.Application, deployment *appsv1.Deployment, image string) *appsv1.Deployment {
+	if color, ok := deployment.Spec.Template.Labels[ColorLabel]; ok {
+		return NewColorDeployment(app, color, image, *deployment.Spec.Replicas)
+	}
+	return newDeployment(app, image)
+}
+
+// crashLooping reports whether a pod of app running image is in
+// CrashLoopBackOff past the CrashLoopRestartThreshold.
+func (c *Controller) crashLooping(ctx context.Context, app *v1.Application, image string) (bool, error) {
+	pods, err := c.PodsLister.Pods(app.Namespace).List(labels.SelectorFromSet(selectorLabels(app)))
+	if err != nil {
+		return false, err
+	}
+
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if container.Name != "main" || container.Image != image {
+				continue
+			}
+			for _, containerStatus := range pod.Status.ContainerStatuses {
+				if containerStatus.Name == "main" &&
+					containerStatus.RestartCount >= c.CrashLoopRestartThreshold &&
+					containerStatus.State.Waiting != nil &&
+					containerStatus.State.Waiting.Reason == reasonCrashLoopBackOff {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,339 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"time"
+)
+
+// ColorLabel tells apart the pods of the blue and green deployments of an
+// application.
+const ColorLabel = "cloudest.artifakt.io/color"
+
+const (
+	blue  = "blue"
+	green = "green"
+
+	defaultBlueGreenPort  = 80
+	defaultScaleDownDelay = 30 * time.Second
+
+	reasonBlueGreenPreview = "BlueGreenPreview"
+)
+
+func usesBlueGreen(app *v1.Application) bool {
+	return app.Spec.Rollout != nil && app.Spec.Rollout.BlueGreen != nil
+}
+
+func otherColor(color string) string {
+	if color == blue {
+		return green
+	}
+	return blue
+}
+
+// NewColorDeployment returns the deployment of the given color of app.
+func NewColorDeployment(app *v1.Application, color, image string, replicas int32) *appsv1.Deployment {
+	deployment := newDeployment(app, image)
+	deployment.Name = app.Name + "-" + color
+	deployment.Spec.Replicas = &replicas
+	deployment.Spec.Selector.MatchLabels = map[string]string{ColorLabel: color}
+	for k, v := range selectorLabels(app) {
+		deployment.Spec.Selector.MatchLabels[k] = v
+	}
+	deployment.Spec.Template.Labels[ColorLabel] = color
+	return deployment
+}
+
+// NewService returns the Service name of app targeting the pods of color.
+func NewService(app *v1.Application, name, color string) *corev1.Service {
+	port := int32(defaultBlueGreenPort)
+	if app.Spec.Rollout.BlueGreen.Port != 0 {
+		port = app.Spec.Rollout.BlueGreen.Port
+	}
+	selector := map[string]string{ColorLabel: color}
+	for k, v := range selectorLabels(app) {
+		selector[k] = v
+	}
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: app.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(app, v1.SchemeGroupVersion.WithKind("Application")),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       port,
+					TargetPort: intstr.FromInt(int(port)),
+				},
+			},
+		},
+	}
+}
+
+// syncBlueGreen reconciles an application rolled out with the blue/green
+// strategy. The active color runs behind the active Service while a new image
+// runs full-size in the other color, behind the preview Service, until it is
+// promoted.
+func (c *Controller) syncBlueGreen(ctx context.Context, key string, app *v1.Application) error {
+	strategy := app.Spec.Rollout.BlueGreen
+	total := desiredReplicas(app)
+	image := workloadImage(app)
+	now := c.Clock.Now()
+
+	status := app.Status.DeepCopy()
+	if status.BlueGreen == nil {
+		status.BlueGreen = &v1.BlueGreenStatus{Phase: v1.BlueGreenActive, ActiveColor: blue}
+	}
+	bg := status.BlueGreen
+
+	active, err := c.getOrCreateDeployment(ctx, NewColorDeployment(app, bg.ActiveColor, image, total))
+	if err != nil {
+		return err
+	}
+	activeImage := mainContainerFromDeploymentTemplate(active).Image
+	previewColor := otherColor(bg.ActiveColor)
+	preview, err := c.getDeployment(ctx, app.Namespace, app.Name+"-"+previewColor)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		preview = nil
+	}
+
+	plan := &rolloutPlan{}
+	if image != activeImage {
+		if bg.Phase != v1.BlueGreenPreviewing {
+			bg.Phase = v1.BlueGreenPreviewing
+			bg.PreviewColor = previewColor
+			bg.PreviewReadyTime = nil
+			bg.ScaleDownTime = nil
+		}
+
+		desired := NewColorDeployment(app, previewColor, image, total)
+		if preview == nil {
+			preview, err = c.createDeployment(ctx, desired)
+		} else {
+			preview, err = c.syncDeployment(ctx, preview, desired)
+		}
+		if err != nil {
+			return err
+		}
+
+		plan = &rolloutPlan{inFlight: true, reason: reasonBlueGreenPreview}
+		state, _ := deploymentRolloutState(preview)
+		reason := ""
+		if state == rolloutFailed {
+			reason = reasonProgressDeadlineExceeded
+		} else if crashLooping, err := c.crashLooping(ctx, app, image); err != nil {
+			return err
+		} else if crashLooping {
+			reason = reasonCrashLoopBackOff
+		}
+
+		switch {
+		case reason != "":
+			preview, err = c.abortPreview(ctx, app, status, preview, activeImage, reason)
+			if err != nil {
+				return err
+			}
+			plan = &rolloutPlan{}
+		case state == rolloutComplete:
+			if bg.PreviewReadyTime == nil {
+				bg.PreviewReadyTime = &metav1.Time{Time: now}
+			}
+			promote := app.Spec.Rollout.Promote
+			plan.promoted = promote
+			if !promote && strategy.AutoPromotionDelay != nil {
+				elapsed := now.Sub(bg.PreviewReadyTime.Time)
+				promote = elapsed >= strategy.AutoPromotionDelay.Duration
+				plan.requeueAfter = strategy.AutoPromotionDelay.Duration - elapsed
+			}
+			if promote {
+				klog.V(4).Infof("Promoting %s deployment of application %s/%s", previewColor, app.Namespace, app.Name)
+				scaleDownDelay := defaultScaleDownDelay
+				if strategy.ScaleDownDelay != nil {
+					scaleDownDelay = strategy.ScaleDownDelay.Duration
+				}
+				bg.Phase = v1.BlueGreenScalingDown
+				bg.ActiveColor = previewColor
+				bg.PreviewColor = ""
+				bg.PreviewReadyTime = nil
+				bg.ScaleDownTime = &metav1.Time{Time: now.Add(scaleDownDelay)}
+				active, preview = preview, active
+				plan.inFlight = false
+				plan.requeueAfter = scaleDownDelay
+			} else {
+				plan.message = fmt.Sprintf("Image %q is ready in the %s deployment, waiting for promotion", image, previewColor)
+			}
+		default:
+			plan.message = fmt.Sprintf("Rolling out image %q in the %s deployment", image, previewColor)
+		}
+	} else {
+		active, err = c.syncDeployment(ctx, active, NewColorDeployment(app, bg.ActiveColor, activeImage, total))
+		if err != nil {
+			return err
+		}
+		if bg.Phase == v1.BlueGreenPreviewing {
+			// The spec went back to the active image in the middle of the preview.
+			bg.Phase = v1.BlueGreenScalingDown
+			bg.PreviewColor = ""
+			bg.PreviewReadyTime = nil
+			bg.ScaleDownTime = &metav1.Time{Time: now}
+		}
+	}
+
+	if bg.Phase == v1.BlueGreenScalingDown {
+		switch {
+		case preview == nil:
+			// The previous color is already gone, there is nothing to scale down.
+			bg.Phase = v1.BlueGreenActive
+			bg.ScaleDownTime = nil
+		case bg.ScaleDownTime != nil && bg.ScaleDownTime.After(now):
+			plan.requeueAfter = bg.ScaleDownTime.Sub(now)
+		default:
+			previewImage := mainContainerFromDeploymentTemplate(preview).Image
+			preview, err = c.syncDeployment(ctx, preview, NewColorDeployment(app, otherColor(bg.ActiveColor), previewImage, 0))
+			if err != nil {
+				return err
+			}
+			bg.Phase = v1.BlueGreenActive
+			bg.ScaleDownTime = nil
+		}
+	}
+
+	previewServiceColor := bg.ActiveColor
+	if bg.PreviewColor != "" {
+		previewServiceColor = bg.PreviewColor
+	}
+	if err := c.syncService(ctx, app, NewService(app, app.Name, bg.ActiveColor)); err != nil {
+		return err
+	}
+	if err := c.syncService(ctx, app, NewService(app, app.Name+"-preview", previewServiceColor)); err != nil {
+		return err
+	}
+
+	if status.DeploymentRefName == app.Name {
+		// The application switched over from the default strategy.
+		if err := c.deleteLegacyDeployment(ctx, app, active); err != nil {
+			return err
+		}
+	}
+
+	bg.Blue, bg.Green = nil, nil
+	for _, deployment := range []*appsv1.Deployment{active, preview} {
+		if deployment == nil {
+			continue
+		}
+		colorStatus := &v1.ColorStatus{
+			Image:         mainContainerFromDeploymentTemplate(deployment).Image,
+			Replicas:      *deployment.Spec.Replicas,
+			ReadyReplicas: deployment.Status.ReadyReplicas,
+		}
+		if deployment.Spec.Template.Labels[ColorLabel] == blue {
+			bg.Blue = colorStatus
+		} else {
+			bg.Green = colorStatus
+		}
+	}
+
+	status.DeploymentRefNamespace = active.Namespace
+	status.DeploymentRefName = active.Name
+	return c.completeSync(ctx, key, app, status, active, plan)
+}
+
+// abortPreview scales down the preview deployment of a failed blue/green
+// rollout and keeps the active image until the spec changes again.
+func (c *Controller) abortPreview(ctx context.Context, app *v1.Application, status *v1.ApplicationStatus, preview *appsv1.Deployment, activeImage, reason string) (*appsv1.Deployment, error) {
+	bg := status.BlueGreen
+	image := mainContainerFromDeploymentTemplate(preview).Image
+	klog.V(4).Infof("Aborting blue/green rollout of application %s/%s: %s", app.Namespace, app.Name, reason)
+	preview, err := c.syncDeployment(ctx, preview, NewColorDeployment(app, bg.PreviewColor, image, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	bg.Phase = v1.BlueGreenActive
+	bg.PreviewColor = ""
+	bg.PreviewReadyTime = nil
+	status.LastReadyImage = activeImage
+
+	message := fmt.Sprintf(MessageRolledBack, image, reason, activeImage)
+	c.setCondition(status, metav1.Condition{
+		Type:               v1.ApplicationRolledBack,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: app.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	c.Recorder.Event(app, corev1.EventTypeWarning, ErrRolledBack, message)
+	return preview, nil
+}
+
+// deleteLegacyDeployment deletes the deployment of the default strategy once
+// the active color took over.
+func (c *Controller) deleteLegacyDeployment(ctx context.Context, app *v1.Application, active *appsv1.Deployment) error {
+	if state, _ := deploymentRolloutState(active); state != rolloutComplete {
+		return nil
+	}
+	klog.V(4).Infof("Deleting deployment %s/%s replaced by %s", app.Namespace, app.Name, active.Name)
+	err := c.deleteDeployment(ctx, app.Namespace, app.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// getOrCreateDeployment returns the deployment named after desired, creating
+// it from desired when missing.
+func (c *Controller) getOrCreateDeployment(ctx context.Context, desired *appsv1.Deployment) (*appsv1.Deployment, error) {
+	deployment, err := c.getDeployment(ctx, desired.Namespace, desired.Name)
+	if errors.IsNotFound(err) {
+		return c.createDeployment(ctx, desired)
+	}
+	return deployment, err
+}
+
+// syncService creates the Service desired or updates its selector and ports.
+// A Service of the same name which is not controlled by app is left alone.
+func (c *Controller) syncService(ctx context.Context, app *v1.Application, desired *corev1.Service) error {
+	service, err := c.ServicesLister.Services(desired.Namespace).Get(desired.Name)
+	if errors.IsNotFound(err) {
+		return c.createService(ctx, desired)
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(service, app) {
+		message := fmt.Sprintf(MessageResourceExists, service.Name)
+		c.Recorder.Event(app, corev1.EventTypeWarning, ErrResourceExists, message)
+		return fmt.Errorf("%s", message)
+	}
+
+	if equality.Semantic.DeepEqual(service.Spec.Selector, desired.Spec.Selector) &&
+		len(service.Spec.Ports) == 1 &&
+		service.Spec.Ports[0].Port == desired.Spec.Ports[0].Port &&
+		service.Spec.Ports[0].TargetPort == desired.Spec.Ports[0].TargetPort {
+		return nil
+	}
+
+	klog.V(4).Infof("Service %s/%s now targets %v", desired.Namespace, desired.Name, desired.Spec.Selector)
+	serviceCopy := service.DeepCopy()
+	serviceCopy.Spec.Selector = desired.Spec.Selector
+	serviceCopy.Spec.Ports = desired.Spec.Ports
+	return c.updateService(ctx, service, serviceCopy)
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// TrackLabel tells apart the pods of the canary deployment of an application
+// from its stable ones. Both share the selector labels of the application.
+const TrackLabel = "cloudest.artifakt.io/track"
+
+const (
+	reasonCanaryInProgress = "CanaryInProgress"
+	reasonCanaryPaused     = "CanaryPaused"
+)
+
+func usesCanary(app *v1.Application) bool {
+	return app.Spec.Rollout != nil && app.Spec.Rollout.Canary != nil
+}
+
+func desiredReplicas(app *v1.Application) int32 {
+	if app.Spec.Replicas != nil {
+		return *app.Spec.Replicas
+	}
+	return 1
+}
+
+// NewCanaryDeployment returns the canary deployment of app running image.
+func NewCanaryDeployment(app *v1.Application, image string, replicas int32) *appsv1.Deployment {
+	deployment := newDeployment(app, image)
+	deployment.Name = app.Name + "-canary"
+	deployment.Spec.Replicas = &replicas
+	deployment.Spec.Selector.MatchLabels = map[string]string{TrackLabel: "canary"}
+	for k, v := range selectorLabels(app) {
+		deployment.Spec.Selector.MatchLabels[k] = v
+	}
+	deployment.Spec.Template.Labels[TrackLabel] = "canary"
+	return deployment
+}
+
+// canaryReplicas returns the number of replicas out of total receiving weight
+// percent of the traffic, rounded up.
+func canaryReplicas(total, weight int32) int32 {
+	replicas := (total*weight + 99) / 100
+	if replicas > total {
+		return total
+	}
+	return replicas
+}
+
+// canaryWeight returns the weight set by the last SetWeight step up to index.
+func canaryWeight(steps []v1.CanaryStep, index int32) int32 {
+	weight := int32(0)
+	for i := int32(0); i <= index && int(i) < len(steps); i++ {
+		if steps[i].SetWeight != nil {
+			weight = *steps[i].SetWeight
+		}
+	}
+	return weight
+}
+
+// syncCanary drives the canary rollout of the workload image of app, stable
+// being the deployment running the previous image. It reports the image and
+// replica count of the stable deployment at the current step.
+func (c *Controller) syncCanary(ctx context.Context, app *v1.Application, status *v1.ApplicationStatus, stable *appsv1.Deployment) (*rolloutPlan, error) {
+	total := desiredReplicas(app)
+	image := workloadImage(app)
+	stableImage := mainContainerFromDeploymentTemplate(stable).Image
+
+	canary, err := c.getDeployment(ctx, app.Namespace, app.Name+"-canary")
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if errors.IsNotFound(err) {
+		canary = nil
+	}
+
+	if image == stableImage {
+		return c.finishCanary(ctx, app, status, stable, canary)
+	}
+
+	if status.Canary == nil || status.Canary.CanaryImage != image ||
+		(status.Canary.Phase != v1.CanaryProgressing && status.Canary.Phase != v1.CanaryPaused) {
+		status.Canary = &v1.CanaryStatus{
+			Phase:                v1.CanaryProgressing,
+			StableImage:          stableImage,
+			CanaryImage:          image,
+			CurrentStepStartTime: &metav1.Time{Time: c.Clock.Now()},
+		}
+	}
+
+	if canary != nil && mainContainerFromDeploymentTemplate(canary).Image == image {
+		reason := ""
+		if state, _ := deploymentRolloutState(canary); state == rolloutFailed {
+			reason = reasonProgressDeadlineExceeded
+		} else if crashLooping, err := c.crashLooping(ctx, app, image); err != nil {
+			return nil, err
+		} else if crashLooping {
+			reason = reasonCrashLoopBackOff
+		}
+		if reason != "" {
+			return c.abortCanary(ctx, app, status, stableImage, reason)
+		}
+	}
+
+	plan := &rolloutPlan{inFlight: true, reason: reasonCanaryInProgress}
+	steps := app.Spec.Rollout.Canary.Steps
+	now := c.Clock.Now()
+	for int(status.Canary.CurrentStepIndex) < len(steps) {
+		step := steps[status.Canary.CurrentStepIndex]
+		done := true
+		switch {
+		case step.SetWeight != nil:
+			replicas := canaryReplicas(total, *step.SetWeight)
+			done = canary != nil &&
+				mainContainerFromDeploymentTemplate(canary).Image == image &&
+				canary.Spec.Replicas != nil && *canary.Spec.Replicas == replicas
+			if done {
+				state, _ := deploymentRolloutState(canary)
+				done = state == rolloutComplete
+			}
+		case step.Pause != nil && step.Pause.Duration != nil:
+			elapsed := now.Sub(status.Canary.CurrentStepStartTime.Time)
+			if elapsed < step.Pause.Duration.Duration {
+				done = false
+				plan.requeueAfter = step.Pause.Duration.Duration - elapsed
+			}
+		case step.Pause != nil:
+			if app.Spec.Rollout.Promote {
+				plan.promoted = true
+			} else {
+				done = false
+				status.Canary.Phase = v1.CanaryPaused
+				plan.reason = reasonCanaryPaused
+			}
+		}
+		if !done {
+			break
+		}
+		status.Canary.Phase = v1.CanaryProgressing
+		status.Canary.CurrentStepIndex++
+		status.Canary.CurrentStepStartTime = &metav1.Time{Time: now}
+	}
+
+	if int(status.Canary.CurrentStepIndex) >= len(steps) {
+		// Every step passed: the stable deployment takes the canary image
+		// over and the canary deployment is removed once it rolled out.
+		klog.V(4).Infof("Promoting canary image %s of application %s/%s", image, app.Namespace, app.Name)
+		status.Canary.Phase = v1.CanaryPromoted
+		return &rolloutPlan{stableImage: image, stableReplicas: total, promoted: plan.promoted}, nil
+	}
+
+	weight := canaryWeight(steps, status.Canary.CurrentStepIndex)
+	replicas := canaryReplicas(total, weight)
+	desired := NewCanaryDeployment(app, image, replicas)
+	if canary == nil {
+		_, err = c.createDeployment(ctx, desired)
+	} else {
+		_, err = c.syncDeployment(ctx, canary, desired)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status.Canary.Weight = weight
+	status.Canary.CanaryReplicas = replicas
+	status.Canary.StableReplicas = total - replicas
+	plan.stableImage = stableImage
+	plan.stableReplicas = total - replicas
+	plan.message = fmt.Sprintf("Canary step %d of %d: %d%% of the replicas run image %q",
+		status.Canary.CurrentStepIndex+1, len(steps), weight, image)
+	return plan, nil
+}
+
+// finishCanary removes the canary deployment once the stable one rolled out
+// the image it runs.
+func (c *Controller) finishCanary(ctx context.Context, app *v1.Application, status *v1.ApplicationStatus, stable, canary *appsv1.Deployment) (*rolloutPlan, error) {
+	stableImage := mainContainerFromDeploymentTemplate(stable).Image
+	if status.Canary != nil && (status.Canary.Phase == v1.CanaryProgressing || status.Canary.Phase == v1.CanaryPaused) {
+		// The spec went back to the stable image in the middle of the rollout.
+		status.Canary.Phase = v1.CanaryAborted
+	}
+
+	if canary != nil {
+		if state, _ := deploymentRolloutState(stable); state == rolloutComplete {
+			klog.V(4).Infof("Deleting canary deployment %s/%s", canary.Namespace, canary.Name)
+			err := c.deleteDeployment(ctx, canary.Namespace, canary.Name)
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			if status.Canary != nil {
+				status.Canary.CanaryReplicas = 0
+				status.Canary.StableReplicas = desiredReplicas(app)
+			}
+		}
+	}
+	return &rolloutPlan{stableImage: stableImage, stableReplicas: desiredReplicas(app)}, nil
+}
+
+// abortCanary removes the canary deployment of a failed canary rollout and
+// keeps the stable image until the spec changes again.
+func (c *Controller) abortCanary(ctx context.Context, app *v1.Application, status *v1.ApplicationStatus, stableImage, reason string) (*rolloutPlan, error) {
+	name := app.Name + "-canary"
+	klog.V(4).Infof("Aborting canary rollout of application %s/%s: %s", app.Namespace, app.Name, reason)
+	err := c.deleteDeployment(ctx, app.Namespace, name)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	status.Canary.Phase = v1.CanaryAborted
+	status.Canary.Weight = 0
+	status.Canary.CanaryReplicas = 0
+	status.Canary.StableReplicas = desiredReplicas(app)
+	status.LastReadyImage = stableImage
+
+	message := fmt.Sprintf(MessageRolledBack, status.Canary.CanaryImage, reason, stableImage)
+	c.setCondition(status, metav1.Condition{
+		Type:               v1.ApplicationRolledBack,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: app.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	c.Recorder.Event(app, corev1.EventTypeWarning, ErrRolledBack, message)
+	return &rolloutPlan{stableImage: stableImage, stableReplicas: desiredReplicas(app)}, nil
+}
+
+// clearPromote resets spec.rollout.promote once the controller acted on it.
+func (c *Controller) clearPromote(ctx context.Context, app *v1.Application) error {
+	appCopy := c.forSpecUpdate(app)
+	if appCopy.Spec.Rollout == nil {
+		// promote is set by the template, which is not written.
+		return nil
+	}
+	appCopy.Spec.Rollout.Promote = false
+	_, err := c.updateApplication(ctx, app, appCopy)
+	return err
+}
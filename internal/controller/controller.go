@@ -1,26 +1,38 @@
 package controller
 
 import (
+	"context"
 	"fmt"
+	"github.com/artifakt-io/demo-controller/internal/images"
+	"github.com/artifakt-io/demo-controller/internal/metrics"
+	"github.com/artifakt-io/demo-controller/internal/policy"
+	"github.com/artifakt-io/demo-controller/internal/quota"
+	"github.com/artifakt-io/demo-controller/internal/template"
 	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
 	clientset "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
 	applicationscheme "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/scheme"
 	informers "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/application/v1"
 	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
-	"k8s.io/apimachinery/pkg/util/wait"
 	appsinformers "k8s.io/client-go/informers/apps/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -31,8 +43,64 @@ const (
 	ErrResourceExists     = "ErrResourceExists"
 	MessageResourceExists = "Resource %q already exists and is not managed by demo-controller"
 	MessageResourceSynced = "Application synced successfully"
+
+	SuccessSuspended      = "Suspended"
+	MessageSuspended      = "Application is suspended, the workload is not reconciled"
+	MessageSuspendedScale = "Application is suspended, the workload is scaled to zero"
+	SuccessResumed        = "Resumed"
+	MessageResumed        = "Application resumed, the workload is reconciled again"
+	ErrRolloutStalled     = "RolloutStalled"
+	MessageRolloutStalled = "Rollout of image %q exceeded its progress deadline"
+
+	SuccessRevisionRecorded = "RevisionRecorded"
+	MessageRevisionRecorded = "Recorded revision %d"
+	SuccessRollback         = "Rollback"
+	MessageRollback         = "Rolled back to revision %d"
+	ErrRevisionNotFound     = "RevisionNotFound"
+	MessageRevisionNotFound = "Unable to roll back: revision %d not found"
+	ErrRolledBack           = "RolledBack"
+	MessageRolledBack       = "Rollout of image %q failed (%s), rolled back to image %q"
+
+	ErrPolicyViolation     = "PolicyViolation"
+	MessagePolicyViolation = "Workload not reconciled, the application violates enforced policies: %s"
+	WarnPolicyViolation    = "PolicyWarning"
+	MessagePolicyWarning   = "The application violates policies: %s"
+
+	SuccessImageResolved   = "ImageResolved"
+	MessageImageResolved   = "Resolved image %q to %s"
+	ErrImageResolution     = "ImageResolutionFailed"
+	MessageImageResolution = "Unable to resolve image %q: %s"
+
+	SuccessImageUpdated      = "ImageUpdated"
+	MessageImageUpdated      = "Updated image from %q to %q"
+	ErrImageUpdate           = "ImageUpdateFailed"
+	MessageImageUpdateFailed = "Unable to update image %q: %s"
+
+	ErrSignatureVerification     = "SignatureVerificationFailed"
+	MessageSignatureVerification = "Image %q failed signature verification: %s"
+
+	ErrQuotaExceeded     = "QuotaExceeded"
+	MessageQuotaExceeded = "Workload not scaled up, it would exceed ApplicationQuotas: %s"
+
+	ErrTemplateNotFound     = "TemplateNotFound"
+	MessageTemplateNotFound = "Workload not reconciled, the template of the application is missing: %s"
 )
 
+// defaultRolloutRequeueDelay is the delay after which an application whose
+// rollout is in flight is synced again.
+const defaultRolloutRequeueDelay = 10 * time.Second
+
+// defaultReconcileTimeout bounds the reconcile of an application.
+const defaultReconcileTimeout = time.Minute
+
+// defaultShutdownGracePeriod is the time left to in-flight reconciles to
+// finish on shutdown.
+const defaultShutdownGracePeriod = 30 * time.Second
+
+// defaultCrashLoopRestartThreshold is the number of restarts of a pod in
+// CrashLoopBackOff after which its image is considered broken.
+const defaultCrashLoopRestartThreshold = 5
+
 // Controller is the controller implementation for application resources
 type Controller struct {
 	Kubeclientset        kubernetes.Interface
@@ -41,11 +109,86 @@ type Controller struct {
 	DeploymentsLister appslisters.DeploymentLister
 	DeploymentsSynced cache.InformerSynced
 
+	PodsLister corelisters.PodLister
+	PodsSynced cache.InformerSynced
+
+	ServicesLister corelisters.ServiceLister
+	ServicesSynced cache.InformerSynced
+
 	ApplicationsLister listers.ApplicationLister
 	ApplicationsSynced cache.InformerSynced
 
+	ApplicationRevisionsLister listers.ApplicationRevisionLister
+	ApplicationRevisionsSynced cache.InformerSynced
+
 	Workqueue workqueue.RateLimitingInterface
 	Recorder  record.EventRecorder
+	Clock     clock.Clock
+
+	RolloutRequeueDelay time.Duration
+
+	// AutoRollback reverts the workload to the last image which reached
+	// Ready when the rollout of a new image fails.
+	AutoRollback bool
+	// CrashLoopRestartThreshold is the number of restarts after which a
+	// crash-looping pod fails the rollout of its image.
+	CrashLoopRestartThreshold int32
+
+	// ReconcileTimeout bounds the duration of the reconcile of an
+	// application.
+	ReconcileTimeout time.Duration
+	// ShutdownGracePeriod is the time left to in-flight reconciles to
+	// finish when Run stops.
+	ShutdownGracePeriod time.Duration
+
+	// StuckItemThreshold is the processing time after which an item fails
+	// the Healthy check.
+	StuckItemThreshold time.Duration
+
+	// Tracer traces the reconciles, defaulting to the global provider.
+	Tracer trace.Tracer
+
+	// Defaulting are the optional defaults applied to the spec of the
+	// applications, as the defaulting webhook does.
+	Defaulting v1.DefaultingOptions
+
+	// DryRun, when set, collects the changes of the server-side dry-runs
+	// the controller makes instead of its writes. See EnableDryRun.
+	DryRun *DryRunReport
+
+	// Policies, when set, evaluates the ApplicationPolicies each
+	// application must comply with. See WatchPolicies.
+	Policies       *policy.Evaluator
+	PoliciesSynced cache.InformerSynced
+
+	// Quotas, when set, checks the scale-ups of the workloads against the
+	// ApplicationQuotas of their namespace. See WatchQuotas.
+	Quotas       *quota.Checker
+	QuotasSynced cache.InformerSynced
+
+	// Templates, when set, applies the templates the applications refer to
+	// to their spec. See WatchTemplates.
+	Templates       *template.Resolver
+	TemplatesSynced cache.InformerSynced
+	// applicationIndexer indexes the applications by template.
+	applicationIndexer cache.Indexer
+
+	// Images reads the images of the applications from their registry to
+	// pin their digest, to apply their ImageUpdatePolicy and to verify their
+	// signatures. See WatchImages.
+	Images       *images.Resolver
+	ImagesSynced cache.InformerSynced
+	// PinImageDigests pins the image of the workloads to the digest its tag
+	// points to.
+	PinImageDigests bool
+
+	// pushes holds the keys of the applications whose ImageUpdatePolicy
+	// allows a tag pushed since their last sync. See HandlePush.
+	pushes sync.Map
+	// resolvedImages holds the last resolvedImage of each application.
+	resolvedImages sync.Map
+
+	workers workerState
 }
 
 // NewController returns a new sample controller
@@ -53,7 +196,28 @@ func NewController(
 	kubeclientset kubernetes.Interface,
 	applicationClientset clientset.Interface,
 	deploymentInformer appsinformers.DeploymentInformer,
-	applicationInformer informers.ApplicationInformer) *Controller {
+	podInformer coreinformers.PodInformer,
+	serviceInformer coreinformers.ServiceInformer,
+	applicationInformer informers.ApplicationInformer,
+	applicationRevisionInformer informers.ApplicationRevisionInformer) *Controller {
+
+	return NewScopedController(kubeclientset, applicationClientset, workqueue.DefaultControllerRateLimiter(), Informers{
+		Deployments:          deploymentInformer,
+		Pods:                 podInformer,
+		Services:             serviceInformer,
+		Applications:         applicationInformer,
+		ApplicationRevisions: applicationRevisionInformer,
+	})
+}
+
+// NewScopedController returns a controller watching the namespaces of
+// scopes, each holding the informers of one namespace. Failed applications
+// are retried as allowed by rateLimiter.
+func NewScopedController(
+	kubeclientset kubernetes.Interface,
+	applicationClientset clientset.Interface,
+	rateLimiter workqueue.RateLimiter,
+	scopes ...Informers) *Controller {
 
 	utilruntime.Must(applicationscheme.AddToScheme(scheme.Scheme))
 	klog.V(4).Info("Creating event broadcaster")
@@ -62,48 +226,73 @@ func NewController(
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 
+	deployments := func(s Informers) cache.SharedIndexInformer { return s.Deployments.Informer() }
+	pods := func(s Informers) cache.SharedIndexInformer { return s.Pods.Informer() }
+	services := func(s Informers) cache.SharedIndexInformer { return s.Services.Informer() }
+	applications := func(s Informers) cache.SharedIndexInformer { return s.Applications.Informer() }
+	applicationRevisions := func(s Informers) cache.SharedIndexInformer { return s.ApplicationRevisions.Informer() }
+
 	controller := &Controller{
-		Kubeclientset:        kubeclientset,
-		ApplicationClientset: applicationClientset,
-		DeploymentsLister:    deploymentInformer.Lister(),
-		DeploymentsSynced:    deploymentInformer.Informer().HasSynced,
-		ApplicationsLister:   applicationInformer.Lister(),
-		ApplicationsSynced:   applicationInformer.Informer().HasSynced,
-		Workqueue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Applications"),
-		Recorder:             recorder,
+		Kubeclientset:              kubeclientset,
+		ApplicationClientset:       applicationClientset,
+		DeploymentsLister:          appslisters.NewDeploymentLister(indexerOf(scopes, deployments)),
+		DeploymentsSynced:          hasSynced(scopes, deployments),
+		PodsLister:                 corelisters.NewPodLister(indexerOf(scopes, pods)),
+		PodsSynced:                 hasSynced(scopes, pods),
+		ServicesLister:             corelisters.NewServiceLister(indexerOf(scopes, services)),
+		ServicesSynced:             hasSynced(scopes, services),
+		ApplicationsLister:         listers.NewApplicationLister(indexerOf(scopes, applications)),
+		ApplicationsSynced:         hasSynced(scopes, applications),
+		ApplicationRevisionsLister: listers.NewApplicationRevisionLister(indexerOf(scopes, applicationRevisions)),
+		ApplicationRevisionsSynced: hasSynced(scopes, applicationRevisions),
+		Workqueue:                  workqueue.NewNamedRateLimitingQueue(rateLimiter, "Applications"),
+		Recorder:                   recorder,
+		Clock:                      clock.RealClock{},
+		RolloutRequeueDelay:        defaultRolloutRequeueDelay,
+		AutoRollback:               true,
+		CrashLoopRestartThreshold:  defaultCrashLoopRestartThreshold,
+		ReconcileTimeout:           defaultReconcileTimeout,
+		ShutdownGracePeriod:        defaultShutdownGracePeriod,
+		StuckItemThreshold:         defaultStuckItemThreshold,
+		Tracer:                     otel.Tracer(TracerName),
 	}
 
 	klog.Info("Setting up event handlers")
-	// Set up an event handler for when Application resources change
-	applicationInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.enqueueApplication,
-		UpdateFunc: func(old, new interface{}) {
-			newApp := new.(*v1.Application)
-			oldApp := old.(*v1.Application)
-			if newApp.ResourceVersion == oldApp.ResourceVersion {
-				return
-			}
-			controller.enqueueApplication(new)
-		},
-	})
+	for _, scope := range scopes {
+		// Set up an event handler for when Application resources change
+		scope.Applications.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: controller.enqueueApplication,
+			UpdateFunc: func(old, new interface{}) {
+				newApp := new.(*v1.Application)
+				oldApp := old.(*v1.Application)
+				if newApp.ResourceVersion == oldApp.ResourceVersion {
+					return
+				}
+				controller.enqueueApplication(new)
+			},
+		})
 
-	deploymentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.handleObject,
-		UpdateFunc: func(old, new interface{}) {
-			newDepl := new.(*appsv1.Deployment)
-			oldDepl := old.(*appsv1.Deployment)
-			if newDepl.ResourceVersion == oldDepl.ResourceVersion {
-				return
-			}
-			controller.handleObject(new)
-		},
-		DeleteFunc: controller.handleObject,
-	})
+		scope.Deployments.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: controller.handleObject,
+			UpdateFunc: func(old, new interface{}) {
+				newDepl := new.(*appsv1.Deployment)
+				oldDepl := old.(*appsv1.Deployment)
+				if newDepl.ResourceVersion == oldDepl.ResourceVersion {
+					return
+				}
+				controller.handleObject(new)
+			},
+			DeleteFunc: controller.handleObject,
+		})
+	}
 
 	return controller
 }
 
-func (c *Controller) Run(workers int, stopCh <-chan struct{}) error {
+// Run starts workers reconciling applications until ctx is done. It then
+// stops taking new keys and waits for the in-flight reconciles to finish
+// within ShutdownGracePeriod, after which they are cancelled.
+func (c *Controller) Run(ctx context.Context, workers int) error {
 	defer utilruntime.HandleCrash()
 	defer c.Workqueue.ShutDown()
 
@@ -112,35 +301,87 @@ func (c *Controller) Run(workers int, stopCh <-chan struct{}) error {
 
 	// Wait for the caches to be synced before starting workers
 	klog.Info("Waiting for informer caches to sync")
-	if ok := cache.WaitForCacheSync(stopCh, c.DeploymentsSynced, c.ApplicationsSynced); !ok {
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.cacheSyncs()...); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
+	atomic.StoreInt32(&c.workers.started, 1)
+
+	// Reconciles are not cancelled with ctx so that a shutdown does not
+	// interrupt their writes halfway.
+	workCtx, cancelWork := context.WithCancel(context.Background())
+	defer cancelWork()
 
 	klog.Info("Starting workers")
+	var wg sync.WaitGroup
 	for i := 0; i < workers; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.runWorker(workCtx)
+		}()
 	}
 
 	klog.Info("Started workers")
-	<-stopCh
+	<-ctx.Done()
 	klog.Info("Shutting down workers")
+	c.Workqueue.ShutDown()
+
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		klog.Info("Workers drained")
+	case <-c.Clock.After(c.ShutdownGracePeriod):
+		klog.Warningf("In-flight reconciles did not finish within %s, cancelling them", c.ShutdownGracePeriod)
+		cancelWork()
+		<-drained
+	}
 
 	return nil
 }
 
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+// cacheSyncs returns whether each informer cache read by the controller is
+// synced.
+func (c *Controller) cacheSyncs() []cache.InformerSynced {
+	synced := []cache.InformerSynced{c.DeploymentsSynced, c.PodsSynced, c.ServicesSynced, c.ApplicationsSynced, c.ApplicationRevisionsSynced}
+	if c.PoliciesSynced != nil {
+		synced = append(synced, c.PoliciesSynced)
+	}
+	if c.QuotasSynced != nil {
+		synced = append(synced, c.QuotasSynced)
+	}
+	if c.TemplatesSynced != nil {
+		synced = append(synced, c.TemplatesSynced)
+	}
+	if c.ImagesSynced != nil {
+		synced = append(synced, c.ImagesSynced)
+	}
+	return synced
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	atomic.AddInt32(&c.workers.liveWorkers, 1)
+	defer atomic.AddInt32(&c.workers.liveWorkers, -1)
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the Workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.Workqueue.Get()
 
 	if shutdown {
 		return false
 	}
+	if c.Workqueue.ShuttingDown() {
+		// Keys still queued are left to the next leader.
+		c.Workqueue.Done(obj)
+		return false
+	}
 
 	err := func(obj interface{}) error {
 		defer c.Workqueue.Done(obj)
@@ -151,13 +392,20 @@ func (c *Controller) processNextWorkItem() bool {
 			utilruntime.HandleError(fmt.Errorf("expected string in Workqueue but got %#v", obj))
 			return nil
 		}
+		c.workers.startProcessing(key, c.Clock.Now())
+		defer c.workers.doneProcessing(key)
+		ctx, cancel := context.WithTimeout(ctx, c.ReconcileTimeout)
+		defer cancel()
 		// Run the syncHandler, passing it the namespace/name string of the
 		// Application resource to be synced.
-		if err := c.SyncHandler(key); err != nil {
+		start := c.Clock.Now()
+		if err := c.SyncHandler(ctx, key); err != nil {
+			observeReconcile(metrics.ResultError, c.Clock.Since(start))
 			// Put the item back on the Workqueue to handle any transient errors.
 			c.Workqueue.AddRateLimited(key)
 			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
 		}
+		observeReconcile(metrics.ResultSuccess, c.Clock.Since(start))
 		// Finally, if no error occurs we Forget this item so it does not
 		// get queued again until another change happens.
 		c.Workqueue.Forget(obj)
@@ -173,6 +421,14 @@ func (c *Controller) processNextWorkItem() bool {
 	return true
 }
 
+func observeReconcile(result string, duration time.Duration) {
+	metrics.ReconcileTotal.WithLabelValues(result).Inc()
+	metrics.ReconcileDuration.WithLabelValues(result).Observe(duration.Seconds())
+	if result == metrics.ResultError {
+		metrics.ReconcileErrors.Inc()
+	}
+}
+
 func (c *Controller) enqueueApplication(obj interface{}) {
 	var key string
 	var err error
@@ -1,23 +1,60 @@
 package controller_test
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"github.com/artifakt-io/demo-controller/internal/controller"
+	"github.com/artifakt-io/demo-controller/internal/images"
+	"github.com/artifakt-io/demo-controller/internal/notification"
 	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
 	informers "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"io/ioutil"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"log"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
 
 	"github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/fake"
 )
@@ -25,6 +62,7 @@ import (
 var (
 	alwaysReady        = func() bool { return true }
 	noResyncPeriodFunc = func() time.Duration { return 0 }
+	fakeNow            = time.Date(2021, time.October, 14, 9, 0, 0, 0, time.UTC)
 )
 
 type fixture struct {
@@ -34,7 +72,27 @@ type fixture struct {
 	kubeclient *k8sfake.Clientset
 	// Objects to put in the store.
 	applicationLister []*v1.Application
+	revisionLister    []*v1.ApplicationRevision
 	deploymentLister  []*apps.Deployment
+	podLister         []*corev1.Pod
+	serviceLister     []*corev1.Service
+	secretLister      []*corev1.Secret
+	policyLister      []*v1.ApplicationPolicy
+	quotaLister       []*v1.ApplicationQuota
+	// otherApplications are only counted by the quotas, as the applications
+	// of another controller sharing the namespace.
+	otherApplications []*v1.Application
+	// templates watches the templates of templateLister and
+	// clusterTemplateLister.
+	templates             bool
+	templateLister        []*v1.ApplicationTemplate
+	clusterTemplateLister []*v1.ClusterApplicationTemplate
+	// registry reads the images from their registry, pinImageDigests pins
+	// them to their digest.
+	registry        bool
+	pinImageDigests bool
+	// pushes are handled by the controller before the sync.
+	pushes []notification.Push
 	// Actions expected to happen on the client.
 	kubeactions []core.Action
 	actions     []core.Action
@@ -76,20 +134,86 @@ func (f *fixture) newController() (*controller.Controller, informers.SharedInfor
 		f.kubeclient,
 		f.client,
 		k8sI.Apps().V1().Deployments(),
-		i.Cloudest().V1().Applications())
+		k8sI.Core().V1().Pods(),
+		k8sI.Core().V1().Services(),
+		i.Cloudest().V1().Applications(),
+		i.Cloudest().V1().ApplicationRevisions())
 
 	c.ApplicationsSynced = alwaysReady
+	c.ApplicationRevisionsSynced = alwaysReady
 	c.DeploymentsSynced = alwaysReady
+	c.PodsSynced = alwaysReady
+	c.ServicesSynced = alwaysReady
 	c.Recorder = &record.FakeRecorder{}
+	c.Clock = testingclock.NewFakeClock(fakeNow)
+
+	// The applications are indexed by template once added.
+	if f.templates {
+		c.WatchTemplates(i.Cloudest().V1().ClusterApplicationTemplates(), controller.Informers{
+			Applications:         i.Cloudest().V1().Applications(),
+			ApplicationTemplates: i.Cloudest().V1().ApplicationTemplates(),
+		})
+		c.TemplatesSynced = alwaysReady
+		for _, t := range f.templateLister {
+			_ = i.Cloudest().V1().ApplicationTemplates().Informer().GetIndexer().Add(t)
+		}
+		for _, t := range f.clusterTemplateLister {
+			_ = i.Cloudest().V1().ClusterApplicationTemplates().Informer().GetIndexer().Add(t)
+		}
+	}
 
 	for _, a := range f.applicationLister {
 		_ = i.Cloudest().V1().Applications().Informer().GetIndexer().Add(a)
 	}
 
+	for _, r := range f.revisionLister {
+		_ = i.Cloudest().V1().ApplicationRevisions().Informer().GetIndexer().Add(r)
+	}
+
 	for _, d := range f.deploymentLister {
 		_ = k8sI.Apps().V1().Deployments().Informer().GetIndexer().Add(d)
 	}
 
+	for _, p := range f.podLister {
+		_ = k8sI.Core().V1().Pods().Informer().GetIndexer().Add(p)
+	}
+
+	for _, s := range f.serviceLister {
+		_ = k8sI.Core().V1().Services().Informer().GetIndexer().Add(s)
+	}
+
+	if f.registry {
+		c.WatchImages(controller.Informers{Secrets: k8sI.Core().V1().Secrets()})
+		c.ImagesSynced = alwaysReady
+		c.PinImageDigests = f.pinImageDigests
+		for _, s := range f.secretLister {
+			_ = k8sI.Core().V1().Secrets().Informer().GetIndexer().Add(s)
+		}
+	}
+
+	if len(f.policyLister) > 0 {
+		c.WatchPolicies(i.Cloudest().V1().ApplicationPolicies())
+		c.PoliciesSynced = alwaysReady
+		for _, p := range f.policyLister {
+			_ = i.Cloudest().V1().ApplicationPolicies().Informer().GetIndexer().Add(p)
+		}
+	}
+
+	if len(f.quotaLister) > 0 {
+		unfiltered := informers.NewSharedInformerFactory(f.client, noResyncPeriodFunc())
+		c.WatchQuotas(controller.Informers{
+			ApplicationQuotas: i.Cloudest().V1().ApplicationQuotas(),
+			QuotaApplications: unfiltered.Cloudest().V1().Applications(),
+		})
+		c.QuotasSynced = alwaysReady
+		for _, a := range append(f.applicationLister, f.otherApplications...) {
+			_ = unfiltered.Cloudest().V1().Applications().Informer().GetIndexer().Add(a)
+		}
+		for _, q := range f.quotaLister {
+			_ = i.Cloudest().V1().ApplicationQuotas().Informer().GetIndexer().Add(q)
+		}
+	}
+
 	return c, i, k8sI
 }
 
@@ -110,7 +234,11 @@ func (f *fixture) runController(appName string, startInformers bool, expectError
 		k8sI.Start(stopCh)
 	}
 
-	err := c.SyncHandler(appName)
+	for _, push := range f.pushes {
+		c.HandlePush(push)
+	}
+
+	err := c.SyncHandler(context.Background(), appName)
 	if !expectError && err != nil {
 		f.t.Errorf("error syncing application: %v", err)
 	} else if expectError && err == nil {
@@ -189,6 +317,18 @@ func checkAction(expected, actual core.Action, t *testing.T) {
 			t.Errorf("Action %s %s has wrong patch\nDiff:\n %s",
 				a.GetVerb(), a.GetResource().Resource, diff.ObjectGoPrintSideBySide(expPatch, patch))
 		}
+	case core.GetActionImpl:
+		e, _ := expected.(core.GetActionImpl)
+		if e.GetName() != a.GetName() {
+			t.Errorf("Action %s %s has wrong name: expected %s, got %s",
+				a.GetVerb(), a.GetResource().Resource, e.GetName(), a.GetName())
+		}
+	case core.DeleteActionImpl:
+		e, _ := expected.(core.DeleteActionImpl)
+		if e.GetName() != a.GetName() {
+			t.Errorf("Action %s %s has wrong name: expected %s, got %s",
+				a.GetVerb(), a.GetResource().Resource, e.GetName(), a.GetName())
+		}
 	default:
 		t.Errorf("Uncaptured Action %s %s, you should explicitly add a case to capture it",
 			actual.GetVerb(), actual.GetResource().Resource)
@@ -201,8 +341,24 @@ func filterInformerActions(actions []core.Action) []core.Action {
 		if len(action.GetNamespace()) == 0 &&
 			(action.Matches("list", "applications") ||
 				action.Matches("watch", "applications") ||
+				action.Matches("list", "applicationrevisions") ||
+				action.Matches("watch", "applicationrevisions") ||
 				action.Matches("list", "deployments") ||
-				action.Matches("watch", "deployments")) {
+				action.Matches("watch", "deployments") ||
+				action.Matches("list", "pods") ||
+				action.Matches("watch", "pods") ||
+				action.Matches("list", "services") ||
+				action.Matches("watch", "services") ||
+				action.Matches("list", "secrets") ||
+				action.Matches("watch", "secrets") ||
+				action.Matches("list", "applicationpolicies") ||
+				action.Matches("watch", "applicationpolicies") ||
+				action.Matches("list", "applicationquotas") ||
+				action.Matches("watch", "applicationquotas") ||
+				action.Matches("list", "applicationtemplates") ||
+				action.Matches("watch", "applicationtemplates") ||
+				action.Matches("list", "clusterapplicationtemplates") ||
+				action.Matches("watch", "clusterapplicationtemplates")) {
 			continue
 		}
 		ret = append(ret, action)
@@ -219,12 +375,46 @@ func (f *fixture) expectUpdateDeploymentAction(d *apps.Deployment) {
 	f.kubeactions = append(f.kubeactions, core.NewUpdateAction(schema.GroupVersionResource{Resource: "deployments"}, d.Namespace, d))
 }
 
+func (f *fixture) expectCreateServiceAction(s *corev1.Service) {
+	f.kubeactions = append(f.kubeactions, core.NewCreateAction(schema.GroupVersionResource{Resource: "services"}, s.Namespace, s))
+}
+
+func (f *fixture) expectUpdateServiceAction(s *corev1.Service) {
+	f.kubeactions = append(f.kubeactions, core.NewUpdateAction(schema.GroupVersionResource{Resource: "services"}, s.Namespace, s))
+}
+
+func (f *fixture) expectDeleteDeploymentAction(d *apps.Deployment) {
+	f.kubeactions = append(f.kubeactions, core.NewDeleteAction(schema.GroupVersionResource{Resource: "deployments"}, d.Namespace, d.Name))
+}
+
+func (f *fixture) expectUpdateApplicationAction(app *v1.Application) {
+	f.actions = append(f.actions, core.NewUpdateAction(v1.SchemeGroupVersion.WithResource("applications"), app.Namespace, app))
+}
+
+func (f *fixture) expectCreateRevisionAction(r *v1.ApplicationRevision) {
+	f.actions = append(f.actions, core.NewCreateAction(v1.SchemeGroupVersion.WithResource("applicationrevisions"), r.Namespace, r))
+}
+
+func (f *fixture) expectGetRevisionAction(r *v1.ApplicationRevision) {
+	f.actions = append(f.actions, core.NewGetAction(v1.SchemeGroupVersion.WithResource("applicationrevisions"), r.Namespace, r.Name))
+}
+
+func (f *fixture) expectDeleteRevisionAction(r *v1.ApplicationRevision) {
+	f.actions = append(f.actions, core.NewDeleteAction(v1.SchemeGroupVersion.WithResource("applicationrevisions"), r.Namespace, r.Name))
+}
+
 func (f *fixture) expectUpdateApplicationStatusAction(app *v1.Application) {
 	action := core.NewUpdateAction(v1.SchemeGroupVersion.WithResource("applications"), app.Namespace, app)
 	action.Subresource = "status"
 	f.actions = append(f.actions, action)
 }
 
+func (f *fixture) expectUpdateQuotaStatusAction(q *v1.ApplicationQuota) {
+	action := core.NewUpdateAction(v1.SchemeGroupVersion.WithResource("applicationquotas"), q.Namespace, q)
+	action.Subresource = "status"
+	f.actions = append(f.actions, action)
+}
+
 func getKey(app *v1.Application, t *testing.T) string {
 	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(app)
 	if err != nil {
@@ -236,6 +426,47 @@ func getKey(app *v1.Application, t *testing.T) string {
 
 func int32Ptr(i int32) *int32 { return &i }
 
+func resourcePtr(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+// completeRollout sets the status of d as the deployment controller does once
+// every replica runs the current pod template.
+func completeRollout(d *apps.Deployment) {
+	d.Status.ObservedGeneration = d.Generation
+	d.Status.Replicas = *d.Spec.Replicas
+	d.Status.UpdatedReplicas = *d.Spec.Replicas
+	d.Status.ReadyReplicas = *d.Spec.Replicas
+	d.Status.AvailableReplicas = *d.Spec.Replicas
+}
+
+// rolloutConditions returns the Progressing, Ready and Failed conditions set
+// for a rollout in the given state.
+func rolloutConditions(app *v1.Application, state, message string) []metav1.Condition {
+	statuses := map[string]metav1.ConditionStatus{
+		"RolloutInProgress":        metav1.ConditionFalse,
+		"RolloutComplete":          metav1.ConditionFalse,
+		"ProgressDeadlineExceeded": metav1.ConditionFalse,
+	}
+	statuses[state] = metav1.ConditionTrue
+	condition := func(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{
+			Type:               conditionType,
+			Status:             status,
+			ObservedGeneration: app.Generation,
+			LastTransitionTime: metav1.NewTime(fakeNow),
+			Reason:             state,
+			Message:            message,
+		}
+	}
+	return []metav1.Condition{
+		condition(v1.ApplicationProgressing, statuses["RolloutInProgress"]),
+		condition(v1.ApplicationReady, statuses["RolloutComplete"]),
+		condition(v1.ApplicationFailed, statuses["ProgressDeadlineExceeded"]),
+	}
+}
+
 // Real test start from here
 
 func TestCreatesDeployment(t *testing.T) {
@@ -251,6 +482,7 @@ func TestCreatesDeployment(t *testing.T) {
 	expectApp := app.DeepCopy()
 	expectApp.Status.DeploymentRefNamespace = expDeployment.Namespace
 	expectApp.Status.DeploymentRefName = expDeployment.Name
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 1 new replicas have been updated")
 	f.expectUpdateApplicationStatusAction(expectApp)
 
 	f.run(getKey(app, t))
@@ -260,11 +492,20 @@ func TestDoNothing(t *testing.T) {
 	f := newFixture(t)
 	app := newApplication("test", "nginx", int32Ptr(1))
 	deployment := controller.NewDeployment(app)
+	completeRollout(deployment)
 	app.Status.DeploymentRefNamespace = deployment.Namespace
 	app.Status.DeploymentRefName = deployment.Name
+	app.Status.UpdatedReplicas = 1
+	app.Status.ReadyReplicas = 1
+	app.Status.Conditions = rolloutConditions(app, "RolloutComplete", `Deployment "test" successfully rolled out`)
+	app.Status.CurrentRevision = 1
+	app.Status.LastReadyImage = "nginx"
+	revision := controller.NewApplicationRevision(app, 1)
 
 	f.applicationLister = append(f.applicationLister, app)
 	f.objects = append(f.objects, app)
+	f.revisionLister = append(f.revisionLister, revision)
+	f.objects = append(f.objects, revision)
 	f.deploymentLister = append(f.deploymentLister, deployment)
 	f.kubeobjects = append(f.kubeobjects, deployment)
 
@@ -284,6 +525,10 @@ func TestUpdateDeploymentReplicas(t *testing.T) {
 	deployment := controller.NewDeployment(app)
 	deployment.Spec.Replicas = int32Ptr(2)
 
+	expectApp := app.DeepCopy()
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 1 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
 	f.applicationLister = append(f.applicationLister, app)
 	f.objects = append(f.objects, app)
 	f.deploymentLister = append(f.deploymentLister, deployment)
@@ -305,10 +550,2064 @@ func TestUpdateDeploymentImageName(t *testing.T) {
 	deployment := controller.NewDeployment(app)
 	deployment.Spec.Template.Spec.Containers[0].Image = "mysql"
 
+	expectApp := app.DeepCopy()
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 1 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	f.run(getKey(app, t))
+}
+
+func TestSuspendedApplicationIsNotReconciled(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", int32Ptr(1))
+	app.Spec.Suspend = true
+
+	deployment := controller.NewDeployment(app)
+	deployment.Spec.Template.Spec.Containers[0].Image = "mysql"
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Conditions = []metav1.Condition{{
+		Type:               v1.ApplicationSuspended,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "Suspended",
+		Message:            controller.MessageSuspended,
+	}}
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestPausedApplicationScalesToZero(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", int32Ptr(3))
+	app.Annotations = map[string]string{v1.PausedAnnotation: "true"}
+	app.Spec.ScaleToZeroOnSuspend = true
+
+	deployment := controller.NewDeployment(app)
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	expDeployment := deployment.DeepCopy()
+	expDeployment.Annotations = map[string]string{controller.ReplicasBeforeSuspendAnnotation: "3"}
+	expDeployment.Spec.Replicas = int32Ptr(0)
+	f.expectUpdateDeploymentAction(expDeployment)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Conditions = []metav1.Condition{{
+		Type:               v1.ApplicationSuspended,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "Paused",
+		Message:            controller.MessageSuspendedScale,
+	}}
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestResumeRestoresReplicas(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", nil)
+
+	deployment := controller.NewDeployment(app)
+	deployment.Annotations = map[string]string{controller.ReplicasBeforeSuspendAnnotation: "3"}
+	deployment.Spec.Replicas = int32Ptr(0)
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+	app.Status.Conditions = []metav1.Condition{{
+		Type:               v1.ApplicationSuspended,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "Suspended",
+		Message:            controller.MessageSuspendedScale,
+	}}
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	expDeployment := deployment.DeepCopy()
+	expDeployment.Annotations = map[string]string{}
+	expDeployment.Spec.Replicas = int32Ptr(3)
+	f.expectUpdateDeploymentAction(expDeployment)
+
+	expectApp := app.DeepCopy()
+	expectApp.Spec.Replicas = int32Ptr(3)
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 3 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestUnsetReplicasKeepDeploymentReplicas(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", nil)
+
+	deployment := controller.NewDeployment(app)
+	deployment.Spec.Replicas = int32Ptr(3)
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	// The replica count is not defaulted back to 1.
+	expectApp := app.DeepCopy()
+	expectApp.Spec.Replicas = int32Ptr(3)
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 3 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestRolloutCompleteSetsReady(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", int32Ptr(2))
+	app.Generation = 2
+	deployment := controller.NewDeployment(app)
+	deployment.Generation = 3
+	completeRollout(deployment)
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+	app.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 2 new replicas have been updated")
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	f.expectCreateRevisionAction(controller.NewApplicationRevision(app, 1))
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.ObservedGeneration = 2
+	expectApp.Status.UpdatedReplicas = 2
+	expectApp.Status.ReadyReplicas = 2
+	expectApp.Status.CurrentRevision = 1
+	expectApp.Status.LastReadyImage = "nginx"
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutComplete", `Deployment "test" successfully rolled out`)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestRolloutStalledSetsFailed(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:broken", int32Ptr(2))
+	deployment := controller.NewDeployment(app)
+	deployment.Status.Replicas = 3
+	deployment.Status.UpdatedReplicas = 1
+	deployment.Status.ReadyReplicas = 2
+	deployment.Status.Conditions = []apps.DeploymentCondition{{
+		Type:   apps.DeploymentProgressing,
+		Status: "False",
+		Reason: "ProgressDeadlineExceeded",
+	}}
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.UpdatedReplicas = 1
+	expectApp.Status.ReadyReplicas = 2
+	expectApp.Status.Conditions = rolloutConditions(app, "ProgressDeadlineExceeded", `Deployment "test" exceeded its progress deadline`)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestRevisionHistoryIsPruned(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:1.21", int32Ptr(1))
+	app.Spec.RevisionHistoryLimit = int32Ptr(2)
+	deployment := controller.NewDeployment(app)
+	completeRollout(deployment)
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+	app.Status.UpdatedReplicas = 1
+	app.Status.ReadyReplicas = 1
+	app.Status.CurrentRevision = 2
+	app.Status.Conditions = rolloutConditions(app, "RolloutComplete", `Deployment "test" successfully rolled out`)
+
+	for i, image := range []string{"nginx:1.19", "nginx:1.20"} {
+		old := app.DeepCopy()
+		old.Spec.ImageName = image
+		revision := controller.NewApplicationRevision(old, int64(i+1))
+		f.revisionLister = append(f.revisionLister, revision)
+		f.objects = append(f.objects, revision)
+	}
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	f.expectCreateRevisionAction(controller.NewApplicationRevision(app, 3))
+	f.expectDeleteRevisionAction(f.revisionLister[0])
+	expectApp := app.DeepCopy()
+	expectApp.Status.CurrentRevision = 3
+	expectApp.Status.LastReadyImage = "nginx:1.21"
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestRevisionMissedByListerIsReused(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", int32Ptr(2))
+	deployment := controller.NewDeployment(app)
+	completeRollout(deployment)
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+	app.Status.UpdatedReplicas = 2
+	app.Status.ReadyReplicas = 2
+	app.Status.Conditions = rolloutConditions(app, "RolloutComplete", `Deployment "test" successfully rolled out`)
+
+	// The revision is recorded but not in the lister yet.
+	revision := controller.NewApplicationRevision(app, 1)
+	f.objects = append(f.objects, revision)
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	f.expectCreateRevisionAction(revision)
+	f.expectGetRevisionAction(revision)
+	expectApp := app.DeepCopy()
+	expectApp.Status.CurrentRevision = 1
+	expectApp.Status.LastReadyImage = "nginx"
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestRollbackRestoresRevisionSpec(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:broken", int32Ptr(3))
+	app.Spec.RollbackTo = func(i int64) *int64 { return &i }(1)
+
+	old := newApplication("test", "nginx:1.21", int32Ptr(2))
+	revision := controller.NewApplicationRevision(old, 1)
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.revisionLister = append(f.revisionLister, revision)
+	f.objects = append(f.objects, revision)
+
+	expectApp := app.DeepCopy()
+	expectApp.Spec.ImageName = "nginx:1.21"
+	expectApp.Spec.Replicas = int32Ptr(2)
+	expectApp.Spec.RollbackTo = nil
+	f.expectUpdateApplicationAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestAutoRollbackOnProgressDeadline(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:broken", int32Ptr(2))
+	app.Generation = 4
+	deployment := controller.NewDeployment(app)
+	deployment.Status.Conditions = []apps.DeploymentCondition{{
+		Type:   apps.DeploymentProgressing,
+		Status: "False",
+		Reason: "ProgressDeadlineExceeded",
+	}}
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+	app.Status.LastReadyImage = "nginx:1.21"
+
 	f.applicationLister = append(f.applicationLister, app)
 	f.objects = append(f.objects, app)
 	f.deploymentLister = append(f.deploymentLister, deployment)
 	f.kubeobjects = append(f.kubeobjects, deployment)
 
+	expDeployment := controller.NewDeployment(app)
+	expDeployment.Spec.Template.Spec.Containers[0].Image = "nginx:1.21"
+	f.expectUpdateDeploymentAction(expDeployment)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.ObservedGeneration = 4
+	expectApp.Status.Conditions = append(
+		rolloutConditions(app, "ProgressDeadlineExceeded", `Deployment "test" exceeded its progress deadline`),
+		metav1.Condition{
+			Type:               v1.ApplicationRolledBack,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: 4,
+			LastTransitionTime: metav1.NewTime(fakeNow),
+			Reason:             "ProgressDeadlineExceeded",
+			Message:            `Rollout of image "nginx:broken" failed (ProgressDeadlineExceeded), rolled back to image "nginx:1.21"`,
+		})
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestAutoRollbackOnCrashLoop(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:broken", int32Ptr(1))
+	deployment := controller.NewDeployment(app)
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+	app.Status.LastReadyImage = "nginx:1.21"
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-5d4f8c7b9-x2x4z",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    deployment.Spec.Template.Labels,
+		},
+		Spec: deployment.Spec.Template.Spec,
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "main",
+				RestartCount: 5,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+			}},
+		},
+	}
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+	f.podLister = append(f.podLister, pod)
+
+	expDeployment := controller.NewDeployment(app)
+	expDeployment.Spec.Template.Spec.Containers[0].Image = "nginx:1.21"
+	f.expectUpdateDeploymentAction(expDeployment)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Conditions = append(
+		rolloutConditions(app, "RolloutInProgress", "0 out of 1 new replicas have been updated"),
+		metav1.Condition{
+			Type:               v1.ApplicationRolledBack,
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(fakeNow),
+			Reason:             "CrashLoopBackOff",
+			Message:            `Rollout of image "nginx:broken" failed (CrashLoopBackOff), rolled back to image "nginx:1.21"`,
+		})
+	f.expectUpdateApplicationStatusAction(expectApp)
+
 	f.run(getKey(app, t))
 }
+
+func TestRolledBackImageIsNotRetried(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:broken", int32Ptr(1))
+	app.Generation = 4
+	deployment := controller.NewDeployment(app)
+	deployment.Spec.Template.Spec.Containers[0].Image = "nginx:1.21"
+	completeRollout(deployment)
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+	app.Status.ObservedGeneration = 4
+	app.Status.UpdatedReplicas = 1
+	app.Status.ReadyReplicas = 1
+	app.Status.LastReadyImage = "nginx:1.21"
+	app.Status.Conditions = append(
+		rolloutConditions(app, "RolloutComplete", `Deployment "test" successfully rolled out`),
+		metav1.Condition{
+			Type:               v1.ApplicationRolledBack,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: 4,
+			LastTransitionTime: metav1.NewTime(fakeNow),
+			Reason:             "ProgressDeadlineExceeded",
+			Message:            `Rollout of image "nginx:broken" failed (ProgressDeadlineExceeded), rolled back to image "nginx:1.21"`,
+		})
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	f.run(getKey(app, t))
+}
+
+// newCanaryApplication returns an application rolling out nginx:2 over a
+// stable deployment running nginx:1 through canary steps.
+func newCanaryApplication() (*v1.Application, *apps.Deployment) {
+	app := newApplication("test", "nginx:2", int32Ptr(4))
+	app.Spec.Rollout = &v1.RolloutSpec{
+		Canary: &v1.CanaryStrategy{
+			Steps: []v1.CanaryStep{
+				{SetWeight: int32Ptr(25)},
+				{Pause: &v1.RolloutPause{}},
+				{SetWeight: int32Ptr(50)},
+				{Pause: &v1.RolloutPause{Duration: &metav1.Duration{Duration: time.Minute}}},
+			},
+		},
+	}
+	stable := controller.NewDeployment(app)
+	stable.Spec.Template.Spec.Containers[0].Image = "nginx:1"
+	completeRollout(stable)
+	app.Status.DeploymentRefNamespace = stable.Namespace
+	app.Status.DeploymentRefName = stable.Name
+	app.Status.LastReadyImage = "nginx:1"
+	return app, stable
+}
+
+func canaryConditions(app *v1.Application, reason, message string) []metav1.Condition {
+	conditions := rolloutConditions(app, "RolloutInProgress", message)
+	for i := range conditions {
+		conditions[i].Reason = reason
+	}
+	return conditions
+}
+
+func TestCanaryStartsFirstStep(t *testing.T) {
+	f := newFixture(t)
+	app, stable := newCanaryApplication()
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, stable)
+	f.kubeobjects = append(f.kubeobjects, stable)
+
+	f.expectCreateDeploymentAction(controller.NewCanaryDeployment(app, "nginx:2", 1))
+	expStable := controller.NewDeployment(app)
+	expStable.Spec.Template.Spec.Containers[0].Image = "nginx:1"
+	expStable.Spec.Replicas = int32Ptr(3)
+	f.expectUpdateDeploymentAction(expStable)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Canary = &v1.CanaryStatus{
+		Phase:                v1.CanaryProgressing,
+		StableImage:          "nginx:1",
+		CanaryImage:          "nginx:2",
+		CurrentStepStartTime: &metav1.Time{Time: fakeNow},
+		Weight:               25,
+		StableReplicas:       3,
+		CanaryReplicas:       1,
+	}
+	expectApp.Status.Conditions = canaryConditions(app, "CanaryInProgress", `Canary step 1 of 4: 25% of the replicas run image "nginx:2"`)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestCanaryManualPauseIsPromoted(t *testing.T) {
+	f := newFixture(t)
+	app, stable := newCanaryApplication()
+	app.Spec.Rollout.Promote = true
+	stable.Spec.Replicas = int32Ptr(3)
+	completeRollout(stable)
+	canary := controller.NewCanaryDeployment(app, "nginx:2", 1)
+	completeRollout(canary)
+	app.Status.Canary = &v1.CanaryStatus{
+		Phase:                v1.CanaryPaused,
+		StableImage:          "nginx:1",
+		CanaryImage:          "nginx:2",
+		CurrentStepIndex:     1,
+		CurrentStepStartTime: &metav1.Time{Time: fakeNow.Add(-time.Hour)},
+		Weight:               25,
+		StableReplicas:       3,
+		CanaryReplicas:       1,
+	}
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, stable, canary)
+	f.kubeobjects = append(f.kubeobjects, stable, canary)
+
+	f.expectUpdateDeploymentAction(controller.NewCanaryDeployment(app, "nginx:2", 2))
+	expStable := controller.NewDeployment(app)
+	expStable.Spec.Template.Spec.Containers[0].Image = "nginx:1"
+	expStable.Spec.Replicas = int32Ptr(2)
+	f.expectUpdateDeploymentAction(expStable)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Canary.Phase = v1.CanaryProgressing
+	expectApp.Status.Canary.CurrentStepIndex = 2
+	expectApp.Status.Canary.CurrentStepStartTime = &metav1.Time{Time: fakeNow}
+	expectApp.Status.Canary.Weight = 50
+	expectApp.Status.Canary.StableReplicas = 2
+	expectApp.Status.Canary.CanaryReplicas = 2
+	expectApp.Status.Conditions = canaryConditions(app, "CanaryInProgress", `Canary step 3 of 4: 50% of the replicas run image "nginx:2"`)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	promotedApp := expectApp.DeepCopy()
+	promotedApp.Spec.Rollout.Promote = false
+	f.expectUpdateApplicationAction(promotedApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestCanaryIsPromotedAfterLastStep(t *testing.T) {
+	f := newFixture(t)
+	app, stable := newCanaryApplication()
+	stable.Spec.Replicas = int32Ptr(2)
+	completeRollout(stable)
+	canary := controller.NewCanaryDeployment(app, "nginx:2", 2)
+	completeRollout(canary)
+	app.Status.Canary = &v1.CanaryStatus{
+		Phase:                v1.CanaryProgressing,
+		StableImage:          "nginx:1",
+		CanaryImage:          "nginx:2",
+		CurrentStepIndex:     3,
+		CurrentStepStartTime: &metav1.Time{Time: fakeNow.Add(-time.Minute)},
+		Weight:               50,
+		StableReplicas:       2,
+		CanaryReplicas:       2,
+	}
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, stable, canary)
+	f.kubeobjects = append(f.kubeobjects, stable, canary)
+
+	f.expectUpdateDeploymentAction(controller.NewDeployment(app))
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Canary.Phase = v1.CanaryPromoted
+	expectApp.Status.Canary.CurrentStepIndex = 4
+	expectApp.Status.Canary.CurrentStepStartTime = &metav1.Time{Time: fakeNow}
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 4 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestCanaryIsAbortedOnFailure(t *testing.T) {
+	f := newFixture(t)
+	app, stable := newCanaryApplication()
+	stable.Spec.Replicas = int32Ptr(3)
+	completeRollout(stable)
+	canary := controller.NewCanaryDeployment(app, "nginx:2", 1)
+	canary.Status.Conditions = []apps.DeploymentCondition{{
+		Type:   apps.DeploymentProgressing,
+		Status: "False",
+		Reason: "ProgressDeadlineExceeded",
+	}}
+	app.Status.Canary = &v1.CanaryStatus{
+		Phase:                v1.CanaryProgressing,
+		StableImage:          "nginx:1",
+		CanaryImage:          "nginx:2",
+		CurrentStepStartTime: &metav1.Time{Time: fakeNow},
+		Weight:               25,
+		StableReplicas:       3,
+		CanaryReplicas:       1,
+	}
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, stable, canary)
+	f.kubeobjects = append(f.kubeobjects, stable, canary)
+
+	f.expectDeleteDeploymentAction(canary)
+	expStable := controller.NewDeployment(app)
+	expStable.Spec.Template.Spec.Containers[0].Image = "nginx:1"
+	f.expectUpdateDeploymentAction(expStable)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Canary.Phase = v1.CanaryAborted
+	expectApp.Status.Canary.Weight = 0
+	expectApp.Status.Canary.StableReplicas = 4
+	expectApp.Status.Canary.CanaryReplicas = 0
+	expectApp.Status.Conditions = append(
+		[]metav1.Condition{{
+			Type:               v1.ApplicationRolledBack,
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(fakeNow),
+			Reason:             "ProgressDeadlineExceeded",
+			Message:            `Rollout of image "nginx:2" failed (ProgressDeadlineExceeded), rolled back to image "nginx:1"`,
+		}},
+		rolloutConditions(app, "RolloutInProgress", "0 out of 4 new replicas have been updated")...)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+// newBlueGreenApplication returns an application rolling out nginx:2 over a
+// blue deployment running nginx:1 with the blue/green strategy.
+func newBlueGreenApplication() (*v1.Application, *apps.Deployment) {
+	app := newApplication("test", "nginx:2", int32Ptr(4))
+	app.Spec.Rollout = &v1.RolloutSpec{
+		BlueGreen: &v1.BlueGreenStrategy{
+			AutoPromotionDelay: &metav1.Duration{Duration: time.Minute},
+		},
+	}
+	active := controller.NewColorDeployment(app, "blue", "nginx:1", 4)
+	completeRollout(active)
+	app.Status.DeploymentRefNamespace = active.Namespace
+	app.Status.DeploymentRefName = active.Name
+	app.Status.LastReadyImage = "nginx:1"
+	return app, active
+}
+
+func TestBlueGreenStartsPreview(t *testing.T) {
+	f := newFixture(t)
+	app, active := newBlueGreenApplication()
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, active)
+	f.kubeobjects = append(f.kubeobjects, active)
+
+	f.expectCreateDeploymentAction(controller.NewColorDeployment(app, "green", "nginx:2", 4))
+	f.expectCreateServiceAction(controller.NewService(app, "test", "blue"))
+	f.expectCreateServiceAction(controller.NewService(app, "test-preview", "green"))
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.UpdatedReplicas = 4
+	expectApp.Status.ReadyReplicas = 4
+	expectApp.Status.BlueGreen = &v1.BlueGreenStatus{
+		Phase:        v1.BlueGreenPreviewing,
+		ActiveColor:  "blue",
+		PreviewColor: "green",
+		Blue:         &v1.ColorStatus{Image: "nginx:1", Replicas: 4, ReadyReplicas: 4},
+		Green:        &v1.ColorStatus{Image: "nginx:2", Replicas: 4},
+	}
+	expectApp.Status.Conditions = canaryConditions(app, "BlueGreenPreview", `Rolling out image "nginx:2" in the green deployment`)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestBlueGreenIsPromotedAfterDelay(t *testing.T) {
+	f := newFixture(t)
+	app, active := newBlueGreenApplication()
+	preview := controller.NewColorDeployment(app, "green", "nginx:2", 4)
+	completeRollout(preview)
+	app.Status.BlueGreen = &v1.BlueGreenStatus{
+		Phase:            v1.BlueGreenPreviewing,
+		ActiveColor:      "blue",
+		PreviewColor:     "green",
+		PreviewReadyTime: &metav1.Time{Time: fakeNow.Add(-time.Minute)},
+	}
+	services := []*corev1.Service{
+		controller.NewService(app, "test", "blue"),
+		controller.NewService(app, "test-preview", "green"),
+	}
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, active, preview)
+	f.kubeobjects = append(f.kubeobjects, active, preview)
+	f.serviceLister = append(f.serviceLister, services...)
+	f.kubeobjects = append(f.kubeobjects, services[0], services[1])
+
+	f.expectUpdateServiceAction(controller.NewService(app, "test", "green"))
+	f.expectCreateRevisionAction(controller.NewApplicationRevision(app, 1))
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.DeploymentRefName = "test-green"
+	expectApp.Status.UpdatedReplicas = 4
+	expectApp.Status.ReadyReplicas = 4
+	expectApp.Status.BlueGreen = &v1.BlueGreenStatus{
+		Phase:         v1.BlueGreenScalingDown,
+		ActiveColor:   "green",
+		ScaleDownTime: &metav1.Time{Time: fakeNow.Add(30 * time.Second)},
+		Blue:          &v1.ColorStatus{Image: "nginx:1", Replicas: 4, ReadyReplicas: 4},
+		Green:         &v1.ColorStatus{Image: "nginx:2", Replicas: 4, ReadyReplicas: 4},
+	}
+	expectApp.Status.LastReadyImage = "nginx:2"
+	expectApp.Status.CurrentRevision = 1
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutComplete", `Deployment "test-green" successfully rolled out`)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestBlueGreenScalesDownPreviousColor(t *testing.T) {
+	f := newFixture(t)
+	app, previous := newBlueGreenApplication()
+	active := controller.NewColorDeployment(app, "green", "nginx:2", 4)
+	completeRollout(active)
+	app.Status.DeploymentRefName = active.Name
+	app.Status.LastReadyImage = "nginx:2"
+	app.Status.CurrentRevision = 1
+	app.Status.BlueGreen = &v1.BlueGreenStatus{
+		Phase:         v1.BlueGreenScalingDown,
+		ActiveColor:   "green",
+		ScaleDownTime: &metav1.Time{Time: fakeNow},
+		Blue:          &v1.ColorStatus{Image: "nginx:1", Replicas: 4, ReadyReplicas: 4},
+		Green:         &v1.ColorStatus{Image: "nginx:2", Replicas: 4, ReadyReplicas: 4},
+	}
+	services := []*corev1.Service{
+		controller.NewService(app, "test", "green"),
+		controller.NewService(app, "test-preview", "green"),
+	}
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, previous, active)
+	f.kubeobjects = append(f.kubeobjects, previous, active)
+	revision := controller.NewApplicationRevision(app, 1)
+	f.revisionLister = append(f.revisionLister, revision)
+	f.objects = append(f.objects, revision)
+	f.serviceLister = append(f.serviceLister, services...)
+	f.kubeobjects = append(f.kubeobjects, services[0], services[1])
+
+	f.expectUpdateDeploymentAction(controller.NewColorDeployment(app, "blue", "nginx:1", 0))
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.UpdatedReplicas = 4
+	expectApp.Status.ReadyReplicas = 4
+	expectApp.Status.BlueGreen.Phase = v1.BlueGreenActive
+	expectApp.Status.BlueGreen.ScaleDownTime = nil
+	expectApp.Status.BlueGreen.Blue = &v1.ColorStatus{Image: "nginx:1"}
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutComplete", `Deployment "test-green" successfully rolled out`)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestBlueGreenFinishesScaleDownWithoutPreviousColor(t *testing.T) {
+	f := newFixture(t)
+	app, _ := newBlueGreenApplication()
+	active := controller.NewColorDeployment(app, "green", "nginx:2", 4)
+	completeRollout(active)
+	app.Status.DeploymentRefName = active.Name
+	app.Status.LastReadyImage = "nginx:2"
+	app.Status.CurrentRevision = 1
+	app.Status.BlueGreen = &v1.BlueGreenStatus{
+		Phase:         v1.BlueGreenScalingDown,
+		ActiveColor:   "green",
+		ScaleDownTime: &metav1.Time{Time: fakeNow.Add(time.Minute)},
+		Green:         &v1.ColorStatus{Image: "nginx:2", Replicas: 4, ReadyReplicas: 4},
+	}
+	services := []*corev1.Service{
+		controller.NewService(app, "test", "green"),
+		controller.NewService(app, "test-preview", "green"),
+	}
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, active)
+	f.kubeobjects = append(f.kubeobjects, active)
+	revision := controller.NewApplicationRevision(app, 1)
+	f.revisionLister = append(f.revisionLister, revision)
+	f.objects = append(f.objects, revision)
+	f.serviceLister = append(f.serviceLister, services...)
+	f.kubeobjects = append(f.kubeobjects, services[0], services[1])
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.UpdatedReplicas = 4
+	expectApp.Status.ReadyReplicas = 4
+	expectApp.Status.BlueGreen.Phase = v1.BlueGreenActive
+	expectApp.Status.BlueGreen.ScaleDownTime = nil
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutComplete", `Deployment "test-green" successfully rolled out`)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestBlueGreenDoesNotTakeOverService(t *testing.T) {
+	f := newFixture(t)
+	app, active := newBlueGreenApplication()
+	service := controller.NewService(app, "test", "blue")
+	service.OwnerReferences = nil
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, active)
+	f.kubeobjects = append(f.kubeobjects, active)
+	f.serviceLister = append(f.serviceLister, service)
+	f.kubeobjects = append(f.kubeobjects, service)
+
+	f.expectCreateDeploymentAction(controller.NewColorDeployment(app, "green", "nginx:2", 4))
+
+	f.runExpectError(getKey(app, t))
+}
+
+func TestBlueGreenAutoRollbackOnCrashLoop(t *testing.T) {
+	f := newFixture(t)
+	app, previous := newBlueGreenApplication()
+	previous.Spec.Replicas = int32Ptr(0)
+	active := controller.NewColorDeployment(app, "green", "nginx:2", 4)
+	app.Status.DeploymentRefName = active.Name
+	app.Status.BlueGreen = &v1.BlueGreenStatus{Phase: v1.BlueGreenActive, ActiveColor: "green"}
+	services := []*corev1.Service{
+		controller.NewService(app, "test", "green"),
+		controller.NewService(app, "test-preview", "green"),
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-green-5d4f8c7b9-x2x4z",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    active.Spec.Template.Labels,
+		},
+		Spec: active.Spec.Template.Spec,
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "main",
+				RestartCount: 5,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+			}},
+		},
+	}
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, previous, active)
+	f.kubeobjects = append(f.kubeobjects, previous, active)
+	f.serviceLister = append(f.serviceLister, services...)
+	f.kubeobjects = append(f.kubeobjects, services[0], services[1])
+	f.podLister = append(f.podLister, pod)
+
+	// The active color is rolled back, not the deployment of the default
+	// strategy.
+	f.expectUpdateDeploymentAction(controller.NewColorDeployment(app, "green", "nginx:1", 4))
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.BlueGreen.Green = &v1.ColorStatus{Image: "nginx:2", Replicas: 4}
+	expectApp.Status.BlueGreen.Blue = &v1.ColorStatus{Image: "nginx:1", ReadyReplicas: 4}
+	expectApp.Status.Conditions = append(
+		rolloutConditions(app, "RolloutInProgress", "0 out of 4 new replicas have been updated"),
+		metav1.Condition{
+			Type:               v1.ApplicationRolledBack,
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(fakeNow),
+			Reason:             "CrashLoopBackOff",
+			Message:            `Rollout of image "nginx:2" failed (CrashLoopBackOff), rolled back to image "nginx:1"`,
+		})
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestHealthDetectsStuckItem(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", int32Ptr(1))
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+
+	c, _, _ := f.newController()
+	c.StuckItemThreshold = time.Minute
+	started := make(chan struct{})
+	release := make(chan struct{})
+	f.kubeclient.PrependReactor("create", "deployments", func(action core.Action) (bool, runtime.Object, error) {
+		close(started)
+		<-release
+		return false, nil, nil
+	})
+
+	synced := false
+	c.DeploymentsSynced = func() bool { return synced }
+	if err := c.Ready(); err == nil {
+		t.Errorf("expected the controller not to be ready before its caches are synced")
+	}
+	// A standby waiting for the Lease is ready once its caches are synced.
+	synced = true
+	if err := c.Ready(); err != nil {
+		t.Errorf("expected the controller to be ready before Run, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = c.Run(ctx, 1) }()
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return c.Ready() == nil, nil
+	})
+	if err != nil {
+		t.Fatalf("controller not ready: %v", c.Ready())
+	}
+
+	c.Workqueue.Add(getKey(app, t))
+	<-started
+	if err := c.Healthy(); err != nil {
+		t.Errorf("expected the controller to be healthy, got %v", err)
+	}
+	c.Clock.(*testingclock.FakeClock).Step(2 * time.Minute)
+	if err := c.Healthy(); err == nil {
+		t.Errorf("expected a stuck item to fail the health check")
+	}
+
+	close(release)
+	err = wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return c.Healthy() == nil, nil
+	})
+	if err != nil {
+		t.Errorf("expected the controller to recover, got %v", c.Healthy())
+	}
+}
+
+func TestSyncHandlerIsTraced(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", int32Ptr(1))
+	app.Generation = 2
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+
+	c, _, _ := f.newController()
+	recorder := tracetest.NewSpanRecorder()
+	c.Tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(controller.TracerName)
+	if err := c.SyncHandler(context.Background(), getKey(app, t)); err != nil {
+		t.Fatalf("error syncing application: %v", err)
+	}
+
+	spans := recorder.Ended()
+	names := []string{}
+	for _, span := range spans {
+		names = append(names, span.Name())
+	}
+	expected := []string{"ApplicationsLister.Get", "DeploymentsLister.Get", "Deployments.Create", "Applications.UpdateStatus", "SyncHandler"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected spans %v, got %v", expected, names)
+	}
+
+	root := spans[len(spans)-1]
+	for _, span := range spans[:len(spans)-1] {
+		if span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("expected span %s to be a child of SyncHandler", span.Name())
+		}
+	}
+	expectedAttributes := []attribute.KeyValue{
+		attribute.String("application.key", "default/test"),
+		attribute.Int64("application.generation", 2),
+	}
+	if !reflect.DeepEqual(root.Attributes(), expectedAttributes) {
+		t.Errorf("expected SyncHandler attributes %v, got %v", expectedAttributes, root.Attributes())
+	}
+}
+
+func TestScopedControllerWatchesNamespaces(t *testing.T) {
+	f := newFixture(t)
+	f.client = fake.NewSimpleClientset()
+	f.kubeclient = k8sfake.NewSimpleClientset()
+
+	var scopes []controller.Informers
+	for _, namespace := range []string{"team-a", "team-b"} {
+		k8sI := kubeinformers.NewSharedInformerFactoryWithOptions(f.kubeclient, noResyncPeriodFunc(), kubeinformers.WithNamespace(namespace))
+		i := informers.NewSharedInformerFactoryWithOptions(f.client, noResyncPeriodFunc(), informers.WithNamespace(namespace))
+		scope := controller.Informers{
+			Namespace:            namespace,
+			Deployments:          k8sI.Apps().V1().Deployments(),
+			Pods:                 k8sI.Core().V1().Pods(),
+			Services:             k8sI.Core().V1().Services(),
+			Applications:         i.Cloudest().V1().Applications(),
+			ApplicationRevisions: i.Cloudest().V1().ApplicationRevisions(),
+		}
+		app := newApplication("test", "nginx", int32Ptr(1))
+		app.Namespace = namespace
+		_ = scope.Applications.Informer().GetIndexer().Add(app)
+		scopes = append(scopes, scope)
+	}
+	c := controller.NewScopedController(f.kubeclient, f.client, workqueue.DefaultControllerRateLimiter(), scopes...)
+
+	apps, err := c.ApplicationsLister.List(labels.Everything())
+	if err != nil || len(apps) != 2 {
+		t.Errorf("expected the applications of both namespaces, got %d (%v)", len(apps), err)
+	}
+	if _, err := c.ApplicationsLister.Applications("team-b").Get("test"); err != nil {
+		t.Errorf("unexpected error getting team-b/test: %v", err)
+	}
+	if _, err := c.ApplicationsLister.Applications("team-c").Get("test"); !errors.IsNotFound(err) {
+		t.Errorf("expected team-c/test not to be found, got %v", err)
+	}
+	apps, err = c.ApplicationsLister.Applications("team-a").List(labels.Everything())
+	if err != nil || len(apps) != 1 || apps[0].Namespace != "team-a" {
+		t.Errorf("expected the application of team-a, got %v (%v)", apps, err)
+	}
+}
+
+func TestShutdownDrainsInFlightReconcile(t *testing.T) {
+	f := newFixture(t)
+	inFlight := newApplication("in-flight", "nginx", int32Ptr(1))
+	queued := newApplication("queued", "nginx", int32Ptr(1))
+	f.applicationLister = append(f.applicationLister, inFlight, queued)
+	f.objects = append(f.objects, inFlight, queued)
+
+	c, _, _ := f.newController()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	f.kubeclient.PrependReactor("create", "deployments", func(action core.Action) (bool, runtime.Object, error) {
+		if action.(core.CreateAction).GetObject().(*apps.Deployment).Name == inFlight.Name {
+			close(started)
+			<-release
+		}
+		return false, nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() { stopped <- c.Run(ctx, 1) }()
+	c.Workqueue.Add(getKey(inFlight, t))
+	<-started
+	c.Workqueue.Add(getKey(queued, t))
+
+	cancel()
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return c.Workqueue.ShuttingDown(), nil
+	})
+	if err != nil {
+		t.Fatalf("expected the workqueue to shut down")
+	}
+	close(release)
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run did not return after draining")
+	}
+
+	var created []string
+	for _, action := range filterInformerActions(f.kubeclient.Actions()) {
+		if action.Matches("create", "deployments") {
+			created = append(created, action.(core.CreateAction).GetObject().(*apps.Deployment).Name)
+		}
+	}
+	if !reflect.DeepEqual(created, []string{inFlight.Name}) {
+		t.Errorf("expected only the in-flight reconcile to complete, got deployments %v", created)
+	}
+	var statusUpdates int
+	for _, action := range filterInformerActions(f.client.Actions()) {
+		if action.Matches("update", "applications") && action.GetSubresource() == "status" {
+			statusUpdates++
+		}
+	}
+	if statusUpdates != 1 {
+		t.Errorf("expected the in-flight reconcile to update its status, got %d updates", statusUpdates)
+	}
+}
+
+func TestDryRunReportsPendingChanges(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", int32Ptr(1))
+	deployment := controller.NewDeployment(app)
+	deployment.Spec.Replicas = int32Ptr(2)
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	c, _, _ := f.newController()
+	report := c.EnableDryRun()
+	if err := c.SyncHandler(context.Background(), getKey(app, t)); err != nil {
+		t.Fatalf("error syncing application: %v", err)
+	}
+
+	changes := report.Changes()[getKey(app, t)]
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 pending changes, got %+v", changes)
+	}
+	expected := controller.Change{
+		Verb:      "update",
+		Resource:  "deployments",
+		Namespace: metav1.NamespaceDefault,
+		Name:      "test",
+		Diff:      []byte(`{"spec":{"replicas":1}}`),
+	}
+	if !reflect.DeepEqual(changes[0], expected) {
+		t.Errorf("expected change %+v, got %+v", expected, changes[0])
+	}
+	if changes[1].Verb != "update" || changes[1].Resource != "applications/status" {
+		t.Errorf("expected a status update, got %+v", changes[1])
+	}
+
+	w := httptest.NewRecorder()
+	report.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/dry-run", nil))
+	if !strings.Contains(w.Body.String(), `"default/test":[{"verb":"update","resource":"deployments"`) {
+		t.Errorf("expected the report to list the deployment update, got %s", w.Body.String())
+	}
+}
+
+func TestSizeProfileSetsReplicasAndResources(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", nil)
+	app.Spec.Size = v1.SizeLarge
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+
+	defaulted := app.DeepCopy()
+	defaulted.Spec.Replicas = int32Ptr(3)
+	defaulted.Spec.Resources = &corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("512Mi"),
+	}}
+	expDeployment := controller.NewDeployment(defaulted)
+	f.expectCreateDeploymentAction(expDeployment)
+
+	expectApp := defaulted.DeepCopy()
+	expectApp.Status.DeploymentRefNamespace = expDeployment.Namespace
+	expectApp.Status.DeploymentRefName = expDeployment.Name
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 3 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func newPolicy(name string, mode v1.PolicyMode, expression string) *v1.ApplicationPolicy {
+	return &v1.ApplicationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name), Generation: 1},
+		Spec: v1.ApplicationPolicySpec{
+			Mode:  mode,
+			Rules: []v1.PolicyRule{{Name: "rule", Expression: expression, Message: "broken rule"}},
+		},
+	}
+}
+
+func TestEnforcedPolicyBlocksWorkload(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:latest", int32Ptr(1))
+	deployment := controller.NewDeployment(app)
+	deployment.Spec.Template.Spec.Containers[0].Image = "nginx:1.21"
+	app.Status.DeploymentRefNamespace = deployment.Namespace
+	app.Status.DeploymentRefName = deployment.Name
+
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+	f.policyLister = append(f.policyLister,
+		newPolicy("pinned", v1.PolicyEnforce, `!object.spec.imageName.endsWith(":latest")`),
+		newPolicy("replicas", v1.PolicyWarn, "workload.spec.replicas >= 2"))
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Conditions = []metav1.Condition{{
+		Type:               v1.ApplicationPolicyViolation,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "Enforced",
+		Message:            "pinned/rule: broken rule; replicas/rule: broken rule",
+	}}
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestWarnedPolicyReconcilesWorkload(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx", int32Ptr(1))
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.policyLister = append(f.policyLister, newPolicy("replicas", v1.PolicyWarn, "workload.spec.replicas >= 2"))
+
+	expDeployment := controller.NewDeployment(app)
+	f.expectCreateDeploymentAction(expDeployment)
+
+	violation := metav1.Condition{
+		Type:               v1.ApplicationPolicyViolation,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "Warned",
+		Message:            "replicas/rule: broken rule",
+	}
+	warnedApp := app.DeepCopy()
+	warnedApp.Status.Conditions = []metav1.Condition{violation}
+	f.expectUpdateApplicationStatusAction(warnedApp)
+
+	expectApp := warnedApp.DeepCopy()
+	expectApp.Status.DeploymentRefNamespace = expDeployment.Namespace
+	expectApp.Status.DeploymentRefName = expDeployment.Name
+	expectApp.Status.Conditions = append([]metav1.Condition{violation},
+		rolloutConditions(app, "RolloutInProgress", "0 out of 1 new replicas have been updated")...)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestCompliantApplicationClearsPolicyViolation(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:1.21", int32Ptr(1))
+	app.Status.Conditions = []metav1.Condition{{
+		Type:   v1.ApplicationPolicyViolation,
+		Status: metav1.ConditionTrue,
+		Reason: "Enforced",
+	}}
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.policyLister = append(f.policyLister, newPolicy("pinned", v1.PolicyEnforce, `!object.spec.imageName.endsWith(":latest")`))
+
+	expDeployment := controller.NewDeployment(app)
+	f.expectCreateDeploymentAction(expDeployment)
+
+	clearedApp := app.DeepCopy()
+	clearedApp.Status.Conditions = []metav1.Condition{}
+	f.expectUpdateApplicationStatusAction(clearedApp)
+
+	expectApp := clearedApp.DeepCopy()
+	expectApp.Status.DeploymentRefNamespace = expDeployment.Namespace
+	expectApp.Status.DeploymentRefName = expDeployment.Name
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 1 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+// pushImage pushes a random image to reference in an in-process registry
+// and returns its digest.
+func pushImage(t *testing.T, reference string) string {
+	ref, err := name.ParseReference(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	image, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, image); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := image.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return digest.String()
+}
+
+// newRegistry serves an in-process registry and returns its host.
+func newRegistry(t *testing.T) string {
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(ioutil.Discard, "", 0))))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestImageIsPinnedToDigest(t *testing.T) {
+	host := newRegistry(t)
+	digest := pushImage(t, host+"/team/app:1.0")
+
+	f := newFixture(t)
+	f.registry = true
+	f.pinImageDigests = true
+	app := newApplication("test", host+"/team/app:1.0", int32Ptr(1))
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+
+	resolvedApp := app.DeepCopy()
+	resolvedApp.Status.Image = &v1.ImageStatus{Tag: "1.0", Digest: digest}
+	f.expectUpdateApplicationStatusAction(resolvedApp)
+
+	pinnedApp := resolvedApp.DeepCopy()
+	pinnedApp.Spec.ImageName = host + "/team/app@" + digest
+	expDeployment := controller.NewDeployment(pinnedApp)
+	f.expectCreateDeploymentAction(expDeployment)
+
+	expectApp := pinnedApp.DeepCopy()
+	expectApp.Status.DeploymentRefNamespace = expDeployment.Namespace
+	expectApp.Status.DeploymentRefName = expDeployment.Name
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 1 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestResolvedDigestIsReusedUntilPushed(t *testing.T) {
+	host := newRegistry(t)
+	first := pushImage(t, host+"/team/app:1.0")
+
+	f := newFixture(t)
+	f.registry = true
+	f.pinImageDigests = true
+	app := newApplication("test", host+"/team/app:1.0", int32Ptr(1))
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	c, i, k8sI := f.newController()
+
+	ctx := context.Background()
+	// sync reconciles the application, feeds the listers with the objects
+	// it wrote and returns the image of the deployment.
+	sync := func() string {
+		if err := c.SyncHandler(ctx, getKey(app, t)); err != nil {
+			t.Fatal(err)
+		}
+		updated, err := f.client.CloudestV1().Applications(app.Namespace).Get(ctx, app.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The fake clientset updates the spec along with the status.
+		stored := app.DeepCopy()
+		stored.Status = updated.Status
+		_ = i.Cloudest().V1().Applications().Informer().GetIndexer().Update(stored)
+		deployment, err := f.kubeclient.AppsV1().Deployments(app.Namespace).Get(ctx, app.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = k8sI.Apps().V1().Deployments().Informer().GetIndexer().Update(deployment)
+		return deployment.Spec.Template.Spec.Containers[0].Image
+	}
+
+	if image := sync(); image != host+"/team/app@"+first {
+		t.Fatalf("expected the image to be pinned to %s, got %s", first, image)
+	}
+	second := pushImage(t, host+"/team/app:1.0")
+	if image := sync(); image != host+"/team/app@"+first {
+		t.Errorf("expected the resolved digest %s to be reused, got %s", first, image)
+	}
+	repository, err := name.NewRepository(host + "/team/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.HandlePush(notification.Push{Repository: repository, Tag: "1.0", Digest: second})
+	if image := sync(); image != host+"/team/app@"+second {
+		t.Errorf("expected the pushed digest %s to be rolled out, got %s", second, image)
+	}
+
+	third := pushImage(t, host+"/team/app:1.0")
+	c.Clock.(*testingclock.FakeClock).Step(5 * time.Minute)
+	if image := sync(); image != host+"/team/app@"+third {
+		t.Errorf("expected the tag to be resolved again to %s, got %s", third, image)
+	}
+}
+
+// resolutionFailed returns the ImageResolutionFailed condition reporting
+// the error of resolving image.
+func resolutionFailed(t *testing.T, image string) metav1.Condition {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = remote.Head(ref); err == nil {
+		t.Fatalf("expected %s not to resolve", image)
+	}
+	return metav1.Condition{
+		Type:               v1.ApplicationImageResolutionFailed,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "Unresolved",
+		Message:            err.Error(),
+	}
+}
+
+func TestUnresolvableImageIsRolledOutByTag(t *testing.T) {
+	host := newRegistry(t)
+
+	f := newFixture(t)
+	f.registry = true
+	f.pinImageDigests = true
+	app := newApplication("test", host+"/team/app:missing", int32Ptr(1))
+	app.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry"}}
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+
+	failedApp := app.DeepCopy()
+	failedApp.Status.Conditions = []metav1.Condition{resolutionFailed(t, app.Spec.ImageName)}
+	f.expectUpdateApplicationStatusAction(failedApp)
+
+	expDeployment := controller.NewDeployment(app)
+	f.expectCreateDeploymentAction(expDeployment)
+
+	expectApp := failedApp.DeepCopy()
+	expectApp.Status.DeploymentRefNamespace = expDeployment.Namespace
+	expectApp.Status.DeploymentRefName = expDeployment.Name
+	expectApp.Status.Conditions = append(failedApp.Status.Conditions,
+		rolloutConditions(app, "RolloutInProgress", "0 out of 1 new replicas have been updated")...)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestUnresolvableImageKeepsDeployedDigest(t *testing.T) {
+	host := newRegistry(t)
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	f := newFixture(t)
+	f.registry = true
+	f.pinImageDigests = true
+	app := newApplication("test", host+"/team/app:1.0", int32Ptr(1))
+	pinnedApp := app.DeepCopy()
+	pinnedApp.Spec.ImageName = host + "/team/app@" + digest
+	d := controller.NewDeployment(pinnedApp)
+	app.Spec.Replicas = int32Ptr(2)
+	app.Status.Image = &v1.ImageStatus{Tag: "1.0", Digest: digest}
+	app.Status.DeploymentRefNamespace = d.Namespace
+	app.Status.DeploymentRefName = d.Name
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	f.deploymentLister = append(f.deploymentLister, d)
+	f.kubeobjects = append(f.kubeobjects, d)
+
+	failedApp := app.DeepCopy()
+	failedApp.Status.Conditions = []metav1.Condition{resolutionFailed(t, app.Spec.ImageName)}
+	f.expectUpdateApplicationStatusAction(failedApp)
+
+	// The workload is scaled, with the digest it runs.
+	pinnedApp = failedApp.DeepCopy()
+	pinnedApp.Spec.ImageName = host + "/team/app@" + digest
+	f.expectUpdateDeploymentAction(controller.NewDeployment(pinnedApp))
+
+	expectApp := pinnedApp.DeepCopy()
+	expectApp.Status.Conditions = append(failedApp.Status.Conditions,
+		rolloutConditions(app, "RolloutInProgress", "0 out of 2 new replicas have been updated")...)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestImageIsUpdatedToNewestAllowedTag(t *testing.T) {
+	host := newRegistry(t)
+	for _, tag := range []string{"1.21.0", "1.21.3", "1.22.0", "1.21.4-rc.1"} {
+		pushImage(t, host+"/team/app:"+tag)
+	}
+
+	f := newFixture(t)
+	f.registry = true
+	app := newApplication("test", host+"/team/app:1.21.0", int32Ptr(1))
+	app.Spec.ImageUpdatePolicy = &v1.ImageUpdatePolicy{Semver: ">=1.21.0 <1.22.0", Filter: `^[0-9.]+$`}
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+
+	updatedApp := app.DeepCopy()
+	updatedApp.Spec.ImageName = host + "/team/app:1.21.3"
+	f.expectUpdateApplicationAction(updatedApp)
+
+	checkTime := metav1.NewTime(fakeNow)
+	expectApp := updatedApp.DeepCopy()
+	expectApp.Status.ImageUpdate = &v1.ImageUpdateStatus{
+		LastCheckTime:  &checkTime,
+		LastUpdateTime: &checkTime,
+		From:           host + "/team/app:1.21.0",
+		To:             host + "/team/app:1.21.3",
+	}
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestImageUpdateWaitsForInterval(t *testing.T) {
+	f := newFixture(t)
+	f.registry = true
+	app := newApplication("test", "registry.invalid/team/app:1.21.0", int32Ptr(1))
+	app.Spec.ImageUpdatePolicy = &v1.ImageUpdatePolicy{Interval: &metav1.Duration{Duration: 10 * time.Minute}}
+	lastCheckTime := metav1.NewTime(fakeNow.Add(-5 * time.Minute))
+	app.Status.ImageUpdate = &v1.ImageUpdateStatus{LastCheckTime: &lastCheckTime}
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+
+	expDeployment := controller.NewDeployment(app)
+	f.expectCreateDeploymentAction(expDeployment)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.DeploymentRefNamespace = expDeployment.Namespace
+	expectApp.Status.DeploymentRefName = expDeployment.Name
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 1 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestPushEnqueuesApplicationsOfPushedImage(t *testing.T) {
+	f := newFixture(t)
+	for _, app := range []*v1.Application{
+		newApplication("same-tag", "registry.example.com/team/app:1.0.0", int32Ptr(1)),
+		newApplication("other-tag", "registry.example.com/team/app:0.9.0", int32Ptr(1)),
+		newApplication("other-repository", "registry.example.com/team/other:1.0.0", int32Ptr(1)),
+		newApplication("docker-hub", "team/app:1.0.0", int32Ptr(1)),
+		newApplication("digest", "registry.example.com/team/app@sha256:"+strings.Repeat("a", 64), int32Ptr(1)),
+	} {
+		f.applicationLister = append(f.applicationLister, app)
+	}
+	allowed := newApplication("allowed", "registry.example.com/team/app:0.9.0", int32Ptr(1))
+	allowed.Spec.ImageUpdatePolicy = &v1.ImageUpdatePolicy{Semver: ">=0.9.0 <2.0.0"}
+	excluded := newApplication("excluded", "registry.example.com/team/app:0.9.0", int32Ptr(1))
+	excluded.Spec.ImageUpdatePolicy = &v1.ImageUpdatePolicy{Semver: "<1.0.0"}
+	f.applicationLister = append(f.applicationLister, allowed, excluded)
+
+	c, _, _ := f.newController()
+	c.HandlePush(notification.Push{Repository: name.MustParseReference("registry.example.com/team/app").Context(), Tag: "1.0.0"})
+
+	var keys []string
+	for c.Workqueue.Len() > 0 {
+		key, _ := c.Workqueue.Get()
+		keys = append(keys, key.(string))
+		c.Workqueue.Done(key)
+	}
+	sort.Strings(keys)
+	if expected := []string{"default/allowed", "default/same-tag"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v to be enqueued, got %v", expected, keys)
+	}
+}
+
+func TestPushSkipsImageUpdateInterval(t *testing.T) {
+	host := newRegistry(t)
+	for _, tag := range []string{"1.21.0", "1.21.3"} {
+		pushImage(t, host+"/team/app:"+tag)
+	}
+
+	f := newFixture(t)
+	f.registry = true
+	app := newApplication("test", host+"/team/app:1.21.0", int32Ptr(1))
+	app.Spec.ImageUpdatePolicy = &v1.ImageUpdatePolicy{Semver: ">=1.21.0 <1.22.0"}
+	lastCheckTime := metav1.NewTime(fakeNow.Add(-time.Minute))
+	app.Status.ImageUpdate = &v1.ImageUpdateStatus{LastCheckTime: &lastCheckTime}
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	repository, err := name.NewRepository(host + "/team/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.pushes = []notification.Push{{Repository: repository, Tag: "1.21.3"}}
+
+	updatedApp := app.DeepCopy()
+	updatedApp.Spec.ImageName = host + "/team/app:1.21.3"
+	f.expectUpdateApplicationAction(updatedApp)
+
+	checkTime := metav1.NewTime(fakeNow)
+	expectApp := updatedApp.DeepCopy()
+	expectApp.Status.ImageUpdate = &v1.ImageUpdateStatus{
+		LastCheckTime:  &checkTime,
+		LastUpdateTime: &checkTime,
+		From:           host + "/team/app:1.21.0",
+		To:             host + "/team/app:1.21.3",
+	}
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+// signImage pushes the cosign signature of the image digest by key and
+// returns the Secret holding the public key.
+func signImage(t *testing.T, digest string, key *ecdsa.PrivateKey) *corev1.Secret {
+	ref, err := name.NewDigest(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload images.SimpleSigning
+	payload.Critical.Identity.DockerReference = ref.Context().Name()
+	payload.Critical.Image.DockerManifestDigest = ref.DigestStr()
+	payload.Critical.Type = images.SignatureType
+	b, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(b)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	image, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:       static.NewLayer(b, ggcrtypes.MediaType(images.SimpleSigningMediaType)),
+		Annotations: map[string]string{images.SignatureAnnotation: base64.StdEncoding.EncodeToString(signature)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(images.SignatureTag(ref), image); err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cosign", Namespace: metav1.NamespaceDefault},
+		Data:       map[string][]byte{"cosign.pub": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})},
+	}
+}
+
+func newSigningKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestSignedImageIsRolledOut(t *testing.T) {
+	host := newRegistry(t)
+	digest := pushImage(t, host+"/team/app:1.0")
+	pinned := host + "/team/app@" + digest
+
+	f := newFixture(t)
+	f.registry = true
+	app := newApplication("test", host+"/team/app:1.0", int32Ptr(1))
+	app.Spec.ImageVerification = &v1.ImageVerification{PublicKeysSecret: corev1.LocalObjectReference{Name: "cosign"}}
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	secret := signImage(t, pinned, newSigningKey(t))
+	f.secretLister = append(f.secretLister, secret)
+	f.kubeobjects = append(f.kubeobjects, secret)
+
+	// The image is pinned to the verified digest even without pinImageDigests.
+	resolvedApp := app.DeepCopy()
+	resolvedApp.Status.Image = &v1.ImageStatus{Tag: "1.0", Digest: digest}
+	f.expectUpdateApplicationStatusAction(resolvedApp)
+
+	verifiedApp := resolvedApp.DeepCopy()
+	verifiedApp.Spec.ImageName = pinned
+	expDeployment := controller.NewDeployment(verifiedApp)
+	f.expectCreateDeploymentAction(expDeployment)
+
+	expectApp := verifiedApp.DeepCopy()
+	expectApp.Status.DeploymentRefNamespace = expDeployment.Namespace
+	expectApp.Status.DeploymentRefName = expDeployment.Name
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 1 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestImageSignedByUnknownKeyIsNotRolledOut(t *testing.T) {
+	host := newRegistry(t)
+	digest := pushImage(t, host+"/team/app:1.0")
+	pinned := host + "/team/app@" + digest
+
+	f := newFixture(t)
+	f.registry = true
+	f.pinImageDigests = true
+	app := newApplication("test", pinned, int32Ptr(1))
+	app.Spec.ImageVerification = &v1.ImageVerification{PublicKeysSecret: corev1.LocalObjectReference{Name: "cosign"}}
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+	// The image is signed, but the Secret holds another key.
+	signImage(t, pinned, newSigningKey(t))
+	secret := signImage(t, host+"/team/other@"+digest, newSigningKey(t))
+	f.secretLister = append(f.secretLister, secret)
+	f.kubeobjects = append(f.kubeobjects, secret)
+
+	resolvedApp := app.DeepCopy()
+	resolvedApp.Status.Image = &v1.ImageStatus{Digest: digest}
+	f.expectUpdateApplicationStatusAction(resolvedApp)
+
+	expectApp := resolvedApp.DeepCopy()
+	expectApp.Status.Conditions = []metav1.Condition{{
+		Type:               v1.ApplicationSignatureVerificationFailed,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "Unverified",
+		Message:            "no signature of " + digest + " matches the public keys of secret cosign",
+	}}
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestUnresolvableImageIsNotVerified(t *testing.T) {
+	host := newRegistry(t)
+
+	f := newFixture(t)
+	f.registry = true
+	app := newApplication("test", host+"/team/app:missing", int32Ptr(1))
+	app.Spec.ImageVerification = &v1.ImageVerification{PublicKeysSecret: corev1.LocalObjectReference{Name: "cosign"}}
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+
+	failedApp := app.DeepCopy()
+	failedApp.Status.Conditions = []metav1.Condition{resolutionFailed(t, app.Spec.ImageName)}
+	f.expectUpdateApplicationStatusAction(failedApp)
+
+	// The tag is not rolled out unverified.
+	expectApp := failedApp.DeepCopy()
+	expectApp.Status.Conditions = append(expectApp.Status.Conditions, metav1.Condition{
+		Type:               v1.ApplicationSignatureVerificationFailed,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "Unverified",
+		Message:            `image "` + app.Spec.ImageName + `" is not resolved to a digest`,
+	})
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func newQuota(name string, spec v1.ApplicationQuotaSpec) *v1.ApplicationQuota {
+	return &v1.ApplicationQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault, Generation: 1},
+		Spec:       spec,
+	}
+}
+
+// quotaUsage returns the usage counted by the controller, built as it
+// builds it.
+func quotaUsage(applications, replicas int32, milliCPU, memory int64) v1.ApplicationQuotaUsage {
+	return v1.ApplicationQuotaUsage{
+		Applications: applications,
+		Replicas:     replicas,
+		CPU:          *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+		Memory:       *resource.NewQuantity(memory, resource.BinarySI),
+	}
+}
+
+func TestQuotaUsageIsReported(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:1.21", int32Ptr(2))
+	app.Spec.Resources = &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}
+	other := newApplication("other", "nginx:1.21", int32Ptr(1))
+	q := newQuota("team", v1.ApplicationQuotaSpec{Replicas: int32Ptr(5), CPU: resourcePtr("1")})
+	f.applicationLister = append(f.applicationLister, app, other)
+	f.quotaLister = append(f.quotaLister, q)
+	f.objects = append(f.objects, app, other, q)
+
+	expectQuota := q.DeepCopy()
+	expectQuota.Status.Used = quotaUsage(2, 3, 500, 2*64<<20)
+	f.expectUpdateQuotaStatusAction(expectQuota)
+
+	expDeployment := controller.NewDeployment(app)
+	f.expectCreateDeploymentAction(expDeployment)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.DeploymentRefNamespace = expDeployment.Namespace
+	expectApp.Status.DeploymentRefName = expDeployment.Name
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 2 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestQuotaCountsTheApplicationsOfOtherControllers(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:1.21", int32Ptr(1))
+	d := controller.NewDeployment(app)
+	app.Spec.Replicas = int32Ptr(2)
+	app.Status.DeploymentRefNamespace = d.Namespace
+	app.Status.DeploymentRefName = d.Name
+	other := newApplication("other", "nginx:1.21", int32Ptr(2))
+	other.Labels = map[string]string{"tenant": "b"}
+	q := newQuota("team", v1.ApplicationQuotaSpec{Replicas: int32Ptr(3)})
+	f.applicationLister = append(f.applicationLister, app)
+	f.otherApplications = append(f.otherApplications, other)
+	f.deploymentLister = append(f.deploymentLister, d)
+	f.quotaLister = append(f.quotaLister, q)
+	f.objects = append(f.objects, app, other, q)
+	f.kubeobjects = append(f.kubeobjects, d)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Conditions = []metav1.Condition{{
+		Type:               v1.ApplicationQuotaExceeded,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "ScaleUpBlocked",
+		Message:            "team: replicas 4 > 3",
+	}}
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	expectQuota := q.DeepCopy()
+	expectQuota.Status.Used = quotaUsage(2, 4, 0, 0)
+	f.expectUpdateQuotaStatusAction(expectQuota)
+
+	f.run(getKey(app, t))
+}
+
+func TestScaleUpExceedingQuotaIsBlocked(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication("test", "nginx:1.21", int32Ptr(1))
+	d := controller.NewDeployment(app)
+	app.Spec.Replicas = int32Ptr(3)
+	app.Status.DeploymentRefNamespace = d.Namespace
+	app.Status.DeploymentRefName = d.Name
+	q := newQuota("team", v1.ApplicationQuotaSpec{Applications: int32Ptr(5), Replicas: int32Ptr(2)})
+	f.applicationLister = append(f.applicationLister, app)
+	f.deploymentLister = append(f.deploymentLister, d)
+	f.quotaLister = append(f.quotaLister, q)
+	f.objects = append(f.objects, app, q)
+	f.kubeobjects = append(f.kubeobjects, d)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Conditions = []metav1.Condition{{
+		Type:               v1.ApplicationQuotaExceeded,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "ScaleUpBlocked",
+		Message:            "team: replicas 3 > 2",
+	}}
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	expectQuota := q.DeepCopy()
+	expectQuota.Status.Used = quotaUsage(1, 3, 0, 0)
+	f.expectUpdateQuotaStatusAction(expectQuota)
+
+	f.run(getKey(app, t))
+}
+
+func TestApplicationOverQuotaIsReconciledWithoutScaleUp(t *testing.T) {
+	f := newFixture(t)
+	// Admitted before the quota, the application is not scaled up.
+	app := newApplication("test", "nginx:1.22", int32Ptr(3))
+	d := controller.NewDeployment(newApplication("test", "nginx:1.21", int32Ptr(3)))
+	app.Status.DeploymentRefNamespace = d.Namespace
+	app.Status.DeploymentRefName = d.Name
+	q := newQuota("team", v1.ApplicationQuotaSpec{Replicas: int32Ptr(2)})
+	q.Status.Used = quotaUsage(1, 3, 0, 0)
+	f.applicationLister = append(f.applicationLister, app)
+	f.deploymentLister = append(f.deploymentLister, d)
+	f.quotaLister = append(f.quotaLister, q)
+	f.objects = append(f.objects, app, q)
+	f.kubeobjects = append(f.kubeobjects, d)
+
+	expDeployment := controller.NewDeployment(app)
+	f.expectUpdateDeploymentAction(expDeployment)
+
+	expectApp := app.DeepCopy()
+	expectApp.Spec.Replicas = int32Ptr(3)
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 3 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func newTemplate(name string, spec v1.ApplicationTemplateSpec) *v1.ApplicationTemplate {
+	return &v1.ApplicationTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault, Generation: 1},
+		Spec:       spec,
+	}
+}
+
+func newTemplatedApplication(name, template string) *v1.Application {
+	app := newApplication(name, "nginx:1.21", nil)
+	app.Spec.TemplateRef = &v1.TemplateReference{Kind: v1.ApplicationTemplateKind, Name: template}
+	return app
+}
+
+func TestTemplateIsAppliedToTheWorkload(t *testing.T) {
+	f := newFixture(t)
+	f.templates = true
+	template := newTemplate("web", v1.ApplicationTemplateSpec{
+		Replicas: int32Ptr(3),
+		Resources: &corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		}},
+		Labels:         map[string]string{"team": "a", "tier": "web"},
+		ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromInt(8080)}}},
+		NodeSelector:   map[string]string{"pool": "apps"},
+	})
+	app := newTemplatedApplication("test", "web")
+	app.Spec.Labels = map[string]string{"tier": "api"}
+	app.Spec.Resources = &corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")}}
+	f.templateLister = append(f.templateLister, template)
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, template, app)
+
+	expDeployment := controller.NewDeployment(newApplication("test", "nginx:1.21", int32Ptr(3)))
+	pod := &expDeployment.Spec.Template
+	pod.Labels = map[string]string{"controller": "test", "team": "a", "tier": "api"}
+	pod.Spec.NodeSelector = map[string]string{"pool": "apps"}
+	pod.Spec.Containers[0].Resources = corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("250m"),
+		corev1.ResourceMemory: resource.MustParse("128Mi"),
+	}}
+	pod.Spec.Containers[0].ReadinessProbe = &corev1.Probe{
+		ProbeHandler:     corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/", Port: intstr.FromInt(8080), Scheme: corev1.URISchemeHTTP}},
+		TimeoutSeconds:   1,
+		PeriodSeconds:    10,
+		SuccessThreshold: 1,
+		FailureThreshold: 3,
+	}
+	f.expectCreateDeploymentAction(expDeployment)
+
+	// The status is written with the spec the template was applied to,
+	// which the status subresource ignores.
+	expectApp := app.DeepCopy()
+	v1.ApplyTemplate(&expectApp.Spec, &template.Spec)
+	expectApp.Status.DeploymentRefNamespace = expDeployment.Namespace
+	expectApp.Status.DeploymentRefName = expDeployment.Name
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutInProgress", "0 out of 3 new replicas have been updated")
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestRollbackOfTemplatedApplication(t *testing.T) {
+	for _, revisions := range [][]int64{{1}, nil} {
+		f := newFixture(t)
+		f.templates = true
+		template := newTemplate("web", v1.ApplicationTemplateSpec{
+			Replicas: int32Ptr(3),
+			Labels:   map[string]string{"tier": "web"},
+		})
+		app := newTemplatedApplication("test", "web")
+		app.Spec.ImageName = "nginx:broken"
+		app.Spec.RollbackTo = func(i int64) *int64 { return &i }(1)
+
+		f.templateLister = append(f.templateLister, template)
+		f.applicationLister = append(f.applicationLister, app)
+		f.objects = append(f.objects, template, app)
+		for _, number := range revisions {
+			revision := controller.NewApplicationRevision(newTemplatedApplication("test", "web"), number)
+			f.revisionLister = append(f.revisionLister, revision)
+			f.objects = append(f.objects, revision)
+		}
+
+		// The fields of the template and the defaults are not written,
+		// whether the revision exists or not.
+		expectApp := app.DeepCopy()
+		expectApp.Spec.RollbackTo = nil
+		if len(revisions) > 0 {
+			expectApp.Spec.ImageName = "nginx:1.21"
+		}
+		f.expectUpdateApplicationAction(expectApp)
+
+		f.run(getKey(app, t))
+	}
+}
+
+func TestRevisionOfTemplatedApplication(t *testing.T) {
+	f := newFixture(t)
+	f.templates = true
+	template := newTemplate("web", v1.ApplicationTemplateSpec{Replicas: int32Ptr(1)})
+	app := newTemplatedApplication("test", "web")
+	expDeployment := controller.NewDeployment(newApplication("test", "nginx:1.21", int32Ptr(1)))
+	completeRollout(expDeployment)
+	app.Status.DeploymentRefNamespace = expDeployment.Namespace
+	app.Status.DeploymentRefName = expDeployment.Name
+
+	f.templateLister = append(f.templateLister, template)
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, template, app)
+	f.deploymentLister = append(f.deploymentLister, expDeployment)
+	f.kubeobjects = append(f.kubeobjects, expDeployment)
+
+	// The revision holds the spec as stored, without its template.
+	f.expectCreateRevisionAction(controller.NewApplicationRevision(app, 1))
+
+	expectApp := app.DeepCopy()
+	v1.ApplyTemplate(&expectApp.Spec, &template.Spec)
+	expectApp.Status.UpdatedReplicas = 1
+	expectApp.Status.ReadyReplicas = 1
+	expectApp.Status.CurrentRevision = 1
+	expectApp.Status.LastReadyImage = "nginx:1.21"
+	expectApp.Status.Conditions = rolloutConditions(app, "RolloutComplete", `Deployment "test" successfully rolled out`)
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestMissingTemplateBlocksReconcile(t *testing.T) {
+	f := newFixture(t)
+	f.templates = true
+	app := newTemplatedApplication("test", "web")
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, app)
+
+	expectApp := app.DeepCopy()
+	expectApp.Status.Conditions = []metav1.Condition{{
+		Type:               v1.ApplicationTemplateNotFound,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(fakeNow),
+		Reason:             "NotFound",
+		Message:            `applicationtemplate.cloudest.artifakt.io "web" not found`,
+	}}
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestImageUpdateDoesNotPersistTemplate(t *testing.T) {
+	host := newRegistry(t)
+	for _, tag := range []string{"1.21.0", "1.21.3"} {
+		pushImage(t, host+"/team/app:"+tag)
+	}
+
+	f := newFixture(t)
+	f.registry = true
+	f.templates = true
+	template := newTemplate("web", v1.ApplicationTemplateSpec{Replicas: int32Ptr(3), Labels: map[string]string{"team": "a"}})
+	app := newTemplatedApplication("test", "web")
+	app.Spec.ImageName = host + "/team/app:1.21.0"
+	app.Spec.ImageUpdatePolicy = &v1.ImageUpdatePolicy{Semver: ">=1.21.0 <1.22.0"}
+	f.templateLister = append(f.templateLister, template)
+	f.applicationLister = append(f.applicationLister, app)
+	f.objects = append(f.objects, template, app)
+
+	// The application keeps following its template.
+	updatedApp := app.DeepCopy()
+	updatedApp.Spec.ImageName = host + "/team/app:1.21.3"
+	f.expectUpdateApplicationAction(updatedApp)
+
+	checkTime := metav1.NewTime(fakeNow)
+	expectApp := updatedApp.DeepCopy()
+	expectApp.Status.ImageUpdate = &v1.ImageUpdateStatus{
+		LastCheckTime:  &checkTime,
+		LastUpdateTime: &checkTime,
+		From:           host + "/team/app:1.21.0",
+		To:             host + "/team/app:1.21.3",
+	}
+	f.expectUpdateApplicationStatusAction(expectApp)
+
+	f.run(getKey(app, t))
+}
+
+func TestTemplateChangeRequeuesDependents(t *testing.T) {
+	f := newFixture(t)
+	f.templates = true
+	template := newTemplate("web", v1.ApplicationTemplateSpec{Replicas: int32Ptr(2)})
+	clusterTemplate := &v1.ClusterApplicationTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Generation: 1},
+		Spec:       v1.ApplicationTemplateSpec{Replicas: int32Ptr(2)},
+	}
+	dependent := newTemplatedApplication("dependent", "web")
+	clusterDependent := newTemplatedApplication("cluster-dependent", "web")
+	clusterDependent.Spec.TemplateRef.Kind = v1.ClusterApplicationTemplateKind
+	other := newTemplatedApplication("other", "other")
+	independent := newApplication("independent", "nginx:1.21", int32Ptr(1))
+	f.templateLister = append(f.templateLister, template)
+	f.clusterTemplateLister = append(f.clusterTemplateLister, clusterTemplate)
+	f.applicationLister = append(f.applicationLister, dependent, clusterDependent, other, independent)
+	f.objects = append(f.objects, template, clusterTemplate, dependent, clusterDependent, other, independent)
+
+	c, i, _ := f.newController()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	i.Start(stopCh)
+	i.WaitForCacheSync(stopCh)
+
+	// The spec is updated until the watch of the informer delivers it.
+	enqueued := func(update func(generation int64) error) []string {
+		for generation := int64(2); generation < 50 && c.Workqueue.Len() == 0; generation++ {
+			if err := update(generation); err != nil {
+				t.Fatal(err)
+			}
+			_ = wait.PollImmediate(10*time.Millisecond, 100*time.Millisecond, func() (bool, error) {
+				return c.Workqueue.Len() > 0, nil
+			})
+		}
+		var keys []string
+		for c.Workqueue.Len() > 0 {
+			key, _ := c.Workqueue.Get()
+			keys = append(keys, key.(string))
+			c.Workqueue.Done(key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	keys := enqueued(func(generation int64) error {
+		templateCopy := template.DeepCopy()
+		templateCopy.Generation = generation
+		templateCopy.Spec.Replicas = int32Ptr(int32(generation))
+		_, err := f.client.CloudestV1().ApplicationTemplates(template.Namespace).Update(context.Background(), templateCopy, metav1.UpdateOptions{})
+		return err
+	})
+	if expected := []string{"default/dependent"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v to be enqueued on template update, got %v", expected, keys)
+	}
+
+	keys = enqueued(func(generation int64) error {
+		templateCopy := clusterTemplate.DeepCopy()
+		templateCopy.Generation = generation
+		_, err := f.client.CloudestV1().ClusterApplicationTemplates().Update(context.Background(), templateCopy, metav1.UpdateOptions{})
+		return err
+	})
+	if expected := []string{"default/cluster-dependent"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v to be enqueued on cluster template update, got %v", expected, keys)
+	}
+}
@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"net/http"
+	"sync"
+)
+
+// Change is a write a controller in dry-run mode would have made.
+type Change struct {
+	Verb      string `json:"verb"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Diff is the JSON merge patch from the current object to the object
+	// the write would persist. It is empty for deletes.
+	Diff json.RawMessage `json:"diff,omitempty"`
+}
+
+// DryRunReport holds the pending changes of each application, as computed
+// by its last reconcile in dry-run mode.
+type DryRunReport struct {
+	mu      sync.RWMutex
+	changes map[string][]Change
+}
+
+// NewDryRunReport returns an empty report.
+func NewDryRunReport() *DryRunReport {
+	return &DryRunReport{changes: map[string][]Change{}}
+}
+
+// Changes returns the pending changes keyed by application.
+func (r *DryRunReport) Changes() map[string][]Change {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	changes := make(map[string][]Change, len(r.changes))
+	for key, c := range r.changes {
+		changes[key] = append([]Change(nil), c...)
+	}
+	return changes
+}
+
+// ServeHTTP serves the pending changes as JSON.
+func (r *DryRunReport) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Applications map[string][]Change `json:"applications"`
+	}{r.Changes()})
+}
+
+// set replaces the pending changes of the application key.
+func (r *DryRunReport) set(key string, changes []Change) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(changes) == 0 {
+		delete(r.changes, key)
+		return
+	}
+	r.changes[key] = changes
+}
+
+// dryRunChangesKey is the context key of the changes recorded by the
+// reconcile of an application.
+type dryRunChangesKey struct{}
+
+// dryRunChanges collects the changes of the reconcile of key.
+type dryRunChanges struct {
+	key     string
+	changes []Change
+}
+
+// withDryRunChanges returns a context in which the writes of the reconcile
+// of key are recorded.
+func withDryRunChanges(ctx context.Context, key string) (context.Context, *dryRunChanges) {
+	changes := &dryRunChanges{key: key}
+	return context.WithValue(ctx, dryRunChangesKey{}, changes), changes
+}
+
+func (c *Controller) createOptions() metav1.CreateOptions {
+	if c.DryRun != nil {
+		return metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}
+	}
+	return metav1.CreateOptions{}
+}
+
+func (c *Controller) updateOptions() metav1.UpdateOptions {
+	if c.DryRun != nil {
+		return metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}}
+	}
+	return metav1.UpdateOptions{}
+}
+
+func (c *Controller) deleteOptions() metav1.DeleteOptions {
+	if c.DryRun != nil {
+		return metav1.DeleteOptions{DryRun: []string{metav1.DryRunAll}}
+	}
+	return metav1.DeleteOptions{}
+}
+
+// recordChange logs and records the dry-run write verb of resource
+// namespace/name, changing current into desired. current is nil for creates
+// and desired for deletes.
+func (c *Controller) recordChange(ctx context.Context, verb, resource, namespace, name string, current, desired runtime.Object) {
+	if c.DryRun == nil {
+		return
+	}
+	change := Change{Verb: verb, Resource: resource, Namespace: namespace, Name: name}
+	if desired != nil {
+		diff, err := mergePatch(current, desired)
+		if err != nil {
+			klog.ErrorS(err, "Unable to diff dry-run change", "resource", resource, "object", klog.KRef(namespace, name))
+		}
+		change.Diff = diff
+	}
+
+	key := ""
+	if changes, ok := ctx.Value(dryRunChangesKey{}).(*dryRunChanges); ok {
+		key = changes.key
+		changes.changes = append(changes.changes, change)
+	}
+	klog.InfoS("Dry-run change", "application", key, "verb", verb, "resource", resource,
+		"object", klog.KRef(namespace, name), "diff", string(change.Diff))
+}
+
+// mergePatch returns the JSON merge patch from current to desired, ignoring
+// the metadata set by the API server.
+func mergePatch(current, desired runtime.Object) ([]byte, error) {
+	currentJSON := []byte("{}")
+	if current != nil {
+		var err error
+		if currentJSON, err = json.Marshal(withoutServerMetadata(current)); err != nil {
+			return nil, err
+		}
+	}
+	desiredJSON, err := json.Marshal(withoutServerMetadata(desired))
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.CreateMergePatch(currentJSON, desiredJSON)
+}
+
+func withoutServerMetadata(obj runtime.Object) runtime.Object {
+	obj = obj.DeepCopyObject()
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetUID("")
+		accessor.SetResourceVersion("")
+		accessor.SetGeneration(0)
+		accessor.SetCreationTimestamp(metav1.Time{})
+		accessor.SetManagedFields(nil)
+	}
+	return obj
+}
+
+// dryRunRecorder logs the events of a controller in dry-run mode instead of
+// creating them.
+type dryRunRecorder struct{}
+
+func (dryRunRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	accessor, _ := meta.Accessor(object)
+	klog.InfoS("Dry-run event", "object", klog.KObj(accessor), "type", eventtype, "reason", reason, "message", message)
+}
+
+func (r dryRunRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r dryRunRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+// EnableDryRun makes the writes of the controller server-side dry-runs whose
+// changes are logged and recorded in the returned report. Events are logged
+// instead of created.
+func (c *Controller) EnableDryRun() *DryRunReport {
+	c.DryRun = NewDryRunReport()
+	c.Recorder = dryRunRecorder{}
+	return c.DryRun
+}
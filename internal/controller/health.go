@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultStuckItemThreshold is the time after which an item still in
+// processing is considered stuck.
+const defaultStuckItemThreshold = 10 * time.Minute
+
+// workerState tracks the workers started by Run and the items they process.
+type workerState struct {
+	started     int32
+	liveWorkers int32
+
+	mu         sync.Mutex
+	processing map[string]time.Time
+}
+
+func (s *workerState) startProcessing(key string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.processing == nil {
+		s.processing = map[string]time.Time{}
+	}
+	s.processing[key] = now
+}
+
+func (s *workerState) doneProcessing(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.processing, key)
+}
+
+// oldestProcessing returns the key processed for the longest time and when
+// its processing started.
+func (s *workerState) oldestProcessing() (string, time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var oldestKey string
+	var oldest time.Time
+	for key, start := range s.processing {
+		if oldestKey == "" || start.Before(oldest) {
+			oldestKey, oldest = key, start
+		}
+	}
+	return oldestKey, oldest, oldestKey != ""
+}
+
+// Ready returns an error until the informer caches are synced and, once Run
+// started the workers, when none of them is live. Standbys waiting for the
+// Lease do not run the workers and are ready with synced caches, so that
+// they do not stall rolling updates.
+func (c *Controller) Ready() error {
+	for _, synced := range c.cacheSyncs() {
+		if !synced() {
+			return fmt.Errorf("informer caches are not synced")
+		}
+	}
+	if atomic.LoadInt32(&c.workers.started) == 1 && atomic.LoadInt32(&c.workers.liveWorkers) == 0 {
+		return fmt.Errorf("no worker is running")
+	}
+	return nil
+}
+
+// Healthy returns an error when an item has been in processing for longer
+// than StuckItemThreshold, which means the workqueue is wedged.
+func (c *Controller) Healthy() error {
+	key, start, ok := c.workers.oldestProcessing()
+	if !ok {
+		return nil
+	}
+	if elapsed := c.Clock.Since(start); elapsed > c.StuckItemThreshold {
+		return fmt.Errorf("item %q has been processed for %s", key, elapsed.Round(time.Second))
+	}
+	return nil
+}
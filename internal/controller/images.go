@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/artifakt-io/demo-controller/internal/images"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"time"
+)
+
+// defaultImageUpdateInterval is the period between two listings of the tags
+// of an image whose ImageUpdatePolicy does not set its interval.
+const defaultImageUpdateInterval = 5 * time.Minute
+
+// imageResolutionRetryDelay is the delay after which the resolution of an
+// image which failed is retried.
+const imageResolutionRetryDelay = time.Minute
+
+// signatureVerificationRetryDelay is the delay after which the verification
+// of an image which failed is retried.
+const signatureVerificationRetryDelay = time.Minute
+
+// imageResolutionInterval is the period during which the digest a tag was
+// resolved to is reused, unless a push of the tag is notified.
+const imageResolutionInterval = 5 * time.Minute
+
+// resolvedImage is the digest the image of a generation of an application
+// was resolved to.
+type resolvedImage struct {
+	image      string
+	generation int64
+	resolved   images.Resolved
+	time       time.Time
+}
+
+// WatchImages reads the images of the applications from their registry,
+// authenticated with the pull secrets read from the Secrets of scopes.
+func (c *Controller) WatchImages(scopes ...Informers) {
+	secrets := func(s Informers) cache.SharedIndexInformer { return s.Secrets.Informer() }
+	c.Images = images.NewResolver(corelisters.NewSecretLister(indexerOf(scopes, secrets)))
+	c.ImagesSynced = hasSynced(scopes, secrets)
+}
+
+// pinImage resolves the image of app to the digest of the manifest its tag
+// points to, so that every replica of the workload runs the same image, and
+// so that verifyImage verifies the digest rolled out. The tag and digest are
+// recorded in the status, and the spec of app is changed to the image pinned
+// to the digest. A failed resolution does not hold the reconcile: it is
+// reported in the ImageResolutionFailed condition and retried later, while
+// the workload keeps the digest it runs for the same tag, or runs the tag.
+// app is updated with the persisted status.
+func (c *Controller) pinImage(ctx context.Context, key string, app *v1.Application) error {
+	previousCondition := meta.FindStatusCondition(app.Status.Conditions, v1.ApplicationImageResolutionFailed)
+	if c.Images == nil || (!c.PinImageDigests && app.Spec.ImageVerification == nil) {
+		if previousCondition == nil {
+			return nil
+		}
+		status := app.Status.DeepCopy()
+		meta.RemoveStatusCondition(&status.Conditions, v1.ApplicationImageResolutionFailed)
+		return c.writeImageStatus(ctx, app, status)
+	}
+
+	status := app.Status.DeepCopy()
+	resolved, resolveErr := c.resolveImage(ctx, key, app)
+	if resolveErr != nil {
+		c.setCondition(status, metav1.Condition{
+			Type:               v1.ApplicationImageResolutionFailed,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: app.Generation,
+			Reason:             "Unresolved",
+			Message:            resolveErr.Error(),
+		})
+		if previousCondition == nil || previousCondition.Message != resolveErr.Error() {
+			c.Recorder.Eventf(app, corev1.EventTypeWarning, ErrImageResolution, MessageImageResolution, app.Spec.ImageName, resolveErr.Error())
+		}
+		c.Workqueue.AddAfter(key, imageResolutionRetryDelay)
+		if err := c.writeImageStatus(ctx, app, status); err != nil {
+			return err
+		}
+		if pinned, ok := c.deployedDigest(ctx, app); ok {
+			app.Spec.ImageName = pinned
+		}
+		return nil
+	}
+
+	previous := app.Status.Image
+	status.Image = &v1.ImageStatus{Tag: resolved.Tag, Digest: resolved.Digest}
+	meta.RemoveStatusCondition(&status.Conditions, v1.ApplicationImageResolutionFailed)
+	if err := c.writeImageStatus(ctx, app, status); err != nil {
+		return err
+	}
+	if resolved.Tag != "" && (previous == nil || previous.Digest != resolved.Digest) {
+		c.Recorder.Eventf(app, corev1.EventTypeNormal, SuccessImageResolved, MessageImageResolved, app.Spec.ImageName, resolved.Digest)
+	}
+	app.Spec.ImageName = resolved.Image
+	return nil
+}
+
+// resolveImage resolves the image of app, reusing the digest it was resolved
+// to for the same generation during imageResolutionInterval.
+func (c *Controller) resolveImage(ctx context.Context, key string, app *v1.Application) (images.Resolved, error) {
+	now := c.Clock.Now()
+	if cached, ok := c.resolvedImages.Load(key); ok {
+		last := cached.(resolvedImage)
+		if last.image == app.Spec.ImageName && last.generation == app.Generation && now.Sub(last.time) < imageResolutionInterval {
+			return last.resolved, nil
+		}
+	}
+	resolved, err := c.Images.Resolve(ctx, app.Namespace, app.Spec.ImagePullSecrets, app.Spec.ImageName)
+	if err != nil {
+		c.resolvedImages.Delete(key)
+		return images.Resolved{}, err
+	}
+	c.resolvedImages.Store(key, resolvedImage{image: app.Spec.ImageName, generation: app.Generation, resolved: resolved, time: now})
+	return resolved, nil
+}
+
+// writeImageStatus persists status and updates app with it.
+func (c *Controller) writeImageStatus(ctx context.Context, app *v1.Application, status *v1.ApplicationStatus) error {
+	updated, err := c.writeApplicationStatus(ctx, app, status)
+	if err != nil {
+		return err
+	}
+	app.Status = updated.Status
+	app.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// deployedDigest returns the image of app pinned to the digest its tag was
+// last resolved to, when the workload still runs it.
+func (c *Controller) deployedDigest(ctx context.Context, app *v1.Application) (string, bool) {
+	previous := app.Status.Image
+	if previous == nil || app.Status.DeploymentRefName == "" {
+		return "", false
+	}
+	pinned, ok := images.Pin(app.Spec.ImageName, previous.Tag, previous.Digest)
+	if !ok {
+		return "", false
+	}
+	deployment, err := c.getDeployment(ctx, app.Status.DeploymentRefNamespace, app.Status.DeploymentRefName)
+	if err != nil || mainContainerFromDeploymentTemplate(deployment).Image != pinned {
+		return "", false
+	}
+	return pinned, true
+}
+
+// updateImage applies the ImageUpdatePolicy of app: once its interval elapsed
+// since the last check, or when an allowed tag was pushed, the tags of the
+// repository of the image are listed and the image is updated to the newest
+// allowed tag. It returns whether the spec was updated, which triggers a new
+// sync. app is updated with the persisted status otherwise.
+func (c *Controller) updateImage(ctx context.Context, key string, app *v1.Application) (bool, error) {
+	policy := app.Spec.ImageUpdatePolicy
+	if policy == nil || c.Images == nil {
+		return false, nil
+	}
+	interval := defaultImageUpdateInterval
+	if policy.Interval != nil {
+		interval = policy.Interval.Duration
+	}
+	now := c.Clock.Now()
+	_, pushed := c.pushes.LoadAndDelete(key)
+	if update := app.Status.ImageUpdate; !pushed && update != nil && update.LastCheckTime != nil {
+		if next := update.LastCheckTime.Add(interval); now.Before(next) {
+			c.Workqueue.AddAfter(key, next.Sub(now))
+			return false, nil
+		}
+	}
+	c.Workqueue.AddAfter(key, interval)
+
+	status := app.Status.DeepCopy()
+	if status.ImageUpdate == nil {
+		status.ImageUpdate = &v1.ImageUpdateStatus{}
+	}
+	checkTime := metav1.NewTime(now)
+	status.ImageUpdate.LastCheckTime = &checkTime
+
+	// A registry failing to list the tags does not hold the reconcile of
+	// the workload, the tags are listed again after the interval.
+	image := ""
+	tags, err := c.Images.ListTags(ctx, app.Namespace, app.Spec.ImagePullSecrets, app.Spec.ImageName)
+	if err == nil {
+		image, err = images.Update(app.Spec.ImageName, tags, policy)
+	}
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to update image %q of application '%s': %w", app.Spec.ImageName, key, err))
+		c.Recorder.Eventf(app, corev1.EventTypeWarning, ErrImageUpdate, MessageImageUpdateFailed, app.Spec.ImageName, err.Error())
+	}
+
+	current := app
+	if image != "" {
+		appCopy := c.forSpecUpdate(app)
+		appCopy.Spec.ImageName = image
+		if current, err = c.updateApplication(ctx, app, appCopy); err != nil {
+			return false, err
+		}
+		status.ImageUpdate.LastUpdateTime = &checkTime
+		status.ImageUpdate.From = app.Spec.ImageName
+		status.ImageUpdate.To = image
+	}
+	updated, err := c.writeApplicationStatus(ctx, current, status)
+	if err != nil {
+		return false, err
+	}
+	if image != "" {
+		c.Recorder.Eventf(app, corev1.EventTypeNormal, SuccessImageUpdated, MessageImageUpdated, app.Spec.ImageName, image)
+		return true, nil
+	}
+	app.Status = updated.Status
+	app.ResourceVersion = updated.ResourceVersion
+	return false, nil
+}
+
+// verifyImage verifies the cosign signature of the image of app, pinned to
+// its digest by pinImage, against the public keys of its ImageVerification.
+// A failed verification is reported in the SignatureVerificationFailed
+// condition and retried later, holding the rollout until the image is
+// signed. It returns whether the rollout is held. app is updated with the
+// persisted status.
+func (c *Controller) verifyImage(ctx context.Context, key string, app *v1.Application) (bool, error) {
+	verification := app.Spec.ImageVerification
+	previous := meta.FindStatusCondition(app.Status.Conditions, v1.ApplicationSignatureVerificationFailed)
+	if verification == nil && previous == nil {
+		return false, nil
+	}
+
+	status := app.Status.DeepCopy()
+	var verifyErr error
+	if verification != nil {
+		if c.Images == nil {
+			verifyErr = fmt.Errorf("no registry client to verify signatures")
+		} else if _, err := name.NewDigest(app.Spec.ImageName); err != nil {
+			verifyErr = fmt.Errorf("image %q is not resolved to a digest", app.Spec.ImageName)
+		} else {
+			verifyErr = c.Images.Verify(ctx, app.Namespace, app.Spec.ImagePullSecrets, app.Spec.ImageName, verification.PublicKeysSecret.Name)
+		}
+	}
+	if verifyErr != nil {
+		c.setCondition(status, metav1.Condition{
+			Type:               v1.ApplicationSignatureVerificationFailed,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: app.Generation,
+			Reason:             "Unverified",
+			Message:            verifyErr.Error(),
+		})
+	} else {
+		meta.RemoveStatusCondition(&status.Conditions, v1.ApplicationSignatureVerificationFailed)
+	}
+	if err := c.writeImageStatus(ctx, app, status); err != nil {
+		return false, err
+	}
+
+	if verifyErr != nil {
+		if previous == nil || previous.Message != verifyErr.Error() {
+			c.Recorder.Eventf(app, corev1.EventTypeWarning, ErrSignatureVerification, MessageSignatureVerification, app.Spec.ImageName, verifyErr.Error())
+		}
+		c.Workqueue.AddAfter(key, signatureVerificationRetryDelay)
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/artifakt-io/demo-controller/internal/images"
+	"github.com/artifakt-io/demo-controller/internal/notification"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// HandlePush enqueues the applications whose image is changed by push: those
+// running the pushed tag of the repository, which is resolved to its new
+// digest, and those whose ImageUpdatePolicy allows the pushed tag, whose
+// tags are listed without waiting for their interval.
+func (c *Controller) HandlePush(push notification.Push) {
+	apps, err := c.ApplicationsLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, app := range apps {
+		key, err := cache.MetaNamespaceKeyFunc(app)
+		if err != nil {
+			utilruntime.HandleError(err)
+			continue
+		}
+		ok, err := pushChangesImage(app, push)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("unable to match the image of application '%s': %w", key, err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		klog.V(2).InfoS("Enqueuing application for pushed image", "application", klog.KObj(app),
+			"repository", push.Repository.Name(), "tag", push.Tag)
+		if app.Spec.ImageUpdatePolicy != nil {
+			c.pushes.Store(key, struct{}{})
+		}
+		c.resolvedImages.Delete(key)
+		c.Workqueue.Add(key)
+	}
+}
+
+// pushChangesImage returns whether push changes the image of app.
+func pushChangesImage(app *v1.Application, push notification.Push) (bool, error) {
+	ref, err := name.ParseReference(app.Spec.ImageName)
+	if err != nil {
+		return false, err
+	}
+	if ref.Context().Name() != push.Repository.Name() {
+		return false, nil
+	}
+	if app.Spec.ImageUpdatePolicy != nil {
+		return images.Allowed(push.Tag, app.Spec.ImageUpdatePolicy)
+	}
+	tag, ok := ref.(name.Tag)
+	return ok && tag.TagStr() == push.Tag, nil
+}
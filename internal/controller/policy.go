@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/artifakt-io/demo-controller/internal/policy"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	informers "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/application/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WatchPolicies evaluates the ApplicationPolicies of informer on each
+// reconcile. Every application is requeued when a policy changes.
+func (c *Controller) WatchPolicies(informer informers.ApplicationPolicyInformer) {
+	c.Policies = policy.NewEvaluator(informer.Lister())
+	c.PoliciesSynced = informer.Informer().HasSynced
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) { c.enqueueAllApplications() },
+		UpdateFunc: func(old, new interface{}) {
+			if old.(*v1.ApplicationPolicy).ResourceVersion == new.(*v1.ApplicationPolicy).ResourceVersion {
+				return
+			}
+			c.enqueueAllApplications()
+		},
+		DeleteFunc: func(interface{}) { c.enqueueAllApplications() },
+	})
+}
+
+func (c *Controller) enqueueAllApplications() {
+	apps, err := c.ApplicationsLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, app := range apps {
+		c.enqueueApplication(app)
+	}
+}
+
+// syncPolicies evaluates the policies against app and the workload rendered
+// for it, and reports their violations in the PolicyViolation condition. It
+// returns whether a violated policy is enforced, in which case the workload
+// must not be reconciled. app is updated with the persisted status.
+func (c *Controller) syncPolicies(ctx context.Context, app *v1.Application) (bool, error) {
+	if c.Policies == nil {
+		return false, nil
+	}
+	violations, err := c.Policies.Evaluate(app, NewDeployment(app))
+	if err != nil {
+		return false, fmt.Errorf("unable to evaluate policies: %w", err)
+	}
+	enforced := violations.Enforced()
+
+	previous := meta.FindStatusCondition(app.Status.Conditions, v1.ApplicationPolicyViolation)
+	status := app.Status.DeepCopy()
+	if len(violations) == 0 {
+		meta.RemoveStatusCondition(&status.Conditions, v1.ApplicationPolicyViolation)
+	} else {
+		reason := "Warned"
+		if len(enforced) > 0 {
+			reason = "Enforced"
+		}
+		c.setCondition(status, metav1.Condition{
+			Type:               v1.ApplicationPolicyViolation,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: app.Generation,
+			Reason:             reason,
+			Message:            violations.String(),
+		})
+	}
+
+	updated, err := c.writeApplicationStatus(ctx, app, status)
+	if err != nil {
+		return false, err
+	}
+	app.Status = updated.Status
+	app.ResourceVersion = updated.ResourceVersion
+
+	if len(violations) > 0 && (previous == nil || previous.Message != violations.String()) {
+		if len(enforced) > 0 {
+			c.Recorder.Eventf(app, corev1.EventTypeWarning, ErrPolicyViolation, MessagePolicyViolation, violations.String())
+		} else {
+			c.Recorder.Eventf(app, corev1.EventTypeWarning, WarnPolicyViolation, MessagePolicyWarning, violations.String())
+		}
+	}
+	return len(enforced) > 0, nil
+}
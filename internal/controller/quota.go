@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"github.com/artifakt-io/demo-controller/internal/quota"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WatchQuotas enforces the ApplicationQuotas of the informers of scopes when
+// a workload is scaled up, and reports the usage of their namespace in their
+// status. The usage counts the QuotaApplications of scopes, so that the
+// controllers sharing a namespace count the same applications. The
+// applications of a namespace are requeued when the spec of one of its quotas
+// changes, and those blocked by a quota when its usage changes. It must be
+// called once Defaulting is set, and after WatchTemplates.
+func (c *Controller) WatchQuotas(scopes ...Informers) {
+	informer := func(scope Informers) cache.SharedIndexInformer { return scope.ApplicationQuotas.Informer() }
+	applications := func(scope Informers) cache.SharedIndexInformer {
+		if scope.QuotaApplications != nil {
+			return scope.QuotaApplications.Informer()
+		}
+		return scope.Applications.Informer()
+	}
+	quotas := listers.NewApplicationQuotaLister(indexerOf(scopes, informer))
+	c.Quotas = quota.NewChecker(quotas, listers.NewApplicationLister(indexerOf(scopes, applications)), NewDeployment)
+	c.Quotas.Defaulting = c.Defaulting
+	c.Quotas.Templates = c.Templates
+	quotasSynced, applicationsSynced := hasSynced(scopes, informer), hasSynced(scopes, applications)
+	c.QuotasSynced = func() bool { return quotasSynced() && applicationsSynced() }
+	for _, scope := range scopes {
+		informer(scope).AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { c.enqueueNamespace(obj.(*v1.ApplicationQuota).Namespace, nil) },
+			UpdateFunc: func(old, new interface{}) {
+				oldQuota, newQuota := old.(*v1.ApplicationQuota), new.(*v1.ApplicationQuota)
+				if oldQuota.Generation != newQuota.Generation {
+					c.enqueueNamespace(newQuota.Namespace, nil)
+					return
+				}
+				// The usage may have been freed by the applications of
+				// another controller.
+				if !equality.Semantic.DeepEqual(oldQuota.Status.Used, newQuota.Status.Used) {
+					c.enqueueNamespace(newQuota.Namespace, quotaExceeded)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				if applicationQuota, ok := obj.(*v1.ApplicationQuota); ok {
+					c.enqueueNamespace(applicationQuota.Namespace, nil)
+				}
+			},
+		})
+	}
+}
+
+// enqueueNamespace enqueues the applications of namespace matching filter,
+// or all of them when filter is nil.
+func (c *Controller) enqueueNamespace(namespace string, filter func(*v1.Application) bool) {
+	apps, err := c.ApplicationsLister.Applications(namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, app := range apps {
+		if filter == nil || filter(app) {
+			c.enqueueApplication(app)
+		}
+	}
+}
+
+// syncQuota checks the workload of app against the ApplicationQuotas of its
+// namespace, and reports the limits a scale-up would exceed in the
+// QuotaExceeded condition. It returns whether the workload must not be
+// reconciled. app is updated with the persisted status.
+func (c *Controller) syncQuota(ctx context.Context, app *v1.Application) (bool, error) {
+	if c.Quotas == nil {
+		return false, nil
+	}
+	// The workload as currently deployed, which is not counted again.
+	var deployed v1.ApplicationQuotaUsage
+	if app.Status.DeploymentRefName != "" {
+		deployment, err := c.getDeployment(ctx, app.Status.DeploymentRefNamespace, app.Status.DeploymentRefName)
+		if err == nil {
+			deployed = quota.WorkloadUsage(deployment)
+		} else if !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	exceeded, err := c.Quotas.Check(app, deployed)
+	if err != nil {
+		return false, err
+	}
+
+	previous := meta.FindStatusCondition(app.Status.Conditions, v1.ApplicationQuotaExceeded)
+	status := app.Status.DeepCopy()
+	if len(exceeded) == 0 {
+		meta.RemoveStatusCondition(&status.Conditions, v1.ApplicationQuotaExceeded)
+	} else {
+		c.setCondition(status, metav1.Condition{
+			Type:               v1.ApplicationQuotaExceeded,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: app.Generation,
+			Reason:             "ScaleUpBlocked",
+			Message:            exceeded.String(),
+		})
+	}
+	updated, err := c.writeApplicationStatus(ctx, app, status)
+	if err != nil {
+		return false, err
+	}
+	app.Status = updated.Status
+	app.ResourceVersion = updated.ResourceVersion
+
+	if len(exceeded) > 0 && (previous == nil || previous.Message != exceeded.String()) {
+		c.Recorder.Eventf(app, corev1.EventTypeWarning, ErrQuotaExceeded, MessageQuotaExceeded, exceeded.String())
+	}
+	if err := c.syncQuotaUsage(ctx, app.Namespace); err != nil {
+		return false, err
+	}
+	return len(exceeded) > 0, nil
+}
+
+// syncQuotaUsage reports the usage of the applications of namespace in the
+// status of its ApplicationQuotas. When it changes, the applications whose
+// scale-up is blocked are requeued.
+func (c *Controller) syncQuotaUsage(ctx context.Context, namespace string) error {
+	if c.Quotas == nil {
+		return nil
+	}
+	quotas, err := c.Quotas.Quotas.ApplicationQuotas(namespace).List(labels.Everything())
+	if err != nil || len(quotas) == 0 {
+		return err
+	}
+	usage, err := c.Quotas.Usage(namespace)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, applicationQuota := range quotas {
+		if equality.Semantic.DeepEqual(applicationQuota.Status.Used, usage) {
+			continue
+		}
+		quotaCopy := applicationQuota.DeepCopy()
+		quotaCopy.Status.Used = usage
+		if err := c.updateQuotaStatus(ctx, applicationQuota, quotaCopy); err != nil {
+			return err
+		}
+		changed = true
+	}
+	if changed {
+		c.enqueueNamespace(namespace, quotaExceeded)
+	}
+	return nil
+}
+
+// quotaExceeded returns whether the scale-up of app is blocked by a quota.
+func quotaExceeded(app *v1.Application) bool {
+	return meta.IsStatusConditionTrue(app.Status.Conditions, v1.ApplicationQuotaExceeded)
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"sort"
+)
+
+// defaultRevisionHistoryLimit is the number of revisions kept when the
+// application does not set RevisionHistoryLimit.
+const defaultRevisionHistoryLimit = 10
+
+// revisionTemplate returns the part of spec recorded in revisions, leaving
+// out the fields which drive the controller rather than the workload.
+func revisionTemplate(spec *v1.ApplicationSpec) v1.ApplicationSpec {
+	template := spec.DeepCopy()
+	template.Suspend = false
+	template.ScaleToZeroOnSuspend = false
+	template.RevisionHistoryLimit = nil
+	template.RollbackTo = nil
+	if template.Rollout != nil {
+		template.Rollout.Promote = false
+	}
+	return *template
+}
+
+// listRevisions returns the revisions of app sorted by revision number.
+func (c *Controller) listRevisions(ctx context.Context, app *v1.Application) ([]*v1.ApplicationRevision, error) {
+	selector := labels.SelectorFromSet(labels.Set{v1.ApplicationNameLabel: app.Name})
+	revisions, err := c.ApplicationRevisionsLister.ApplicationRevisions(app.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := revisions[:0]
+	for _, revision := range revisions {
+		if metav1.IsControlledBy(revision, app) {
+			owned = append(owned, revision)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[i].Spec.Revision < owned[j].Spec.Revision
+	})
+	return owned, nil
+}
+
+// NewApplicationRevision returns the revision snapshotting the spec of app.
+func NewApplicationRevision(app *v1.Application, revision int64) *v1.ApplicationRevision {
+	return &v1.ApplicationRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", app.Name, revision),
+			Namespace: app.Namespace,
+			Labels: map[string]string{
+				v1.ApplicationNameLabel: app.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(app, v1.SchemeGroupVersion.WithKind("Application")),
+			},
+		},
+		Spec: v1.ApplicationRevisionSpec{
+			Revision: revision,
+			Template: revisionTemplate(&app.Spec),
+		},
+	}
+}
+
+// recordRevision records the spec of app, which has just been rolled out
+// successfully, unless the latest revision already holds it. The spec is the
+// one stored, without its template, so that a rollback restores what was
+// written. A revision already created but missed by the lister is reused.
+// Revisions beyond the history limit are pruned.
+func (c *Controller) recordRevision(ctx context.Context, app *v1.Application, status *v1.ApplicationStatus) error {
+	revisions, err := c.listRevisions(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	stored := c.forSpecUpdate(app)
+	template := revisionTemplate(&stored.Spec)
+	if n := len(revisions); n > 0 && equality.Semantic.DeepEqual(revisions[n-1].Spec.Template, template) {
+		status.CurrentRevision = revisions[n-1].Spec.Revision
+	} else {
+		next := int64(1)
+		if n > 0 {
+			next = revisions[n-1].Spec.Revision + 1
+		}
+		desired := NewApplicationRevision(stored, next)
+		revision, err := c.createRevision(ctx, desired)
+		switch {
+		case errors.IsAlreadyExists(err):
+			// The lister missed the revision recorded by a previous reconcile.
+			revision, err = c.getRevision(ctx, desired.Namespace, desired.Name)
+			if err != nil {
+				return err
+			}
+			if !metav1.IsControlledBy(revision, app) {
+				return fmt.Errorf("revision %s/%s is not controlled by application %s", revision.Namespace, revision.Name, app.Name)
+			}
+		case err != nil:
+			return err
+		default:
+			c.Recorder.Eventf(app, corev1.EventTypeNormal, SuccessRevisionRecorded, MessageRevisionRecorded, next)
+		}
+		revisions = append(revisions, revision)
+		status.CurrentRevision = next
+	}
+
+	limit := defaultRevisionHistoryLimit
+	if app.Spec.RevisionHistoryLimit != nil {
+		limit = int(*app.Spec.RevisionHistoryLimit)
+	}
+	for i := 0; i < len(revisions)-limit; i++ {
+		if revisions[i].Spec.Revision == status.CurrentRevision {
+			continue
+		}
+		klog.V(4).Infof("Pruning revision %s/%s", revisions[i].Namespace, revisions[i].Name)
+		err := c.deleteRevision(ctx, app.Namespace, revisions[i].Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollback restores the spec recorded in the revision app.Spec.RollbackTo
+// points to. The fields driving the controller are kept as they are.
+func (c *Controller) rollback(ctx context.Context, app *v1.Application) error {
+	target := *app.Spec.RollbackTo
+	revisions, err := c.listRevisions(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	appCopy := c.forSpecUpdate(app)
+	appCopy.Spec.RollbackTo = nil
+	found := false
+	for _, revision := range revisions {
+		if revision.Spec.Revision == target {
+			spec := revision.Spec.Template.DeepCopy()
+			spec.Suspend = app.Spec.Suspend
+			spec.ScaleToZeroOnSuspend = app.Spec.ScaleToZeroOnSuspend
+			spec.RevisionHistoryLimit = app.Spec.RevisionHistoryLimit
+			appCopy.Spec = *spec
+			found = true
+			break
+		}
+	}
+
+	if _, err := c.updateApplication(ctx, app, appCopy); err != nil {
+		return err
+	}
+	if !found {
+		c.Recorder.Eventf(app, corev1.EventTypeWarning, ErrRevisionNotFound, MessageRevisionNotFound, target)
+		return nil
+	}
+	c.Recorder.Eventf(app, corev1.EventTypeNormal, SuccessRollback, MessageRollback, target)
+	return nil
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"fmt"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+const (
+	reasonRolloutInProgress        = "RolloutInProgress"
+	reasonRolloutComplete          = "RolloutComplete"
+	reasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+)
+
+type rolloutState int
+
+// rolloutPlan is the outcome of a step of a progressive rollout strategy.
+type rolloutPlan struct {
+	// stableImage and stableReplicas are the image and replica count of the
+	// deployment serving the application at this step.
+	stableImage    string
+	stableReplicas int32
+	// inFlight is set until the new image is promoted.
+	inFlight     bool
+	requeueAfter time.Duration
+	// promoted is set when the rollout consumed spec.rollout.promote.
+	promoted bool
+	reason   string
+	message  string
+}
+
+const (
+	rolloutInProgress rolloutState = iota
+	rolloutComplete
+	rolloutFailed
+)
+
+// deploymentRolloutState tells how far the rollout of deployment went, in the
+// same way `kubectl rollout status` does.
+func deploymentRolloutState(deployment *appsv1.Deployment) (rolloutState, string) {
+	if deployment.Generation > deployment.Status.ObservedGeneration {
+		return rolloutInProgress, "Waiting for the deployment spec update to be observed"
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == reasonProgressDeadlineExceeded {
+			return rolloutFailed, fmt.Sprintf("Deployment %q exceeded its progress deadline", deployment.Name)
+		}
+	}
+
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	switch {
+	case deployment.Status.UpdatedReplicas < desired:
+		return rolloutInProgress, fmt.Sprintf("%d out of %d new replicas have been updated", deployment.Status.UpdatedReplicas, desired)
+	case deployment.Status.Replicas > deployment.Status.UpdatedReplicas:
+		return rolloutInProgress, fmt.Sprintf("%d old replicas are pending termination", deployment.Status.Replicas-deployment.Status.UpdatedReplicas)
+	case deployment.Status.AvailableReplicas < deployment.Status.UpdatedReplicas:
+		return rolloutInProgress, fmt.Sprintf("%d of %d updated replicas are available", deployment.Status.AvailableReplicas, deployment.Status.UpdatedReplicas)
+	}
+	return rolloutComplete, fmt.Sprintf("Deployment %q successfully rolled out", deployment.Name)
+}
+
+// setRolloutStatus reports the rollout of deployment into the status of app
+// and tells whether the rollout is still in flight.
+func (c *Controller) setRolloutStatus(app *v1.Application, status *v1.ApplicationStatus, deployment *appsv1.Deployment) bool {
+	status.ObservedGeneration = app.Generation
+	status.UpdatedReplicas = deployment.Status.UpdatedReplicas
+	status.ReadyReplicas = deployment.Status.ReadyReplicas
+
+	state, message := deploymentRolloutState(deployment)
+	reason := reasonRolloutComplete
+	switch state {
+	case rolloutInProgress:
+		reason = reasonRolloutInProgress
+	case rolloutFailed:
+		reason = reasonProgressDeadlineExceeded
+	}
+	c.setRolloutConditions(app, status, state, reason, message)
+	return state == rolloutInProgress
+}
+
+// setRolloutConditions sets the Progressing, Ready and Failed conditions of
+// status for a rollout in the given state.
+func (c *Controller) setRolloutConditions(app *v1.Application, status *v1.ApplicationStatus, state rolloutState, reason, message string) {
+	for _, condition := range []struct {
+		conditionType string
+		state         rolloutState
+	}{
+		{v1.ApplicationProgressing, rolloutInProgress},
+		{v1.ApplicationReady, rolloutComplete},
+		{v1.ApplicationFailed, rolloutFailed},
+	} {
+		conditionStatus := metav1.ConditionFalse
+		if condition.state == state {
+			conditionStatus = metav1.ConditionTrue
+		}
+		c.setCondition(status, metav1.Condition{
+			Type:               condition.conditionType,
+			Status:             conditionStatus,
+			ObservedGeneration: app.Generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+}
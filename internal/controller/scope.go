@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"fmt"
+	informers "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/application/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Informers are the informers of the resources watched by the controller in
+// one namespace, or in the whole cluster when Namespace is empty.
+type Informers struct {
+	Namespace string
+
+	Deployments          appsinformers.DeploymentInformer
+	Pods                 coreinformers.PodInformer
+	Services             coreinformers.ServiceInformer
+	Applications         informers.ApplicationInformer
+	ApplicationRevisions informers.ApplicationRevisionInformer
+	// ApplicationQuotas are only read by WatchQuotas.
+	ApplicationQuotas informers.ApplicationQuotaInformer
+	// QuotaApplications are the applications whose usage WatchQuotas counts,
+	// including those the application selector leaves to other controllers.
+	// Defaults to Applications.
+	QuotaApplications informers.ApplicationInformer
+	// ApplicationTemplates are only read by WatchTemplates.
+	ApplicationTemplates informers.ApplicationTemplateInformer
+	// Secrets are only read by WatchImages.
+	Secrets coreinformers.SecretInformer
+}
+
+// namespacedIndexer serves the reads of a lister from the informer cache of
+// the namespace they target. Objects of other namespaces are not found.
+type namespacedIndexer map[string]cache.Indexer
+
+func (n namespacedIndexer) namespaceOf(obj interface{}) string {
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return object.GetNamespace()
+}
+
+func (n namespacedIndexer) Add(obj interface{}) error    { return errReadOnlyIndexer }
+func (n namespacedIndexer) Update(obj interface{}) error { return errReadOnlyIndexer }
+func (n namespacedIndexer) Delete(obj interface{}) error { return errReadOnlyIndexer }
+func (n namespacedIndexer) Resync() error                { return errReadOnlyIndexer }
+
+func (n namespacedIndexer) Replace([]interface{}, string) error {
+	return errReadOnlyIndexer
+}
+
+func (n namespacedIndexer) AddIndexers(cache.Indexers) error {
+	return errReadOnlyIndexer
+}
+
+var errReadOnlyIndexer = fmt.Errorf("the indexer of several namespaces is read-only")
+
+func (n namespacedIndexer) List() []interface{} {
+	var items []interface{}
+	for _, indexer := range n {
+		items = append(items, indexer.List()...)
+	}
+	return items
+}
+
+func (n namespacedIndexer) ListKeys() []string {
+	var keys []string
+	for _, indexer := range n {
+		keys = append(keys, indexer.ListKeys()...)
+	}
+	return keys
+}
+
+func (n namespacedIndexer) Get(obj interface{}) (interface{}, bool, error) {
+	indexer, ok := n[n.namespaceOf(obj)]
+	if !ok {
+		return nil, false, nil
+	}
+	return indexer.Get(obj)
+}
+
+func (n namespacedIndexer) GetByKey(key string) (interface{}, bool, error) {
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	indexer, ok := n[namespace]
+	if !ok {
+		return nil, false, nil
+	}
+	return indexer.GetByKey(key)
+}
+
+func (n namespacedIndexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	if indexName == cache.NamespaceIndex {
+		indexer, ok := n[n.namespaceOf(obj)]
+		if !ok {
+			return nil, nil
+		}
+		return indexer.Index(indexName, obj)
+	}
+	var items []interface{}
+	for _, indexer := range n {
+		indexed, err := indexer.Index(indexName, obj)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, indexed...)
+	}
+	return items, nil
+}
+
+func (n namespacedIndexer) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	var keys []string
+	for _, indexer := range n {
+		indexed, err := indexer.IndexKeys(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, indexed...)
+	}
+	return keys, nil
+}
+
+func (n namespacedIndexer) ListIndexFuncValues(indexName string) []string {
+	var values []string
+	for _, indexer := range n {
+		values = append(values, indexer.ListIndexFuncValues(indexName)...)
+	}
+	return values
+}
+
+func (n namespacedIndexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	if indexName == cache.NamespaceIndex {
+		indexer, ok := n[indexedValue]
+		if !ok {
+			return nil, nil
+		}
+		return indexer.ByIndex(indexName, indexedValue)
+	}
+	var items []interface{}
+	for _, indexer := range n {
+		indexed, err := indexer.ByIndex(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, indexed...)
+	}
+	return items, nil
+}
+
+func (n namespacedIndexer) GetIndexers() cache.Indexers {
+	for _, indexer := range n {
+		return indexer.GetIndexers()
+	}
+	return cache.Indexers{}
+}
+
+// indexerOf returns the indexer serving the informers returned by informer
+// for every scope.
+func indexerOf(scopes []Informers, informer func(Informers) cache.SharedIndexInformer) cache.Indexer {
+	if len(scopes) == 1 {
+		return informer(scopes[0]).GetIndexer()
+	}
+	indexer := namespacedIndexer{}
+	for _, scope := range scopes {
+		indexer[scope.Namespace] = informer(scope).GetIndexer()
+	}
+	return indexer
+}
+
+// hasSynced returns whether the informers returned by informer for every
+// scope have synced.
+func hasSynced(scopes []Informers, informer func(Informers) cache.SharedIndexInformer) cache.InformerSynced {
+	var synced []cache.InformerSynced
+	for _, scope := range scopes {
+		synced = append(synced, informer(scope).HasSynced)
+	}
+	return func() bool {
+		for _, s := range synced {
+			if !s() {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"strconv"
+)
+
+// ReplicasBeforeSuspendAnnotation records on a Deployment the replica count it
+// had before being scaled to zero by a suspension.
+const ReplicasBeforeSuspendAnnotation = "demo-controller/replicas-before-suspend"
+
+func isSuspended(app *v1.Application) bool {
+	return app.Spec.Suspend || app.Annotations[v1.PausedAnnotation] == "true"
+}
+
+// syncSuspended short-circuits the reconciliation of a suspended application.
+// The workload is left as is, unless ScaleToZeroOnSuspend is set.
+func (c *Controller) syncSuspended(ctx context.Context, app *v1.Application) error {
+	reason, message := "Suspended", MessageSuspended
+	if !app.Spec.Suspend {
+		reason = "Paused"
+	}
+	if app.Spec.ScaleToZeroOnSuspend {
+		message = MessageSuspendedScale
+		if err := c.scaleToZero(ctx, app); err != nil {
+			return err
+		}
+	}
+
+	suspended := meta.IsStatusConditionTrue(app.Status.Conditions, v1.ApplicationSuspended)
+	status := app.Status.DeepCopy()
+	c.setCondition(status, metav1.Condition{
+		Type:               v1.ApplicationSuspended,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: app.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+
+	if _, err := c.writeApplicationStatus(ctx, app, status); err != nil {
+		return err
+	}
+	if !suspended {
+		c.Recorder.Event(app, corev1.EventTypeNormal, SuccessSuspended, message)
+	}
+	return nil
+}
+
+// scaleToZero scales the deployment of app to zero replicas, remembering its
+// current replica count in the ReplicasBeforeSuspendAnnotation.
+func (c *Controller) scaleToZero(ctx context.Context, app *v1.Application) error {
+	deployment, err := c.getDeployment(ctx, app.Status.DeploymentRefNamespace, app.Status.DeploymentRefName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 {
+		return nil
+	}
+
+	deploymentCopy := deployment.DeepCopy()
+	if _, ok := deploymentCopy.Annotations[ReplicasBeforeSuspendAnnotation]; !ok {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deploymentCopy.Annotations == nil {
+			deploymentCopy.Annotations = map[string]string{}
+		}
+		deploymentCopy.Annotations[ReplicasBeforeSuspendAnnotation] = strconv.Itoa(int(replicas))
+	}
+	zero := int32(0)
+	deploymentCopy.Spec.Replicas = &zero
+
+	klog.V(4).Infof("Scaling deployment %s/%s to zero replicas", deployment.Namespace, deployment.Name)
+	_, err = c.updateDeployment(ctx, deploymentCopy)
+	return err
+}
+
+// resumeDeployment restores the replica count a deployment had before it was
+// scaled to zero by a suspension. The replica count set by the stored spec of
+// the application, specReplicas, takes precedence over the remembered one.
+func (c *Controller) resumeDeployment(ctx context.Context, deployment *appsv1.Deployment, specReplicas *int32) (*appsv1.Deployment, error) {
+	deploymentCopy := deployment.DeepCopy()
+	remembered := deploymentCopy.Annotations[ReplicasBeforeSuspendAnnotation]
+	delete(deploymentCopy.Annotations, ReplicasBeforeSuspendAnnotation)
+
+	if specReplicas != nil {
+		deploymentCopy.Spec.Replicas = specReplicas
+	} else {
+		replicas, err := strconv.ParseInt(remembered, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation on deployment %s/%s: %s", ReplicasBeforeSuspendAnnotation, deployment.Namespace, deployment.Name, err.Error())
+		}
+		r := int32(replicas)
+		deploymentCopy.Spec.Replicas = &r
+	}
+
+	klog.V(4).Infof("Restoring deployment %s/%s to %d replicas", deployment.Namespace, deployment.Name, *deploymentCopy.Spec.Replicas)
+	return c.updateDeployment(ctx, deploymentCopy)
+}
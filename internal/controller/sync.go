@@ -4,66 +4,243 @@ import (
 	"context"
 	"fmt"
 	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 )
 
-func (c *Controller) SyncHandler(key string) error {
+// SyncHandler reconciles the application key, tracing the reconcile as a
+// span. The API calls of the reconcile are cancelled with ctx. In dry-run
+// mode, the report of the application is replaced by the changes of a
+// successful reconcile.
+func (c *Controller) SyncHandler(ctx context.Context, key string) error {
+	ctx, span := c.Tracer.Start(ctx, "SyncHandler", trace.WithAttributes(attribute.String("application.key", key)))
+	var changes *dryRunChanges
+	if c.DryRun != nil {
+		ctx, changes = withDryRunChanges(ctx, key)
+	}
+	err := c.syncHandler(ctx, key)
+	if changes != nil && err == nil {
+		c.DryRun.set(key, changes.changes)
+	}
+	endSpan(span, err)
+	return err
+}
+
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
 		return nil
 	}
-	app, err := c.ApplicationsLister.Applications(namespace).Get(name)
+	app, err := c.getApplication(ctx, namespace, name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			utilruntime.HandleError(fmt.Errorf("Application '%s' in work queue no longer exists", key))
-			return nil
+			c.resolvedImages.Delete(key)
+			// The quotas of the namespace no longer count it.
+			return c.syncQuotaUsage(ctx, namespace)
 		}
 
 		return err
 	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("application.generation", app.Generation))
+	// Applications admitted without the defaulting webhook are reconciled
+	// with the same effective spec.
+	app = app.DeepCopy()
+	if blocked, err := c.applyTemplate(ctx, app); err != nil || blocked {
+		return err
+	}
+	// Replicas left unset by the stored spec only default the replica count
+	// of a new Deployment, so that a resume keeps the remembered one.
+	specReplicas := app.Spec.Replicas
+	v1.DefaultSpec(&app.Spec, c.Defaulting)
+
+	if app.Spec.RollbackTo != nil {
+		// The spec update triggers a new sync of the restored spec.
+		return c.rollback(ctx, app)
+	}
+
+	if isSuspended(app) {
+		return c.syncSuspended(ctx, app)
+	}
+
+	if updated, err := c.updateImage(ctx, key, app); err != nil || updated {
+		return err
+	}
+
+	if blocked, err := c.syncPolicies(ctx, app); err != nil || blocked {
+		return err
+	}
+
+	if blocked, err := c.syncQuota(ctx, app); err != nil || blocked {
+		return err
+	}
+
+	if err := c.pinImage(ctx, key, app); err != nil {
+		return err
+	}
 
-	deployment, err := c.DeploymentsLister.Deployments(app.Status.DeploymentRefNamespace).Get(app.Status.DeploymentRefName)
+	if blocked, err := c.verifyImage(ctx, key, app); err != nil || blocked {
+		return err
+	}
+
+	if usesBlueGreen(app) {
+		return c.syncBlueGreen(ctx, key, app)
+	}
+
+	image := workloadImage(app)
+	deployment, err := c.getDeployment(ctx, app.Status.DeploymentRefNamespace, app.Status.DeploymentRefName)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			deployment, err = c.Kubeclientset.AppsV1().Deployments(app.Namespace).Create(context.TODO(), NewDeployment(app), metav1.CreateOptions{})
-		} else {
+			deployment, err = c.createDeployment(ctx, newDeployment(app, image))
+		}
+		if err != nil {
 			return err
 		}
 	}
 
-
-	if app.Spec.Replicas != nil && *app.Spec.Replicas != *deployment.Spec.Replicas {
-		klog.V(4).Infof("Application %s replicas: %d, deployment replicas: %d", name, *app.Spec.Replicas, *deployment.Spec.Replicas)
-		deployment, err = c.Kubeclientset.AppsV1().Deployments(app.Namespace).Update(context.TODO(), NewDeployment(app), metav1.UpdateOptions{})
+	if _, ok := deployment.Annotations[ReplicasBeforeSuspendAnnotation]; ok {
+		deployment, err = c.resumeDeployment(ctx, deployment, specReplicas)
+		if err != nil {
+			return err
+		}
+	}
+	if specReplicas == nil {
+		app.Spec.Replicas = deployment.Spec.Replicas
 	}
 
-	container := mainContainerFromDeploymentTemplate(deployment)
-	if app.Spec.ImageName != container.Image {
-		klog.V(4).Infof("Application %s image: %s, deployment image: %d", name, app.Spec.ImageName, container.Image)
-		deployment, err = c.Kubeclientset.AppsV1().Deployments(app.Namespace).Update(context.TODO(), NewDeployment(app), metav1.UpdateOptions{})
+	status := app.Status.DeepCopy()
+	desired := newDeployment(app, image)
+	var plan *rolloutPlan
+	if usesCanary(app) {
+		plan, err = c.syncCanary(ctx, app, status, deployment)
+		if err != nil {
+			return err
+		}
+		desired = newDeployment(app, plan.stableImage)
+		desired.Spec.Replicas = &plan.stableReplicas
 	}
 
+	deployment, err = c.syncDeployment(ctx, deployment, desired)
 	if err != nil {
 		return err
 	}
 
-	err = c.updateApplicationStatus(app, deployment)
+	status.DeploymentRefNamespace = deployment.Namespace
+	status.DeploymentRefName = deployment.Name
+	return c.completeSync(ctx, key, app, status, deployment, plan)
+}
+
+// completeSync reports the rollout of deployment, which serves the
+// application, into status. The status is then persisted and the application
+// requeued while its rollout is in flight.
+func (c *Controller) completeSync(ctx context.Context, key string, app *v1.Application, status *v1.ApplicationStatus, deployment *appsv1.Deployment, plan *rolloutPlan) error {
+	inFlight := c.setRolloutStatus(app, status, deployment)
+	if plan != nil && plan.inFlight {
+		c.setRolloutConditions(app, status, rolloutInProgress, plan.reason, plan.message)
+		inFlight = true
+	}
+	if c.AutoRollback {
+		rolledBack, err := c.autoRollback(ctx, app, status, deployment)
+		if err != nil {
+			return err
+		}
+		inFlight = inFlight || rolledBack
+	}
+	if meta.IsStatusConditionTrue(status.Conditions, v1.ApplicationReady) && !isRolledBack(app) {
+		if err := c.recordRevision(ctx, app, status); err != nil {
+			return err
+		}
+	}
+
+	updated, err := c.updateApplicationStatus(ctx, app, status)
 	if err != nil {
 		return err
 	}
 
-	c.Recorder.Event(app, corev1.EventTypeNormal, SuccessSynced, MessageResourceSynced)
+	if plan != nil && plan.promoted {
+		if err := c.clearPromote(ctx, updated); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case plan != nil && plan.requeueAfter > 0:
+		c.Workqueue.AddAfter(key, plan.requeueAfter)
+	case inFlight:
+		c.Workqueue.AddAfter(key, c.RolloutRequeueDelay)
+	}
 	return nil
 }
 
+// syncDeployment updates deployment to the replica count, image and pod
+// settings of desired when they drifted apart.
+func (c *Controller) syncDeployment(ctx context.Context, deployment, desired *appsv1.Deployment) (*appsv1.Deployment, error) {
+	image := mainContainerFromDeploymentTemplate(deployment).Image
+	desiredImage := mainContainerFromDeploymentTemplate(desired).Image
+	replicasDrifted := desired.Spec.Replicas != nil && (deployment.Spec.Replicas == nil || *desired.Spec.Replicas != *deployment.Spec.Replicas)
+	if !replicasDrifted && image == desiredImage && !podSettingsDrifted(deployment, desired) {
+		return deployment, nil
+	}
+
+	klog.V(4).Infof("Deployment %s/%s replicas: %v, image: %s, desired replicas: %v, image: %s",
+		deployment.Namespace, deployment.Name, deployment.Spec.Replicas, image, desired.Spec.Replicas, desiredImage)
+	return c.updateDeployment(ctx, desired)
+}
+
+// podSettingsDrifted returns whether the labels, scheduling, resources or
+// probes of the pods of deployment differ from those of desired, as set by
+// the spec of the application or its template.
+func podSettingsDrifted(deployment, desired *appsv1.Deployment) bool {
+	pod, desiredPod := deployment.Spec.Template, desired.Spec.Template
+	container, desiredContainer := mainContainerFromDeploymentTemplate(deployment), mainContainerFromDeploymentTemplate(desired)
+	return !equality.Semantic.DeepEqual(pod.Labels, desiredPod.Labels) ||
+		!equality.Semantic.DeepEqual(pod.Spec.NodeSelector, desiredPod.Spec.NodeSelector) ||
+		!equality.Semantic.DeepEqual(pod.Spec.Tolerations, desiredPod.Spec.Tolerations) ||
+		!equality.Semantic.DeepEqual(pod.Spec.Affinity, desiredPod.Spec.Affinity) ||
+		!equality.Semantic.DeepEqual(container.Resources, desiredContainer.Resources) ||
+		!equality.Semantic.DeepEqual(container.LivenessProbe, desiredContainer.LivenessProbe) ||
+		!equality.Semantic.DeepEqual(container.ReadinessProbe, desiredContainer.ReadinessProbe)
+}
 
+// defaultProbe returns a copy of probe with the defaults the API server
+// sets, so that the probes of a deployment do not drift from the spec.
+func defaultProbe(probe *corev1.Probe) *corev1.Probe {
+	if probe == nil {
+		return nil
+	}
+	probe = probe.DeepCopy()
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = 1
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = 1
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = 3
+	}
+	if probe.HTTPGet != nil {
+		if probe.HTTPGet.Path == "" {
+			probe.HTTPGet.Path = "/"
+		}
+		if probe.HTTPGet.Scheme == "" {
+			probe.HTTPGet.Scheme = corev1.URISchemeHTTP
+		}
+	}
+	return probe
+}
 
 func mainContainerFromDeploymentTemplate(deployment *appsv1.Deployment) corev1.Container {
 	for _, d := range deployment.Spec.Template.Spec.Containers {
@@ -74,10 +251,32 @@ func mainContainerFromDeploymentTemplate(deployment *appsv1.Deployment) corev1.C
 	return corev1.Container{}
 }
 
-func NewDeployment(app *v1.Application) *appsv1.Deployment {
-	labels := map[string]string{
+// selectorLabels returns the labels set on the pods of app.
+func selectorLabels(app *v1.Application) map[string]string {
+	return map[string]string{
 		"controller": app.Name,
 	}
+}
+
+// NewDeployment returns the deployment running the spec of app.
+func NewDeployment(app *v1.Application) *appsv1.Deployment {
+	return newDeployment(app, app.Spec.ImageName)
+}
+
+func newDeployment(app *v1.Application, image string) *appsv1.Deployment {
+	labels := selectorLabels(app)
+	// The selector labels take precedence over the labels of the spec.
+	podLabels := make(map[string]string, len(app.Spec.Labels)+len(labels))
+	for k, v := range app.Spec.Labels {
+		podLabels[k] = v
+	}
+	for k, v := range labels {
+		podLabels[k] = v
+	}
+	var resources corev1.ResourceRequirements
+	if app.Spec.Resources != nil {
+		resources = *app.Spec.Resources
+	}
 
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -94,13 +293,20 @@ func NewDeployment(app *v1.Application) *appsv1.Deployment {
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels: podLabels,
 				},
 				Spec: corev1.PodSpec{
+					ImagePullSecrets: app.Spec.ImagePullSecrets,
+					NodeSelector:     app.Spec.NodeSelector,
+					Tolerations:      app.Spec.Tolerations,
+					Affinity:         app.Spec.Affinity,
 					Containers: []corev1.Container{
 						{
-							Name:  "main",
-							Image: app.Spec.ImageName,
+							Name:           "main",
+							Image:          image,
+							Resources:      resources,
+							LivenessProbe:  defaultProbe(app.Spec.LivenessProbe),
+							ReadinessProbe: defaultProbe(app.Spec.ReadinessProbe),
 						},
 					},
 				},
@@ -109,15 +315,50 @@ func NewDeployment(app *v1.Application) *appsv1.Deployment {
 	}
 }
 
-func (c *Controller) updateApplicationStatus(app *v1.Application, deployment *appsv1.Deployment) error {
+// updateApplicationStatus persists the status of a reconciled application and
+// emits events for the transitions it carries.
+func (c *Controller) updateApplicationStatus(ctx context.Context, app *v1.Application, status *v1.ApplicationStatus) (*v1.Application, error) {
+	resumed := meta.FindStatusCondition(status.Conditions, v1.ApplicationSuspended) != nil
+	meta.RemoveStatusCondition(&status.Conditions, v1.ApplicationSuspended)
 
-	if app.Status.DeploymentRefNamespace != deployment.Namespace ||
-		app.Status.DeploymentRefName != deployment.Name {
-		appCopy := app.DeepCopy()
-		appCopy.Status.DeploymentRefNamespace = deployment.Namespace
-		appCopy.Status.DeploymentRefName = deployment.Name
-		_, err := c.ApplicationClientset.CloudestV1().Applications(appCopy.Namespace).UpdateStatus(context.TODO(), appCopy, metav1.UpdateOptions{})
-		return err
+	updated, err := c.writeApplicationStatus(ctx, app, status)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	if resumed {
+		c.Recorder.Event(app, corev1.EventTypeNormal, SuccessResumed, MessageResumed)
+	}
+	if !meta.IsStatusConditionTrue(app.Status.Conditions, v1.ApplicationFailed) && meta.IsStatusConditionTrue(status.Conditions, v1.ApplicationFailed) {
+		c.Recorder.Eventf(app, corev1.EventTypeWarning, ErrRolloutStalled, MessageRolloutStalled, app.Spec.ImageName)
+	}
+	if !meta.IsStatusConditionTrue(app.Status.Conditions, v1.ApplicationReady) && meta.IsStatusConditionTrue(status.Conditions, v1.ApplicationReady) {
+		c.Recorder.Event(app, corev1.EventTypeNormal, SuccessSynced, MessageResourceSynced)
+	}
+	return updated, nil
+}
+
+// writeApplicationStatus persists status on the status subresource of app,
+// skipping the API call when nothing changed. It returns the up to date
+// application.
+func (c *Controller) writeApplicationStatus(ctx context.Context, app *v1.Application, status *v1.ApplicationStatus) (*v1.Application, error) {
+	if equality.Semantic.DeepEqual(app.Status, *status) {
+		return app, nil
+	}
+	appCopy := app.DeepCopy()
+	appCopy.Status = *status
+	ctx, span := c.startSpan(ctx, "Applications.UpdateStatus", app.Namespace, app.Name)
+	updated, err := c.ApplicationClientset.CloudestV1().Applications(appCopy.Namespace).UpdateStatus(ctx, appCopy, c.updateOptions())
+	endSpan(span, err)
+	if err == nil {
+		c.recordChange(ctx, "update", "applications/status", app.Namespace, app.Name, app, updated)
+	}
+	return updated, err
+}
+
+// setCondition sets condition on status, stamping its transition time with
+// the controller clock.
+func (c *Controller) setCondition(status *v1.ApplicationStatus, condition metav1.Condition) {
+	condition.LastTransitionTime = metav1.NewTime(c.Clock.Now())
+	meta.SetStatusCondition(&status.Conditions, condition)
 }
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"context"
+	"github.com/artifakt-io/demo-controller/internal/template"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	informers "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/application/v1"
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WatchTemplates applies the ApplicationTemplates of the informers of scopes
+// and the ClusterApplicationTemplates of clusterTemplates to the spec of the
+// applications referring to them on each reconcile. A nil clusterTemplates
+// finds no ClusterApplicationTemplate, so that the cluster-scoped templates
+// need not be watched. The applications are indexed by template, so that the
+// dependents of a template are requeued when its spec changes. It must be
+// called before the informers start.
+func (c *Controller) WatchTemplates(clusterTemplates informers.ClusterApplicationTemplateInformer, scopes ...Informers) {
+	informer := func(scope Informers) cache.SharedIndexInformer { return scope.ApplicationTemplates.Informer() }
+	applications := func(scope Informers) cache.SharedIndexInformer { return scope.Applications.Informer() }
+	c.Templates = template.NewResolver(listers.NewApplicationTemplateLister(indexerOf(scopes, informer)), nil)
+	c.TemplatesSynced = hasSynced(scopes, informer)
+	if clusterTemplates != nil {
+		c.Templates.ClusterTemplates = clusterTemplates.Lister()
+		synced := c.TemplatesSynced
+		c.TemplatesSynced = func() bool { return synced() && clusterTemplates.Informer().HasSynced() }
+		clusterTemplates.Informer().AddEventHandler(c.templateEventHandler(v1.ClusterApplicationTemplateKind))
+	}
+
+	for _, scope := range scopes {
+		utilruntime.Must(applications(scope).AddIndexers(cache.Indexers{template.IndexName: template.IndexFunc}))
+		informer(scope).AddEventHandler(c.templateEventHandler(v1.ApplicationTemplateKind))
+	}
+	c.applicationIndexer = indexerOf(scopes, applications)
+}
+
+// templateEventHandler requeues the applications based on the templates of
+// kind whose spec changes.
+func (c *Controller) templateEventHandler(kind string) cache.ResourceEventHandler {
+	enqueue := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		object, err := meta.Accessor(obj)
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+		c.enqueueTemplateDependents(template.Key(kind, object.GetNamespace(), object.GetName()))
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			oldObject, oldErr := meta.Accessor(old)
+			newObject, newErr := meta.Accessor(new)
+			if oldErr == nil && newErr == nil && oldObject.GetGeneration() == newObject.GetGeneration() {
+				return
+			}
+			enqueue(new)
+		},
+		DeleteFunc: enqueue,
+	}
+}
+
+// enqueueTemplateDependents enqueues the applications indexed under the
+// template key.
+func (c *Controller) enqueueTemplateDependents(key string) {
+	apps, err := c.applicationIndexer.ByIndex(template.IndexName, key)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, app := range apps {
+		c.enqueueApplication(app)
+	}
+}
+
+// applyTemplate applies the template app refers to to its spec, and reports
+// a missing template in the TemplateNotFound condition. It returns whether
+// the workload must not be reconciled, as the spec of app lacks its template.
+// app is updated with the persisted status.
+func (c *Controller) applyTemplate(ctx context.Context, app *v1.Application) (bool, error) {
+	if c.Templates == nil {
+		return false, nil
+	}
+	err := c.Templates.Apply(app)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	previous := meta.FindStatusCondition(app.Status.Conditions, v1.ApplicationTemplateNotFound)
+	status := app.Status.DeepCopy()
+	if err == nil {
+		meta.RemoveStatusCondition(&status.Conditions, v1.ApplicationTemplateNotFound)
+	} else {
+		c.setCondition(status, metav1.Condition{
+			Type:               v1.ApplicationTemplateNotFound,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: app.Generation,
+			Reason:             "NotFound",
+			Message:            err.Error(),
+		})
+	}
+	updated, writeErr := c.writeApplicationStatus(ctx, app, status)
+	if writeErr != nil {
+		return false, writeErr
+	}
+	app.Status = updated.Status
+	app.ResourceVersion = updated.ResourceVersion
+
+	if err != nil && (previous == nil || previous.Message != err.Error()) {
+		c.Recorder.Eventf(app, corev1.EventTypeWarning, ErrTemplateNotFound, MessageTemplateNotFound, err.Error())
+	}
+	return err != nil, nil
+}
+
+// forSpecUpdate returns a copy of app to write a change of its spec on. The
+// spec is the one stored rather than the one the template of app was
+// applied to, so that the application keeps following its template.
+func (c *Controller) forSpecUpdate(app *v1.Application) *v1.Application {
+	appCopy := app.DeepCopy()
+	if app.Spec.TemplateRef == nil {
+		return appCopy
+	}
+	stored, err := c.ApplicationsLister.Applications(app.Namespace).Get(app.Name)
+	if err == nil && stored.Generation == app.Generation {
+		appCopy.Spec = *stored.Spec.DeepCopy()
+	}
+	return appCopy
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TracerName is the instrumentation name of the spans of the controller.
+const TracerName = "github.com/artifakt-io/demo-controller/internal/controller"
+
+// startSpan starts a child span of the reconcile traced in ctx for an
+// operation on the object namespace/name.
+func (c *Controller) startSpan(ctx context.Context, name, namespace, objectName string) (context.Context, trace.Span) {
+	return c.Tracer.Start(ctx, name, trace.WithAttributes(
+		attribute.String("k8s.namespace", namespace),
+		attribute.String("k8s.name", objectName),
+	))
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (c *Controller) getApplication(ctx context.Context, namespace, name string) (*v1.Application, error) {
+	_, span := c.startSpan(ctx, "ApplicationsLister.Get", namespace, name)
+	app, err := c.ApplicationsLister.Applications(namespace).Get(name)
+	endSpan(span, err)
+	return app, err
+}
+
+func (c *Controller) getDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	_, span := c.startSpan(ctx, "DeploymentsLister.Get", namespace, name)
+	deployment, err := c.DeploymentsLister.Deployments(namespace).Get(name)
+	endSpan(span, err)
+	return deployment, err
+}
+
+func (c *Controller) getRevision(ctx context.Context, namespace, name string) (*v1.ApplicationRevision, error) {
+	ctx, span := c.startSpan(ctx, "ApplicationRevisions.Get", namespace, name)
+	revision, err := c.ApplicationClientset.CloudestV1().ApplicationRevisions(namespace).Get(ctx, name, metav1.GetOptions{})
+	endSpan(span, err)
+	return revision, err
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The writes of the controller go through the helpers below, which trace
+// them and turn them into server-side dry-runs in dry-run mode.
+
+func (c *Controller) createDeployment(ctx context.Context, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	ctx, span := c.startSpan(ctx, "Deployments.Create", deployment.Namespace, deployment.Name)
+	created, err := c.Kubeclientset.AppsV1().Deployments(deployment.Namespace).Create(ctx, deployment, c.createOptions())
+	endSpan(span, err)
+	if err == nil {
+		c.recordChange(ctx, "create", "deployments", deployment.Namespace, deployment.Name, nil, created)
+	}
+	return created, err
+}
+
+func (c *Controller) updateDeployment(ctx context.Context, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	ctx, span := c.startSpan(ctx, "Deployments.Update", deployment.Namespace, deployment.Name)
+	updated, err := c.Kubeclientset.AppsV1().Deployments(deployment.Namespace).Update(ctx, deployment, c.updateOptions())
+	endSpan(span, err)
+	if err == nil && c.DryRun != nil {
+		current, _ := c.DeploymentsLister.Deployments(deployment.Namespace).Get(deployment.Name)
+		c.recordChange(ctx, "update", "deployments", deployment.Namespace, deployment.Name, current, updated)
+	}
+	return updated, err
+}
+
+func (c *Controller) deleteDeployment(ctx context.Context, namespace, name string) error {
+	ctx, span := c.startSpan(ctx, "Deployments.Delete", namespace, name)
+	err := c.Kubeclientset.AppsV1().Deployments(namespace).Delete(ctx, name, c.deleteOptions())
+	endSpan(span, err)
+	if err == nil {
+		c.recordChange(ctx, "delete", "deployments", namespace, name, nil, nil)
+	}
+	return err
+}
+
+func (c *Controller) createService(ctx context.Context, service *corev1.Service) error {
+	ctx, span := c.startSpan(ctx, "Services.Create", service.Namespace, service.Name)
+	created, err := c.Kubeclientset.CoreV1().Services(service.Namespace).Create(ctx, service, c.createOptions())
+	endSpan(span, err)
+	if err == nil {
+		c.recordChange(ctx, "create", "services", service.Namespace, service.Name, nil, created)
+	}
+	return err
+}
+
+func (c *Controller) updateService(ctx context.Context, current, service *corev1.Service) error {
+	ctx, span := c.startSpan(ctx, "Services.Update", service.Namespace, service.Name)
+	updated, err := c.Kubeclientset.CoreV1().Services(service.Namespace).Update(ctx, service, c.updateOptions())
+	endSpan(span, err)
+	if err == nil {
+		c.recordChange(ctx, "update", "services", service.Namespace, service.Name, current, updated)
+	}
+	return err
+}
+
+func (c *Controller) updateApplication(ctx context.Context, current, app *v1.Application) (*v1.Application, error) {
+	ctx, span := c.startSpan(ctx, "Applications.Update", app.Namespace, app.Name)
+	updated, err := c.ApplicationClientset.CloudestV1().Applications(app.Namespace).Update(ctx, app, c.updateOptions())
+	endSpan(span, err)
+	if err == nil {
+		c.recordChange(ctx, "update", "applications", app.Namespace, app.Name, current, updated)
+	}
+	return updated, err
+}
+
+func (c *Controller) createRevision(ctx context.Context, revision *v1.ApplicationRevision) (*v1.ApplicationRevision, error) {
+	ctx, span := c.startSpan(ctx, "ApplicationRevisions.Create", revision.Namespace, revision.Name)
+	created, err := c.ApplicationClientset.CloudestV1().ApplicationRevisions(revision.Namespace).Create(ctx, revision, c.createOptions())
+	endSpan(span, err)
+	if err == nil {
+		c.recordChange(ctx, "create", "applicationrevisions", revision.Namespace, revision.Name, nil, created)
+	}
+	return created, err
+}
+
+func (c *Controller) deleteRevision(ctx context.Context, namespace, name string) error {
+	ctx, span := c.startSpan(ctx, "ApplicationRevisions.Delete", namespace, name)
+	err := c.ApplicationClientset.CloudestV1().ApplicationRevisions(namespace).Delete(ctx, name, c.deleteOptions())
+	endSpan(span, err)
+	if err == nil {
+		c.recordChange(ctx, "delete", "applicationrevisions", namespace, name, nil, nil)
+	}
+	return err
+}
+
+func (c *Controller) updateQuotaStatus(ctx context.Context, current, applicationQuota *v1.ApplicationQuota) error {
+	ctx, span := c.startSpan(ctx, "ApplicationQuotas.UpdateStatus", applicationQuota.Namespace, applicationQuota.Name)
+	updated, err := c.ApplicationClientset.CloudestV1().ApplicationQuotas(applicationQuota.Namespace).UpdateStatus(ctx, applicationQuota, c.updateOptions())
+	endSpan(span, err)
+	if err == nil {
+		c.recordChange(ctx, "update", "applicationquotas/status", applicationQuota.Namespace, applicationQuota.Name, current, updated)
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+// Package crd generates the CustomResourceDefinitions of the API from the
+// types and validation markers of pkg/apis.
+package crd
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"runtime"
+	"sigs.k8s.io/controller-tools/pkg/crd"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+// Packages are the packages of the types the CRDs are generated from.
+const Packages = "github.com/artifakt-io/demo-controller/pkg/apis/application/..."
+
+// header starts the generated manifest.
+const header = "# Code generated by hack/crdgen from pkg/apis. DO NOT EDIT.\n"
+
+// conversions are the conversion strategies of the CRDs serving several
+// versions, which the types cannot express.
+var conversions = map[string]*apiextensionsv1.CustomResourceConversion{
+	"applications.cloudest.artifakt.io": {
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service: &apiextensionsv1.ServiceReference{
+					Namespace: "default",
+					Name:      "demo-controller-webhook",
+					Path:      stringPtr("/convert"),
+				},
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	},
+}
+
+func stringPtr(s string) *string { return &s }
+
+// Generate returns the CRDs of the kinds of packages as a multi-document
+// YAML manifest.
+func Generate(packages ...string) ([]byte, error) {
+	roots, err := loader.LoadRoots(packages...)
+	if err != nil {
+		return nil, err
+	}
+	fixTypesSizes(roots)
+	registry := &markers.Registry{}
+	if err := crdmarkers.Register(registry); err != nil {
+		return nil, err
+	}
+	parser := &crd.Parser{
+		Collector: &markers.Collector{Registry: registry},
+		Checker:   &loader.TypeChecker{NodeFilters: []loader.NodeFilter{crd.Generator{}.CheckFilter()}},
+	}
+	crd.AddKnownTypes(parser)
+	for _, root := range roots {
+		parser.NeedPackage(root)
+	}
+
+	metav1Pkg := crd.FindMetav1(roots)
+	if metav1Pkg == nil {
+		return nil, fmt.Errorf("no kinds found in %s", strings.Join(packages, ", "))
+	}
+	var manifest bytes.Buffer
+	manifest.WriteString(header)
+	for i, groupKind := range crd.FindKubeKinds(parser, metav1Pkg) {
+		parser.NeedCRDFor(groupKind, nil)
+		definition := parser.CustomResourceDefinitions[groupKind]
+		crd.FixTopLevelMetadata(definition)
+		definition.Spec.Conversion = conversions[definition.Name]
+
+		out, err := marshal(&definition)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			manifest.WriteString("---\n")
+		}
+		manifest.Write(out)
+	}
+	if err := packageErrors(roots); err != nil {
+		return nil, err
+	}
+	return manifest.Bytes(), nil
+}
+
+// fixTypesSizes sets the sizes of the packages reachable from roots to
+// those of the gc compiler. The go/packages version controller-tools
+// depends on reports them as a typed nil with recent toolchains, on which
+// go/types panics.
+func fixTypesSizes(roots []*loader.Package) {
+	sizes := types.SizesFor("gc", runtime.GOARCH)
+	seen := map[*loader.Package]bool{}
+	var fix func(pkg *loader.Package)
+	fix = func(pkg *loader.Package) {
+		if seen[pkg] {
+			return
+		}
+		seen[pkg] = true
+		if stdSizes, ok := pkg.TypesSizes.(*types.StdSizes); ok && stdSizes == nil {
+			pkg.TypesSizes = sizes
+		}
+		for _, imported := range pkg.Imports() {
+			fix(imported)
+		}
+	}
+	for _, root := range roots {
+		fix(root)
+	}
+}
+
+// marshal returns definition as YAML, without its status and empty
+// creation timestamp.
+func marshal(definition *apiextensionsv1.CustomResourceDefinition) ([]byte, error) {
+	out, err := yaml.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := yaml.Unmarshal(out, &object); err != nil {
+		return nil, err
+	}
+	delete(object, "status")
+	delete(object["metadata"].(map[string]interface{}), "creationTimestamp")
+	return yaml.Marshal(object)
+}
+
+// packageErrors returns the errors of the markers and types of roots. Type
+// errors are ignored: only the declarations the CRDs depend on are checked.
+func packageErrors(roots []*loader.Package) error {
+	var messages []string
+	for _, root := range roots {
+		for _, err := range root.Errors {
+			if err.Kind != packages.TypeError {
+				messages = append(messages, err.Error())
+			}
+		}
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("unable to generate the CRDs: %s", strings.Join(messages, "; "))
+	}
+	return nil
+}
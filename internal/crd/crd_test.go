@@ -0,0 +1,163 @@
+package crd_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/artifakt-io/demo-controller/internal/crd"
+	"io/ioutil"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+	"strings"
+	"testing"
+)
+
+const manifest = "../../manifests/crd.yaml"
+
+func TestCRDsAreUpToDate(t *testing.T) {
+	generated, err := crd.Generate(crd.Packages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkedIn, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(generated, checkedIn) {
+		t.Error("manifests/crd.yaml is out of date with pkg/apis, run make manifests")
+	}
+}
+
+// validate returns the errors of the OpenAPI schema and of the CEL rules
+// of version of the Applications CRD for the object in YAML.
+func validate(t *testing.T, version, object string) field.ErrorList {
+	raw, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var definition apiextensionsv1.CustomResourceDefinition
+	if err := yaml.Unmarshal(bytes.SplitN(raw, []byte("\n---\n"), 2)[0], &definition); err != nil {
+		t.Fatal(err)
+	}
+	var versionSchema *apiextensionsv1.CustomResourceValidation
+	for _, v := range definition.Spec.Versions {
+		if v.Name == version {
+			versionSchema = v.Schema
+		}
+	}
+	var internal apiextensions.CustomResourceValidation
+	if err := apiextensionsv1.Convert_v1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(versionSchema, &internal, nil); err != nil {
+		t.Fatal(err)
+	}
+	validator, _, err := validation.NewSchemaValidator(&internal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	structural, err := schema.NewStructural(internal.OpenAPIV3Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Decode the numbers as int64, as the API server does.
+	objectJSON, err := yaml.YAMLToJSON([]byte(object))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(objectJSON, &obj); err != nil {
+		t.Fatal(err)
+	}
+	errs := validation.ValidateCustomResource(nil, obj, validator)
+	celErrs, _ := cel.NewValidator(structural, cel.PerCallLimit).Validate(context.Background(), nil, structural, obj, nil, cel.RuntimeCELCostBudget)
+	return append(errs, celErrs...)
+}
+
+func TestSchemaValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		object  string
+		err     string
+	}{
+		{name: "example", version: "v1", object: mustRead(t, "../../examples/app.yaml")},
+		{name: "canary example", version: "v1", object: mustRead(t, "../../examples/canary.yaml")},
+		{name: "blue/green example", version: "v1", object: mustRead(t, "../../examples/bluegreen.yaml")},
+		{name: "v2 example", version: "v2", object: mustRead(t, "../../examples/app-v2.yaml")},
+		{
+			name:    "negative replicas",
+			version: "v1",
+			object:  "spec: {imageName: nginx, replicas: -1}",
+			err:     "spec.replicas",
+		},
+		{
+			name:    "unknown size",
+			version: "v1",
+			object:  "spec: {imageName: nginx, size: Huge}",
+			err:     "spec.size",
+		},
+		{
+			name:    "malformed image",
+			version: "v1",
+			object:  "spec: {imageName: ' nginx'}",
+			err:     "spec.imageName",
+		},
+		{
+			name:    "missing image",
+			version: "v1",
+			object:  "spec: {replicas: 1}",
+			err:     "spec.imageName",
+		},
+		{
+			name:    "canary step with weight and pause",
+			version: "v1",
+			object:  "spec: {imageName: nginx, rollout: {canary: {steps: [{setWeight: 50, pause: {}}]}}}",
+			err:     "exactly one of setWeight or pause must be set",
+		},
+		{
+			name:    "canary and blue/green",
+			version: "v1",
+			object:  "spec: {imageName: nginx, rollout: {canary: {steps: [{setWeight: 50}]}, blueGreen: {}}}",
+			err:     "canary and blueGreen are mutually exclusive",
+		},
+		{
+			name:    "no containers",
+			version: "v2",
+			object:  "spec: {containers: []}",
+			err:     "spec.containers",
+		},
+		{
+			name:    "several containers",
+			version: "v2",
+			object:  "spec: {containers: [{name: main, image: nginx}, {name: sidecar, image: envoy}]}",
+			err:     "spec.containers",
+		},
+		{
+			name:    "invalid container name",
+			version: "v2",
+			object:  "spec: {containers: [{name: Main, image: nginx}]}",
+			err:     "spec.containers[0].name",
+		},
+	}
+	for _, test := range tests {
+		errs := validate(t, test.version, test.object)
+		switch {
+		case test.err == "" && len(errs) > 0:
+			t.Errorf("%s: expected no error, got %v", test.name, errs)
+		case test.err != "" && !strings.Contains(errs.ToAggregate().Error(), test.err):
+			t.Errorf("%s: expected an error containing %q, got %v", test.name, test.err, errs)
+		}
+	}
+}
+
+func mustRead(t *testing.T, path string) string {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(raw)
+}
@@ -0,0 +1,106 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"k8s.io/klog/v2"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+)
+
+// Check is a named probe returning an error when the component it checks is
+// unhealthy.
+type Check struct {
+	Name  string
+	Check func() error
+}
+
+// Server serves the liveness and readiness probes of the controller process
+// on /healthz and /readyz. Other endpoints, such as /metrics, are added with
+// Handle, and the pprof profiles with EnablePprof.
+type Server struct {
+	Addr      string
+	Liveness  []Check
+	Readiness []Check
+
+	mux *http.ServeMux
+}
+
+// NewServer returns a server listening on addr.
+func NewServer(addr string) *Server {
+	s := &Server{Addr: addr, mux: http.NewServeMux()}
+	s.mux.Handle("/healthz", checksHandler(func() []Check { return s.Liveness }))
+	s.mux.Handle("/readyz", checksHandler(func() []Check { return s.Readiness }))
+	return s
+}
+
+// EnablePprof serves the pprof profiles under /debug/pprof/. They expose the
+// command line and memory of the process, so they are off by default.
+func (s *Server) EnablePprof() {
+	s.mux.HandleFunc("/debug/pprof/", pprof.Index)
+	s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
+
+// Handle registers an additional handler, such as a debug endpoint.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Run serves until ctx is done.
+func (s *Server) Run(ctx context.Context) error {
+	server := &http.Server{Addr: s.Addr, Handler: s.mux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Error shutting down health server: %s", err.Error())
+		}
+	}()
+
+	klog.Infof("Serving health probes on %s", s.Addr)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// checksHandler answers 200 when every check passes and 500 otherwise. The
+// result of each check is listed with ?verbose, or on failure.
+func checksHandler(checks func() []Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report strings.Builder
+		failed := false
+		for _, check := range checks() {
+			if err := check.Check(); err != nil {
+				failed = true
+				fmt.Fprintf(&report, "[-]%s failed: %s\n", check.Name, err.Error())
+				continue
+			}
+			fmt.Fprintf(&report, "[+]%s ok\n", check.Name)
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if failed {
+			klog.V(2).Infof("%s check failed:\n%s", r.URL.Path, report.String())
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "%s%s check failed\n", report.String(), strings.TrimPrefix(r.URL.Path, "/"))
+			return
+		}
+		if _, verbose := r.URL.Query()["verbose"]; verbose {
+			fmt.Fprint(w, report.String())
+		}
+		fmt.Fprint(w, "ok\n")
+	})
+}
@@ -0,0 +1,48 @@
+package health_test
+
+import (
+	"errors"
+	"github.com/artifakt-io/demo-controller/internal/health"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChecks(t *testing.T) {
+	s := health.NewServer(":0")
+	s.Liveness = []health.Check{{Name: "workqueue", Check: func() error { return nil }}}
+	s.Readiness = []health.Check{{Name: "informers", Check: func() error { return errors.New("not synced") }}}
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/healthz", http.StatusOK, "ok\n"},
+		{"/healthz?verbose", http.StatusOK, "[+]workqueue ok\nok\n"},
+		{"/readyz", http.StatusInternalServerError, "[-]informers failed: not synced\nreadyz check failed\n"},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+		if w.Code != test.code || w.Body.String() != test.body {
+			t.Errorf("%s: expected %d %q, got %d %q", test.path, test.code, test.body, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestPprof(t *testing.T) {
+	s := health.NewServer(":0")
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected pprof to be disabled by default, got %d", w.Code)
+	}
+
+	s.EnablePprof()
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected pprof to be served once enabled, got %d", w.Code)
+	}
+}
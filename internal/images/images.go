@@ -0,0 +1,188 @@
+// Package images resolves the images of applications through the OCI
+// distribution API of their registry.
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	"path"
+	"strings"
+)
+
+// Resolver resolves image tags to the digest of the manifest they point to,
+// authenticating with the pull secrets of the applications.
+type Resolver struct {
+	SecretsLister corelisters.SecretLister
+	// Options are added to the options of the registry requests.
+	Options []remote.Option
+}
+
+// NewResolver returns a resolver reading the pull secrets and the public
+// keys of the signatures from secretsLister.
+func NewResolver(secretsLister corelisters.SecretLister) *Resolver {
+	return &Resolver{SecretsLister: secretsLister}
+}
+
+// Resolved is an image resolved to a digest.
+type Resolved struct {
+	// Image is the reference of the image by digest, such as
+	// index.docker.io/library/nginx@sha256:...
+	Image string
+	// Tag is the tag the image was resolved from, empty when the image was
+	// already pinned to a digest.
+	Tag string
+	// Digest is the digest of the manifest of the image.
+	Digest string
+}
+
+// Resolve resolves the tag of image with a HEAD request on its manifest,
+// authenticated with pullSecrets of namespace. An image pinned to a digest is
+// returned as is.
+func (r *Resolver) Resolve(ctx context.Context, namespace string, pullSecrets []corev1.LocalObjectReference, image string) (Resolved, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return Resolved{}, err
+	}
+	if digest, ok := ref.(name.Digest); ok {
+		return Resolved{Image: image, Digest: digest.DigestStr()}, nil
+	}
+
+	options, err := r.RemoteOptions(ctx, namespace, pullSecrets)
+	if err != nil {
+		return Resolved{}, err
+	}
+	descriptor, err := remote.Head(ref, options...)
+	if err != nil {
+		return Resolved{}, err
+	}
+	digest := descriptor.Digest.String()
+	return Resolved{Image: ref.Context().Digest(digest).Name(), Tag: ref.Identifier(), Digest: digest}, nil
+}
+
+// Pin returns image pinned to digest, when digest was resolved from tag,
+// the tag of image. It returns false when image is not tagged with tag.
+func Pin(image, tag, digest string) (string, bool) {
+	ref, err := name.ParseReference(image)
+	if err != nil || digest == "" || ref.Identifier() != tag {
+		return "", false
+	}
+	if _, ok := ref.(name.Tag); !ok {
+		return "", false
+	}
+	return ref.Context().Digest(digest).Name(), true
+}
+
+// ListTags returns the tags of the repository of image, listed with the
+// credentials of pullSecrets of namespace.
+func (r *Resolver) ListTags(ctx context.Context, namespace string, pullSecrets []corev1.LocalObjectReference, image string) ([]string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+	options, err := r.RemoteOptions(ctx, namespace, pullSecrets)
+	if err != nil {
+		return nil, err
+	}
+	return remote.List(ref.Context(), options...)
+}
+
+// RemoteOptions returns the options of the requests to the registries of
+// the images of an application, authenticated with its pullSecrets.
+func (r *Resolver) RemoteOptions(ctx context.Context, namespace string, pullSecrets []corev1.LocalObjectReference) ([]remote.Option, error) {
+	keychain, err := r.Keychain(namespace, pullSecrets)
+	if err != nil {
+		return nil, err
+	}
+	return append([]remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain)}, r.Options...), nil
+}
+
+// Keychain returns the credentials of the docker configs held by
+// pullSecrets of namespace. Missing secrets are skipped, as the kubelet does.
+func (r *Resolver) Keychain(namespace string, pullSecrets []corev1.LocalObjectReference) (authn.Keychain, error) {
+	var keychain dockerConfigKeychain
+	for _, reference := range pullSecrets {
+		secret, err := r.SecretsLister.Secrets(namespace).Get(reference.Name)
+		if errors.IsNotFound(err) {
+			klog.Warningf("Pull secret %s/%s not found", namespace, reference.Name)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		config, err := dockerConfigOf(secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pull secret %s/%s: %w", namespace, reference.Name, err)
+		}
+		keychain = append(keychain, config)
+	}
+	return keychain, nil
+}
+
+// dockerConfig maps registries to their credentials.
+type dockerConfig map[string]authn.AuthConfig
+
+// dockerConfigOf returns the docker config of a secret of type
+// kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg.
+func dockerConfigOf(secret *corev1.Secret) (dockerConfig, error) {
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		var config struct {
+			Auths dockerConfig `json:"auths"`
+		}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return nil, err
+		}
+		return config.Auths, nil
+	case corev1.SecretTypeDockercfg:
+		var config dockerConfig
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &config); err != nil {
+			return nil, err
+		}
+		return config, nil
+	default:
+		return nil, fmt.Errorf("type %s is not %s", secret.Type, corev1.SecretTypeDockerConfigJson)
+	}
+}
+
+// dockerConfigKeychain is an authn.Keychain picking the credentials of the
+// first docker config matching the registry, or anonymous access.
+type dockerConfigKeychain []dockerConfig
+
+func (k dockerConfigKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	registry := resource.RegistryStr()
+	for _, config := range k {
+		for key, auth := range config {
+			if matchesRegistry(key, registry) {
+				return authn.FromConfig(auth), nil
+			}
+		}
+	}
+	return authn.Anonymous, nil
+}
+
+// matchesRegistry returns whether the key of a docker config, such as
+// https://index.docker.io/v1/, registry.example.com or *.example.com,
+// holds the credentials of registry.
+func matchesRegistry(key, registry string) bool {
+	host := key
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	if strings.Contains(host, "*") {
+		matched, _ := path.Match(host, registry)
+		return matched
+	}
+	normalized, err := name.NewRegistry(host)
+	return err == nil && normalized.RegistryStr() == registry
+}
@@ -0,0 +1,88 @@
+package images
+
+import (
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"github.com/blang/semver/v4"
+	"github.com/google/go-containerregistry/pkg/name"
+	"regexp"
+	"strings"
+)
+
+// tagFilter selects the tags allowed by an ImageUpdatePolicy.
+type tagFilter struct {
+	allowed semver.Range
+	filter  *regexp.Regexp
+}
+
+func newTagFilter(policy *v1.ImageUpdatePolicy) (*tagFilter, error) {
+	f := &tagFilter{}
+	var err error
+	if policy.Semver != "" {
+		if f.allowed, err = semver.ParseRange(policy.Semver); err != nil {
+			return nil, err
+		}
+	}
+	if policy.Filter != "" {
+		if f.filter, err = regexp.Compile(policy.Filter); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// version returns the semantic version of tag and whether the tag is
+// allowed.
+func (f *tagFilter) version(tag string) (semver.Version, bool) {
+	if f.filter != nil && !f.filter.MatchString(tag) {
+		return semver.Version{}, false
+	}
+	version, err := semver.ParseTolerant(tag)
+	if err != nil || (f.allowed != nil && !f.allowed(version)) {
+		return semver.Version{}, false
+	}
+	return version, true
+}
+
+// Allowed returns whether policy allows tag, regardless of the current tag
+// of the image.
+func Allowed(tag string, policy *v1.ImageUpdatePolicy) (bool, error) {
+	f, err := newTagFilter(policy)
+	if err != nil {
+		return false, err
+	}
+	_, ok := f.version(tag)
+	return ok, nil
+}
+
+// Update returns image with its tag replaced by the newest of tags allowed
+// by policy, or an empty string when no tag is newer than the current one.
+// A current tag which is not a semantic version, such as latest, is
+// replaced by the newest allowed tag.
+func Update(image string, tags []string, policy *v1.ImageUpdatePolicy) (string, error) {
+	ref, err := name.NewTag(image)
+	if err != nil {
+		return "", err
+	}
+	f, err := newTagFilter(policy)
+	if err != nil {
+		return "", err
+	}
+
+	current, currentErr := semver.ParseTolerant(ref.TagStr())
+	newest, newestVersion := "", semver.Version{}
+	for _, tag := range tags {
+		version, ok := f.version(tag)
+		if !ok || (currentErr == nil && !version.GT(current)) {
+			continue
+		}
+		if newest == "" || version.GT(newestVersion) {
+			newest, newestVersion = tag, version
+		}
+	}
+	if newest == "" {
+		return "", nil
+	}
+	// The image keeps the form of the spec, such as nginx:1.21 rather than
+	// index.docker.io/library/nginx:1.21.
+	return strings.TrimSuffix(image, ":"+ref.TagStr()) + ":" + newest, nil
+}
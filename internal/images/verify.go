@@ -0,0 +1,177 @@
+package images
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/name"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"io/ioutil"
+	corev1 "k8s.io/api/core/v1"
+	"sort"
+	"strings"
+)
+
+const (
+	// SignatureAnnotation is the annotation of the layers of a cosign
+	// signature holding the base64 encoded signature of the layer.
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// SimpleSigningMediaType is the media type of the layers of a cosign
+	// signature.
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// SignatureType is the type of the payload signed by cosign.
+	SignatureType = "cosign container image signature"
+)
+
+// SimpleSigning is the payload of a cosign signature, in the simple signing
+// format of containers/image.
+type SimpleSigning struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+// SignatureTag returns the tag cosign stores the signatures of digest at, in
+// the repository of the image: sha256-<hex>.sig.
+func SignatureTag(digest name.Digest) name.Tag {
+	return digest.Context().Tag(strings.Replace(digest.DigestStr(), ":", "-", 1) + ".sig")
+}
+
+// PublicKeys returns the public keys of the PEM blocks of the Secret name of
+// namespace. Other blocks, such as the encrypted private key of cosign, are
+// ignored.
+func (r *Resolver) PublicKeys(namespace, name string) ([]crypto.PublicKey, error) {
+	secret, err := r.SecretsLister.Secrets(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var publicKeys []crypto.PublicKey
+	for _, key := range keys {
+		rest := secret.Data[key]
+		for {
+			var block *pem.Block
+			if block, rest = pem.Decode(rest); block == nil {
+				break
+			}
+			if block.Type != "PUBLIC KEY" {
+				continue
+			}
+			publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid public key in %s of secret %s: %w", key, name, err)
+			}
+			publicKeys = append(publicKeys, publicKey)
+		}
+	}
+	if len(publicKeys) == 0 {
+		return nil, fmt.Errorf("secret %s holds no PEM encoded public key", name)
+	}
+	return publicKeys, nil
+}
+
+// Verify returns an error unless image, which must be pinned to a digest, is
+// signed with cosign by one of the public keys of the Secret keysSecret. The
+// signatures are read with the credentials of pullSecrets of namespace.
+func (r *Resolver) Verify(ctx context.Context, namespace string, pullSecrets []corev1.LocalObjectReference, image, keysSecret string) error {
+	digest, err := name.NewDigest(image)
+	if err != nil {
+		return err
+	}
+	keys, err := r.PublicKeys(namespace, keysSecret)
+	if err != nil {
+		return err
+	}
+	options, err := r.RemoteOptions(ctx, namespace, pullSecrets)
+	if err != nil {
+		return err
+	}
+	signatures, err := remote.Image(SignatureTag(digest), options...)
+	if err != nil {
+		return fmt.Errorf("unable to fetch the signatures of %s: %w", digest.DigestStr(), err)
+	}
+	manifest, err := signatures.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to fetch the signatures of %s: %w", digest.DigestStr(), err)
+	}
+
+	for _, descriptor := range manifest.Layers {
+		signature, err := base64.StdEncoding.DecodeString(descriptor.Annotations[SignatureAnnotation])
+		if err != nil || len(signature) == 0 {
+			continue
+		}
+		layer, err := signatures.LayerByDigest(descriptor.Digest)
+		if err != nil {
+			return err
+		}
+		payload, err := readPayload(layer)
+		if err != nil {
+			return fmt.Errorf("unable to fetch the signature %s: %w", descriptor.Digest, err)
+		}
+		if !verifySignature(keys, payload, signature) {
+			continue
+		}
+		var simpleSigning SimpleSigning
+		if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+			continue
+		}
+		if simpleSigning.Critical.Type == SignatureType && simpleSigning.Critical.Image.DockerManifestDigest == digest.DigestStr() {
+			return nil
+		}
+	}
+	return fmt.Errorf("no signature of %s matches the public keys of secret %s", digest.DigestStr(), keysSecret)
+}
+
+// readPayload returns the content of the layer of a signature.
+func readPayload(layer ociv1.Layer) ([]byte, error) {
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+// verifySignature returns whether signature is the signature of payload by
+// one of keys, as cosign signs with ECDSA, RSA or Ed25519 keys.
+func verifySignature(keys []crypto.PublicKey, payload, signature []byte) bool {
+	digest := sha256.Sum256(payload)
+	for _, key := range keys {
+		switch key := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(key, digest[:], signature) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(key, payload, signature) {
+				return true
+			}
+		}
+	}
+	return false
+}
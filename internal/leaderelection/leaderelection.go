@@ -0,0 +1,183 @@
+package leaderelection
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config holds the leader election settings of the controller.
+type Config struct {
+	// Enabled runs the workers only in the process holding the Lease.
+	Enabled bool
+
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+
+	// Namespace and Name identify the Lease used as lock.
+	Namespace string
+	Name      string
+	// Identity is the holder identity written to the Lease by this process.
+	Identity string
+}
+
+// AddFlags registers the leader election flags on fs.
+func (c *Config) AddFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.Enabled, "leader-elect", false, "Start the workers only once elected leader, so several replicas of the controller can run for availability.")
+	fs.DurationVar(&c.LeaseDuration, "leader-elect-lease-duration", 15*time.Second, "The duration standbys wait after the last renewal before acquiring the Lease of a leader.")
+	fs.DurationVar(&c.RenewDeadline, "leader-elect-renew-deadline", 10*time.Second, "The duration the leader retries renewing its Lease before giving up leadership.")
+	fs.DurationVar(&c.RetryPeriod, "leader-elect-retry-period", 2*time.Second, "The duration between two attempts to acquire or renew the Lease.")
+	fs.StringVar(&c.Namespace, "leader-elect-resource-namespace", defaultNamespace(), "The namespace of the Lease used for leader election. Defaults to $POD_NAMESPACE.")
+	fs.StringVar(&c.Name, "leader-elect-resource-name", "demo-controller", "The name of the Lease used for leader election.")
+	fs.StringVar(&c.Identity, "leader-elect-identity", "", "The holder identity of this process. Defaults to the hostname with a unique suffix.")
+}
+
+func defaultNamespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+	return "default"
+}
+
+// State exposes the leadership of the process.
+type State struct {
+	mu       sync.RWMutex
+	leader   string
+	isLeader bool
+}
+
+// IsLeader returns whether this process currently leads.
+func (s *State) IsLeader() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.isLeader
+}
+
+// Leader returns the identity of the last observed leader.
+func (s *State) Leader() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.leader
+}
+
+func (s *State) set(leader string, isLeader bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leader = leader
+	s.isLeader = isLeader
+}
+
+// Run calls run once this process is elected leader and returns when ctx is
+// done. The context of run is cancelled with ctx, and the Lease is held until
+// run returned. Losing the Lease is fatal: the workers of a former leader must
+// not keep reconciling alongside the new one. When leader election is
+// disabled, run is called right away.
+func Run(ctx context.Context, config Config, client kubernetes.Interface, state *State, run func(ctx context.Context)) error {
+	if !config.Enabled {
+		state.set(config.Identity, true)
+		run(ctx)
+		return nil
+	}
+
+	identity := config.Identity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("unable to get hostname: %w", err)
+		}
+		identity = hostname + "_" + string(uuid.NewUUID())
+	}
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events(config.Namespace)})
+	defer eventBroadcaster.Shutdown()
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{Namespace: config.Namespace, Name: config.Name},
+		Client:    client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity:      identity,
+			EventRecorder: eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: config.Name}),
+		},
+	}
+
+	// The elector runs until run returned rather than until ctx is done, so
+	// that the Lease is released only once the workers drained and a standby
+	// cannot start reconciling alongside them.
+	electorCtx, stopElector := context.WithCancel(context.Background())
+	defer stopElector()
+	var leading int32
+	go func() {
+		select {
+		case <-ctx.Done():
+			if atomic.LoadInt32(&leading) == 0 {
+				stopElector()
+			}
+		case <-electorCtx.Done():
+		}
+	}()
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   config.LeaseDuration,
+		RenewDeadline:   config.RenewDeadline,
+		RetryPeriod:     config.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            config.Name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				atomic.StoreInt32(&leading, 1)
+				defer stopElector()
+				runCtx, cancel := context.WithCancel(leaderCtx)
+				defer cancel()
+				go func() {
+					select {
+					case <-ctx.Done():
+						cancel()
+					case <-runCtx.Done():
+					}
+				}()
+				klog.Infof("Started leading as %s", identity)
+				state.set(identity, true)
+				run(runCtx)
+			},
+			OnStoppedLeading: func() {
+				state.set("", false)
+				if electorCtx.Err() != nil {
+					klog.Infof("Released leadership of %s/%s", config.Namespace, config.Name)
+					return
+				}
+				klog.Fatalf("Lost leadership of %s/%s", config.Namespace, config.Name)
+			},
+			OnNewLeader: func(leader string) {
+				if leader == identity {
+					return
+				}
+				klog.Infof("New leader elected: %s", leader)
+				state.set(leader, false)
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Waiting to acquire lease %s/%s as %s", config.Namespace, config.Name, identity)
+	elector.Run(electorCtx)
+	return nil
+}
@@ -0,0 +1,123 @@
+package leaderelection_test
+
+import (
+	"context"
+	"github.com/artifakt-io/demo-controller/internal/leaderelection"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"testing"
+	"time"
+)
+
+func newConfig(enabled bool) leaderelection.Config {
+	return leaderelection.Config{
+		Enabled:       enabled,
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   10 * time.Millisecond,
+		Namespace:     metav1.NamespaceDefault,
+		Name:          "demo-controller",
+		Identity:      "replica-a",
+	}
+}
+
+func TestRunWithoutLeaderElection(t *testing.T) {
+	state := &leaderelection.State{}
+	ran := false
+	err := leaderelection.Run(context.Background(), newConfig(false), k8sfake.NewSimpleClientset(), state, func(ctx context.Context) {
+		ran = true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ran || !state.IsLeader() {
+		t.Errorf("expected run to be called as leader")
+	}
+}
+
+func TestRunAcquiresLease(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	state := &leaderelection.State{}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var leader string
+	err := leaderelection.Run(ctx, newConfig(true), client, state, func(ctx context.Context) {
+		leader = state.Leader()
+		if !state.IsLeader() {
+			t.Errorf("expected to lead while running")
+		}
+		cancel()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if leader != "replica-a" {
+		t.Errorf("expected replica-a to lead, got %q", leader)
+	}
+	if state.IsLeader() {
+		t.Errorf("expected leadership to be released")
+	}
+
+	lease, err := client.CoordinationV1().Leases(metav1.NamespaceDefault).Get(context.Background(), "demo-controller", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error getting lease: %v", err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != "" {
+		t.Errorf("expected the released lease to have no holder, got %v", lease.Spec.HolderIdentity)
+	}
+}
+
+func TestRunHoldsLeaseUntilDrained(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	draining := make(chan struct{})
+	drained := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- leaderelection.Run(ctx, newConfig(true), client, &leaderelection.State{}, func(ctx context.Context) {
+			cancel()
+			<-ctx.Done()
+			// A reconcile is still in flight.
+			close(draining)
+			<-drained
+		})
+	}()
+
+	holder := func() string {
+		lease, err := client.CoordinationV1().Leases(metav1.NamespaceDefault).Get(context.Background(), "demo-controller", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error getting lease: %v", err)
+		}
+		if lease.Spec.HolderIdentity == nil {
+			return ""
+		}
+		return *lease.Spec.HolderIdentity
+	}
+
+	select {
+	case <-draining:
+	case <-time.After(10 * time.Second):
+		t.Fatal("run was not called")
+	}
+	// Leave the elector several retry periods to release the Lease.
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("expected Run to wait for run to return")
+	default:
+	}
+	if holder := holder(); holder != "replica-a" {
+		t.Errorf("expected the lease to be held while draining, got holder %q", holder)
+	}
+
+	close(drained)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if holder := holder(); holder != "" {
+		t.Errorf("expected the lease to be released once drained, got holder %q", holder)
+	}
+}
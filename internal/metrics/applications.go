@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+var (
+	applicationDesiredReplicas = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "application", "desired_replicas"),
+		"Number of replicas requested by the spec of the application.",
+		[]string{"namespace", "application"}, nil)
+
+	applicationReadyReplicas = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "application", "ready_replicas"),
+		"Number of ready replicas reported in the status of the application.",
+		[]string{"namespace", "application"}, nil)
+)
+
+// ApplicationCollector exposes the desired and ready replicas of every
+// application in the informer cache at scrape time, so that deleted
+// applications leave no stale series behind.
+type ApplicationCollector struct {
+	Lister listers.ApplicationLister
+}
+
+// Describe implements prometheus.Collector.
+func (c ApplicationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- applicationDesiredReplicas
+	ch <- applicationReadyReplicas
+}
+
+// Collect implements prometheus.Collector.
+func (c ApplicationCollector) Collect(ch chan<- prometheus.Metric) {
+	apps, err := c.Lister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Error listing applications for metrics: %s", err.Error())
+		return
+	}
+	for _, app := range apps {
+		desired := int32(1)
+		if app.Spec.Replicas != nil {
+			desired = *app.Spec.Replicas
+		}
+		ch <- prometheus.MustNewConstMetric(applicationDesiredReplicas, prometheus.GaugeValue, float64(desired), app.Namespace, app.Name)
+		ch <- prometheus.MustNewConstMetric(applicationReadyReplicas, prometheus.GaugeValue, float64(app.Status.ReadyReplicas), app.Namespace, app.Name)
+	}
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var leaderDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "leader"),
+	"Identity of the last observed leader, with value 1 when this process leads and 0 otherwise.",
+	[]string{"identity"}, nil)
+
+// LeaderState is the leadership of the process, as tracked by leader
+// election.
+type LeaderState interface {
+	IsLeader() bool
+	Leader() string
+}
+
+// LeaderCollector exposes the leadership of the process at scrape time.
+type LeaderCollector struct {
+	State LeaderState
+}
+
+// Describe implements prometheus.Collector.
+func (c LeaderCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- leaderDesc
+}
+
+// Collect implements prometheus.Collector. Nothing is reported until a leader
+// is observed.
+func (c LeaderCollector) Collect(ch chan<- prometheus.Metric) {
+	leader, isLeader := c.State.Leader(), c.State.IsLeader()
+	if leader == "" && !isLeader {
+		return
+	}
+	value := 0.0
+	if isLeader {
+		value = 1
+	}
+	ch <- prometheus.MustNewConstMetric(leaderDesc, prometheus.GaugeValue, value, leader)
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+const namespace = "demo_controller"
+
+// Registry holds every metric exposed by the controller.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// ReconcileTotal counts the syncs of applications by result.
+	ReconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_total",
+		Help:      "Total number of application reconciles by result.",
+	}, []string{"result"})
+
+	// ReconcileErrors counts the syncs of applications which failed.
+	ReconcileErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_errors_total",
+		Help:      "Total number of application reconciles which returned an error.",
+	})
+
+	// ReconcileDuration observes the duration of the syncs of applications
+	// by result.
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of application reconciles by result.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"result"})
+
+	// APIWrites counts the write requests sent to the API server by verb and
+	// resource.
+	APIWrites = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_writes_total",
+		Help:      "Total number of write requests sent to the API server by verb, resource and status code.",
+	}, []string{"verb", "resource", "code"})
+)
+
+// Reconcile results.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		ReconcileTotal,
+		ReconcileErrors,
+		ReconcileDuration,
+		APIWrites,
+	)
+}
+
+// Handler serves the metrics of Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
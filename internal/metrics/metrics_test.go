@@ -0,0 +1,132 @@
+package metrics_test
+
+import (
+	"github.com/artifakt-io/demo-controller/internal/metrics"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWorkqueueMetrics(t *testing.T) {
+	queue := workqueue.NewNamed("Test")
+	defer queue.ShutDown()
+	queue.Add("default/test")
+	queue.Add("default/other")
+
+	expected := `
+# HELP demo_controller_workqueue_adds_total Total number of adds handled by the workqueue.
+# TYPE demo_controller_workqueue_adds_total counter
+demo_controller_workqueue_adds_total{name="Test"} 2
+# HELP demo_controller_workqueue_depth Current depth of the workqueue.
+# TYPE demo_controller_workqueue_depth gauge
+demo_controller_workqueue_depth{name="Test"} 2
+`
+	err := testutil.GatherAndCompare(metrics.Registry, strings.NewReader(expected),
+		"demo_controller_workqueue_adds_total", "demo_controller_workqueue_depth")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInstrumentTransportCountsWrites(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: metrics.InstrumentTransport(http.DefaultTransport)}
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/apis/apps/v1/namespaces/default/deployments/test"},
+		{http.MethodPost, "/apis/apps/v1/namespaces/default/deployments"},
+		{http.MethodPut, "/apis/apps/v1/namespaces/default/deployments/test"},
+		{http.MethodPut, "/apis/cloudest.artifakt.io/v1/namespaces/default/applications/test/status"},
+		{http.MethodDelete, "/api/v1/namespaces/default/services/test"},
+	}
+	for _, r := range requests {
+		req, _ := http.NewRequest(r.method, server.URL+r.path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	expected := map[[2]string]float64{
+		{"create", "deployments"}:         1,
+		{"update", "deployments"}:         1,
+		{"update", "applications/status"}: 1,
+		{"delete", "services"}:            1,
+	}
+	for labels, value := range expected {
+		if got := testutil.ToFloat64(metrics.APIWrites.WithLabelValues(labels[0], labels[1], "200")); got != value {
+			t.Errorf("%v: expected %v writes, got %v", labels, value, got)
+		}
+	}
+	if got := testutil.CollectAndCount(metrics.APIWrites); got != len(expected) {
+		t.Errorf("expected %d series, got %d", len(expected), got)
+	}
+}
+
+func TestApplicationCollector(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	replicas := int32(3)
+	_ = indexer.Add(&v1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: metav1.NamespaceDefault},
+		Spec:       v1.ApplicationSpec{Replicas: &replicas},
+		Status:     v1.ApplicationStatus{ReadyReplicas: 2},
+	})
+
+	expected := `
+# HELP demo_controller_application_desired_replicas Number of replicas requested by the spec of the application.
+# TYPE demo_controller_application_desired_replicas gauge
+demo_controller_application_desired_replicas{application="test",namespace="default"} 3
+# HELP demo_controller_application_ready_replicas Number of ready replicas reported in the status of the application.
+# TYPE demo_controller_application_ready_replicas gauge
+demo_controller_application_ready_replicas{application="test",namespace="default"} 2
+`
+	collector := metrics.ApplicationCollector{Lister: listers.NewApplicationLister(indexer)}
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+type leaderState struct {
+	leader   string
+	isLeader bool
+}
+
+func (s leaderState) IsLeader() bool { return s.isLeader }
+func (s leaderState) Leader() string { return s.leader }
+
+func TestLeaderCollector(t *testing.T) {
+	tests := []struct {
+		state    leaderState
+		expected string
+	}{
+		{state: leaderState{}, expected: ""},
+		{state: leaderState{leader: "replica-a", isLeader: true}, expected: `demo_controller_leader{identity="replica-a"} 1`},
+		{state: leaderState{leader: "replica-b"}, expected: `demo_controller_leader{identity="replica-b"} 0`},
+	}
+	for _, test := range tests {
+		expected := ""
+		if test.expected != "" {
+			expected = `
+# HELP demo_controller_leader Identity of the last observed leader, with value 1 when this process leads and 0 otherwise.
+# TYPE demo_controller_leader gauge
+` + test.expected + "\n"
+		}
+		if err := testutil.CollectAndCompare(metrics.LeaderCollector{State: test.state}, strings.NewReader(expected)); err != nil {
+			t.Errorf("%+v: %v", test.state, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var writeVerbs = map[string]string{
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "patch",
+	http.MethodDelete: "delete",
+}
+
+// InstrumentTransport wraps rt to count in APIWrites the write requests sent
+// to the API server. It fits rest.Config.Wrap.
+func InstrumentTransport(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		verb, ok := writeVerbs[req.Method]
+		if !ok {
+			return rt.RoundTrip(req)
+		}
+		resp, err := rt.RoundTrip(req)
+		code := "<error>"
+		if err == nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		APIWrites.WithLabelValues(verb, resourceFromPath(req.URL.Path), code).Inc()
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// resourceFromPath returns the resource, with its subresource, targeted by
+// an API path such as /apis/apps/v1/namespaces/default/deployments/name.
+func resourceFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return "unknown"
+	}
+	if len(segments) >= 2 && segments[0] == "namespaces" {
+		if len(segments) == 2 {
+			return "namespaces"
+		}
+		segments = segments[2:]
+	}
+	switch len(segments) {
+	case 0:
+		return "unknown"
+	case 1, 2:
+		return segments[0]
+	default:
+		return segments[0] + "/" + segments[2]
+	}
+}
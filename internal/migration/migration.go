@@ -0,0 +1,86 @@
+// Package migration rewrites the stored Applications in the storage version
+// of the Applications CRD.
+package migration
+
+import (
+	"context"
+	"fmt"
+	v2 "github.com/artifakt-io/demo-controller/pkg/apis/application/v2"
+	clientset "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// CustomResourceDefinition is the name of the Applications CRD.
+const CustomResourceDefinition = "applications.cloudest.artifakt.io"
+
+// pageSize is the number of Applications listed at once.
+const pageSize = 500
+
+var customResourceDefinitions = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// Migrate rewrites every Application so that the API server stores it in
+// the v2 storage version, then records v2 as the only stored version of the
+// CRD, allowing v1 to be removed from it later. Applications deleted
+// meanwhile are skipped and those updated meanwhile are retried.
+func Migrate(ctx context.Context, client clientset.Interface, dynamicClient dynamic.Interface) error {
+	migrated := 0
+	options := metav1.ListOptions{Limit: pageSize}
+	for {
+		list, err := client.CloudestV2().Applications(metav1.NamespaceAll).List(ctx, options)
+		if err != nil {
+			return fmt.Errorf("unable to list applications: %w", err)
+		}
+		for i := range list.Items {
+			if err := rewrite(ctx, client, &list.Items[i]); err != nil {
+				return err
+			}
+			migrated++
+		}
+		if list.Continue == "" {
+			break
+		}
+		options.Continue = list.Continue
+	}
+	klog.InfoS("Migrated applications", "count", migrated, "storageVersion", v2.Version)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		definition, err := dynamicClient.Resource(customResourceDefinitions).Get(ctx, CustomResourceDefinition, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedStringSlice(definition.Object, []string{v2.Version}, "status", "storedVersions"); err != nil {
+			return err
+		}
+		_, err = dynamicClient.Resource(customResourceDefinitions).UpdateStatus(ctx, definition, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// rewrite updates app unchanged, which stores it in the storage version.
+func rewrite(ctx context.Context, client clientset.Interface, app *v2.Application) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := client.CloudestV2().Applications(app.Namespace).Update(ctx, app, metav1.UpdateOptions{})
+		if !errors.IsConflict(err) {
+			return err
+		}
+		current, getErr := client.CloudestV2().Applications(app.Namespace).Get(ctx, app.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return getErr
+		}
+		app = current
+		return err
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to migrate application %s/%s: %w", app.Namespace, app.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package migration_test
+
+import (
+	"context"
+	"github.com/artifakt-io/demo-controller/internal/migration"
+	v2 "github.com/artifakt-io/demo-controller/pkg/apis/application/v2"
+	"github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/fake"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	core "k8s.io/client-go/testing"
+	"testing"
+)
+
+func newApplication(namespace, name string) *v2.Application {
+	return &v2.Application{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v2.ApplicationSpec{Containers: []v2.Container{{Name: v2.MainContainerName, Image: "nginx"}}},
+	}
+}
+
+func newCustomResourceDefinition() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": migration.CustomResourceDefinition},
+		"status":     map[string]interface{}{"storedVersions": []interface{}{"v1", "v2"}},
+	}}
+}
+
+func TestMigrate(t *testing.T) {
+	client := fake.NewSimpleClientset(newApplication("team-a", "web"), newApplication("team-b", "api"))
+	// The first update of web conflicts with a concurrent write.
+	conflicted := false
+	client.PrependReactor("update", "applications", func(action core.Action) (bool, runtime.Object, error) {
+		app := action.(core.UpdateAction).GetObject().(*v2.Application)
+		if app.Name == "web" && !conflicted {
+			conflicted = true
+			return true, nil, errors.NewConflict(v2.Resource("applications"), app.Name, nil)
+		}
+		return false, nil, nil
+	})
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newCustomResourceDefinition())
+
+	if err := migration.Migrate(context.Background(), client, dynamicClient); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated := map[string]int{}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "update" {
+			updated[action.(core.UpdateAction).GetObject().(*v2.Application).Name]++
+		}
+	}
+	if updated["web"] != 2 || updated["api"] != 1 {
+		t.Errorf("expected web to be rewritten after a conflict and api once, got %v", updated)
+	}
+
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	definition, err := dynamicClient.Resource(crds).Get(context.Background(), migration.CustomResourceDefinition, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	storedVersions, _, _ := unstructured.NestedStringSlice(definition.Object, "status", "storedVersions")
+	if len(storedVersions) != 1 || storedVersions[0] != "v2" {
+		t.Errorf("expected v2 to be the only stored version, got %v", storedVersions)
+	}
+}
+
+func TestMigrateSkipsDeletedApplications(t *testing.T) {
+	client := fake.NewSimpleClientset(newApplication("team-a", "web"))
+	client.PrependReactor("update", "applications", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewNotFound(v2.Resource("applications"), "web")
+	})
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newCustomResourceDefinition())
+
+	if err := migration.Migrate(context.Background(), client, dynamicClient); err != nil {
+		t.Fatalf("expected deleted applications to be skipped, got %v", err)
+	}
+}
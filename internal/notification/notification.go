@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/name"
+	"io/ioutil"
+	"k8s.io/klog/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the header holding the HMAC-SHA256 of the body of a
+// notification, as sha256=<hex>.
+const SignatureHeader = "X-Signature-256"
+
+// maxBodySize bounds the size of the notifications read by the receiver.
+const maxBodySize = 1 << 20
+
+// Push is an image manifest pushed to a registry under a tag.
+type Push struct {
+	Repository name.Repository
+	Tag        string
+	Digest     string
+}
+
+// Envelope is the body of the notifications of a Docker distribution or OCI
+// registry.
+type Envelope struct {
+	Events []Event `json:"events"`
+}
+
+// Event is an event of a registry notification. Only the fields read by the
+// receiver are decoded.
+type Event struct {
+	Action string `json:"action"`
+	Target struct {
+		MediaType  string `json:"mediaType"`
+		Digest     string `json:"digest"`
+		Repository string `json:"repository"`
+		URL        string `json:"url"`
+		Tag        string `json:"tag"`
+	} `json:"target"`
+	Request struct {
+		Host string `json:"host"`
+	} `json:"request"`
+}
+
+// Receiver accepts the push notifications of registries, authenticated
+// with the HMAC of their body, and calls Notify with the tags they push.
+// When Leading is set and returns false, notifications are refused with 503
+// Service Unavailable, so that the registry retries them until they reach
+// the leader.
+type Receiver struct {
+	Addr    string
+	Secret  []byte
+	Notify  func(Push)
+	Leading func() bool
+}
+
+// NewReceiver returns a receiver listening on addr.
+func NewReceiver(addr string, secret []byte, notify func(Push)) *Receiver {
+	return &Receiver{Addr: addr, Secret: secret, Notify: notify}
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Leading != nil && !r.Leading() {
+		http.Error(w, "not the leader", http.StatusServiceUnavailable)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to read the notification: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if !r.verify(body, req.Header.Get(SignatureHeader)) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		http.Error(w, fmt.Sprintf("unable to decode the notification: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	for _, event := range envelope.Events {
+		push, ok, err := pushOf(event)
+		if err != nil {
+			klog.Warningf("Ignoring registry event: %s", err.Error())
+			continue
+		}
+		if ok {
+			klog.V(2).InfoS("Image pushed", "repository", push.Repository.Name(), "tag", push.Tag, "digest", push.Digest)
+			r.Notify(push)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Sign returns the value of the SignatureHeader of body.
+func Sign(body, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify returns whether signature is the signature of body with the secret
+// of the receiver.
+func (r *Receiver) verify(body []byte, signature string) bool {
+	return len(r.Secret) > 0 && hmac.Equal([]byte(strings.ToLower(signature)), []byte(Sign(body, r.Secret)))
+}
+
+// pushOf returns the tag pushed by event. Pulls, deletes, blob uploads and
+// manifests pushed by digest only push no tag.
+func pushOf(event Event) (Push, bool, error) {
+	if event.Action != "push" || event.Target.Tag == "" {
+		return Push{}, false, nil
+	}
+	// The registry is the host the client pushed to, or the host of the
+	// URL of the manifest.
+	host := event.Request.Host
+	if host == "" && event.Target.URL != "" {
+		u, err := url.Parse(event.Target.URL)
+		if err != nil {
+			return Push{}, false, fmt.Errorf("invalid target URL %q: %w", event.Target.URL, err)
+		}
+		host = u.Host
+	}
+	if host == "" {
+		return Push{}, false, fmt.Errorf("no registry host for repository %q", event.Target.Repository)
+	}
+	repository, err := name.NewRepository(host + "/" + event.Target.Repository)
+	if err != nil {
+		return Push{}, false, err
+	}
+	return Push{Repository: repository, Tag: event.Target.Tag, Digest: event.Target.Digest}, true, nil
+}
+
+// Run serves until ctx is done.
+func (r *Receiver) Run(ctx context.Context) error {
+	server := &http.Server{Addr: r.Addr, Handler: r}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Error shutting down notification receiver: %s", err.Error())
+		}
+	}()
+
+	klog.Infof("Receiving registry notifications on %s", r.Addr)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package notification_test
+
+import (
+	"github.com/artifakt-io/demo-controller/internal/notification"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const envelope = `{
+  "events": [
+    {
+      "action": "push",
+      "target": {
+        "mediaType": "application/vnd.oci.image.manifest.v1+json",
+        "digest": "sha256:fc8b1cfa1ba3f5cc8b1c0bd5b6e9e3d6fb2bcd6c4a2d5dbaa7c6c8c3a2d1e0f9",
+        "repository": "team/app",
+        "url": "https://registry.example.com:5000/v2/team/app/manifests/sha256:fc8b1cfa1ba3f5cc8b1c0bd5b6e9e3d6fb2bcd6c4a2d5dbaa7c6c8c3a2d1e0f9",
+        "tag": "1.2.0"
+      },
+      "request": {"host": "registry.example.com:5000", "method": "PUT"}
+    },
+    {
+      "action": "push",
+      "target": {"mediaType": "application/octet-stream", "repository": "team/app", "digest": "sha256:0123"},
+      "request": {"host": "registry.example.com:5000", "method": "PUT"}
+    },
+    {
+      "action": "pull",
+      "target": {"repository": "team/app", "tag": "1.1.0"},
+      "request": {"host": "registry.example.com:5000", "method": "GET"}
+    },
+    {
+      "action": "push",
+      "target": {"repository": "library/nginx", "url": "https://index.docker.io/v2/library/nginx/manifests/1.21", "tag": "1.21"}
+    }
+  ]
+}`
+
+func TestReceiver(t *testing.T) {
+	secret := []byte("s3cr3t")
+	tests := []struct {
+		name      string
+		method    string
+		signature string
+		body      string
+		standby   bool
+		code      int
+		pushes    []string
+	}{
+		{
+			name:      "push",
+			signature: notification.Sign([]byte(envelope), secret),
+			body:      envelope,
+			code:      http.StatusNoContent,
+			pushes:    []string{"registry.example.com:5000/team/app:1.2.0", "index.docker.io/library/nginx:1.21"},
+		},
+		{name: "unsigned", body: envelope, code: http.StatusUnauthorized},
+		{name: "other secret", signature: notification.Sign([]byte(envelope), []byte("other")), body: envelope, code: http.StatusUnauthorized},
+		{name: "malformed signature", signature: "sha256=zz", body: envelope, code: http.StatusUnauthorized},
+		{name: "invalid body", signature: notification.Sign([]byte("{"), secret), body: "{", code: http.StatusBadRequest},
+		{name: "get", method: http.MethodGet, code: http.StatusMethodNotAllowed},
+		{
+			name:      "standby",
+			signature: notification.Sign([]byte(envelope), secret),
+			body:      envelope,
+			standby:   true,
+			code:      http.StatusServiceUnavailable,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var pushes []string
+			r := notification.NewReceiver(":0", secret, func(push notification.Push) {
+				pushes = append(pushes, push.Repository.Name()+":"+push.Tag)
+			})
+			r.Leading = func() bool { return !test.standby }
+			method := test.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/", strings.NewReader(test.body))
+			if test.signature != "" {
+				req.Header.Set(notification.SignatureHeader, test.signature)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != test.code {
+				t.Errorf("expected %d, got %d %q", test.code, w.Code, w.Body.String())
+			}
+			if !reflect.DeepEqual(pushes, test.pushes) {
+				t.Errorf("expected pushes %v, got %v", test.pushes, pushes)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Package policy evaluates the rules of the ApplicationPolicies of the cluster
+// against applications and the workload rendered for them.
+package policy
+
+import (
+	"fmt"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	celtypes "github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// costLimit bounds the evaluation of a rule, as the API server bounds the
+// CEL rules of CRDs.
+const costLimit = 1000000
+
+// Violation is a rule of an ApplicationPolicy an application breaks.
+type Violation struct {
+	Policy  string
+	Rule    string
+	Mode    v1.PolicyMode
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s/%s: %s", v.Policy, v.Rule, v.Message)
+}
+
+// Violations are the rules an application breaks.
+type Violations []Violation
+
+// Enforced returns the violations of the policies in Enforce mode.
+func (v Violations) Enforced() Violations {
+	var enforced Violations
+	for _, violation := range v {
+		if violation.Mode == v1.PolicyEnforce {
+			enforced = append(enforced, violation)
+		}
+	}
+	return enforced
+}
+
+func (v Violations) String() string {
+	messages := make([]string, len(v))
+	for i, violation := range v {
+		messages[i] = violation.String()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// env declares the variables of the rules: the Application as object and
+// its rendered Deployment as workload.
+var env = func() *cel.Env {
+	env, err := cel.NewEnv(
+		ext.Strings(),
+		cel.Declarations(
+			decls.NewVar("object", decls.Dyn),
+			decls.NewVar("workload", decls.Dyn),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return env
+}()
+
+// compile returns the program of the CEL expression of a rule.
+func compile(expression string) (cel.Program, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if resultType := ast.ResultType(); !proto.Equal(resultType, decls.Bool) && !proto.Equal(resultType, decls.Dyn) {
+		return nil, fmt.Errorf("must evaluate to a bool")
+	}
+	return env.Program(ast, cel.CostLimit(costLimit))
+}
+
+// ValidateApplicationPolicy returns the errors of the rules of policy,
+// including the expressions which do not compile.
+func ValidateApplicationPolicy(policy *v1.ApplicationPolicy) field.ErrorList {
+	var errs field.ErrorList
+	rulesPath := field.NewPath("spec", "rules")
+	if len(policy.Spec.Rules) == 0 {
+		errs = append(errs, field.Required(rulesPath, "a policy needs at least one rule"))
+	}
+	names := map[string]bool{}
+	for i, rule := range policy.Spec.Rules {
+		rulePath := rulesPath.Index(i)
+		if rule.Name == "" {
+			errs = append(errs, field.Required(rulePath.Child("name"), ""))
+		} else if names[rule.Name] {
+			errs = append(errs, field.Duplicate(rulePath.Child("name"), rule.Name))
+		}
+		names[rule.Name] = true
+		if _, err := compile(rule.Expression); err != nil {
+			errs = append(errs, field.Invalid(rulePath.Child("expression"), rule.Expression, err.Error()))
+		}
+	}
+	switch policy.Spec.Mode {
+	case "", v1.PolicyWarn, v1.PolicyEnforce:
+	default:
+		errs = append(errs, field.NotSupported(field.NewPath("spec", "mode"), policy.Spec.Mode, []string{string(v1.PolicyWarn), string(v1.PolicyEnforce)}))
+	}
+	return errs
+}
+
+// compiledPolicy holds the programs of the rules of a generation of a
+// policy. A nil program is a rule which failed to compile.
+type compiledPolicy struct {
+	generation int64
+	programs   []cel.Program
+	errs       []error
+}
+
+// Evaluator evaluates the ApplicationPolicies listed by Policies, caching
+// the programs of their rules.
+type Evaluator struct {
+	Policies listers.ApplicationPolicyLister
+
+	mu       sync.Mutex
+	compiled map[types.UID]*compiledPolicy
+}
+
+// NewEvaluator returns an evaluator of the policies of lister.
+func NewEvaluator(lister listers.ApplicationPolicyLister) *Evaluator {
+	return &Evaluator{Policies: lister, compiled: map[types.UID]*compiledPolicy{}}
+}
+
+// Evaluate returns the rules app breaks, sorted by policy. workload is the
+// Deployment rendered for app. Rules which fail to compile or to evaluate
+// are broken.
+func (e *Evaluator) Evaluate(app *v1.Application, workload *appsv1.Deployment) (Violations, error) {
+	policies, err := e.Policies.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(app)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := runtime.DefaultUnstructuredConverter.ToUnstructured(workload)
+	if err != nil {
+		return nil, err
+	}
+	vars := map[string]interface{}{"object": object, "workload": rendered}
+
+	var violations Violations
+	for _, policy := range policies {
+		mode := policy.Spec.Mode
+		if mode == "" {
+			mode = v1.PolicyEnforce
+		}
+		compiled := e.compile(policy)
+		for i, rule := range policy.Spec.Rules {
+			var message string
+			if err := compiled.errs[i]; err != nil {
+				message = fmt.Sprintf("invalid rule: %v", err)
+			} else if complies, err := evaluate(compiled.programs[i], vars); err != nil {
+				message = fmt.Sprintf("rule evaluation failed: %v", err)
+			} else if complies {
+				continue
+			} else if message = rule.Message; message == "" {
+				message = fmt.Sprintf("failed rule: %s", rule.Expression)
+			}
+			violations = append(violations, Violation{Policy: policy.Name, Rule: rule.Name, Mode: mode, Message: message})
+		}
+	}
+	e.prune(policies)
+	return violations, nil
+}
+
+// compile returns the programs of the current generation of policy.
+func (e *Evaluator) compile(policy *v1.ApplicationPolicy) *compiledPolicy {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if compiled, ok := e.compiled[policy.UID]; ok && compiled.generation == policy.Generation && len(compiled.programs) == len(policy.Spec.Rules) {
+		return compiled
+	}
+	compiled := &compiledPolicy{
+		generation: policy.Generation,
+		programs:   make([]cel.Program, len(policy.Spec.Rules)),
+		errs:       make([]error, len(policy.Spec.Rules)),
+	}
+	for i, rule := range policy.Spec.Rules {
+		compiled.programs[i], compiled.errs[i] = compile(rule.Expression)
+	}
+	e.compiled[policy.UID] = compiled
+	return compiled
+}
+
+// prune forgets the programs of the policies which were deleted.
+func (e *Evaluator) prune(policies []*v1.ApplicationPolicy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.compiled) <= len(policies) {
+		return
+	}
+	current := make(map[types.UID]bool, len(policies))
+	for _, policy := range policies {
+		current[policy.UID] = true
+	}
+	for uid := range e.compiled {
+		if !current[uid] {
+			delete(e.compiled, uid)
+		}
+	}
+}
+
+// evaluate returns whether the rule of program holds for vars.
+func evaluate(program cel.Program, vars map[string]interface{}) (bool, error) {
+	val, _, err := program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	complies, ok := val.(celtypes.Bool)
+	if !ok {
+		return false, fmt.Errorf("evaluated to %s, not a bool", val.Type().TypeName())
+	}
+	return bool(complies), nil
+}
@@ -0,0 +1,191 @@
+package policy_test
+
+import (
+	"fmt"
+	"github.com/artifakt-io/demo-controller/internal/controller"
+	"github.com/artifakt-io/demo-controller/internal/policy"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/scheme"
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	"io/ioutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/client-go/tools/cache"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+// examplePolicies returns the policies of examples/policy.yaml.
+func examplePolicies(t *testing.T) []*v1.ApplicationPolicy {
+	raw, err := ioutil.ReadFile("../../examples/policy.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var policies []*v1.ApplicationPolicy
+	for i, document := range strings.Split(string(raw), "\n---\n") {
+		applicationPolicy := &v1.ApplicationPolicy{}
+		if _, _, err := scheme.Codecs.UniversalDeserializer().Decode([]byte(document), nil, applicationPolicy); err != nil {
+			t.Fatal(err)
+		}
+		applicationPolicy.UID = types.UID(fmt.Sprint(i))
+		applicationPolicy.Generation = 1
+		policies = append(policies, applicationPolicy)
+	}
+	return policies
+}
+
+func newEvaluator(policies ...*v1.ApplicationPolicy) (*policy.Evaluator, cache.Indexer) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, applicationPolicy := range policies {
+		_ = indexer.Add(applicationPolicy)
+	}
+	return policy.NewEvaluator(listers.NewApplicationPolicyLister(indexer)), indexer
+}
+
+func newApplication(spec v1.ApplicationSpec) *v1.Application {
+	return &v1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: metav1.NamespaceDefault},
+		Spec:       spec,
+	}
+}
+
+func evaluate(t *testing.T, evaluator *policy.Evaluator, app *v1.Application) policy.Violations {
+	violations, err := evaluator.Evaluate(app, controller.NewDeployment(app))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return violations
+}
+
+func TestEvaluate(t *testing.T) {
+	limits := &corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")}}
+	tests := []struct {
+		name       string
+		spec       v1.ApplicationSpec
+		violations policy.Violations
+	}{
+		{
+			name: "compliant",
+			spec: v1.ApplicationSpec{ImageName: "nginx:1.21", Replicas: int32Ptr(2), Resources: limits},
+		},
+		{
+			name: "pinned to a digest",
+			spec: v1.ApplicationSpec{ImageName: "nginx@sha256:" + strings.Repeat("a", 64), Replicas: int32Ptr(2), Resources: limits},
+		},
+		{
+			name: "single replica",
+			spec: v1.ApplicationSpec{ImageName: "registry.example.com:5000/app:1.0", Replicas: int32Ptr(1), Resources: limits},
+			violations: policy.Violations{
+				{Policy: "replicas", Rule: "highly-available", Mode: v1.PolicyWarn, Message: "run at least 2 replicas to survive a node failure"},
+			},
+		},
+		{
+			name: "unpinned without limits",
+			spec: v1.ApplicationSpec{ImageName: "registry.example.com:5000/app", Replicas: int32Ptr(2)},
+			violations: policy.Violations{
+				{Policy: "production-readiness", Rule: "pinned-image", Mode: v1.PolicyEnforce, Message: "imageName must be pinned to a tag other than latest or to a digest"},
+				{Policy: "production-readiness", Rule: "resource-limits", Mode: v1.PolicyEnforce, Message: "every container must set resource limits"},
+			},
+		},
+	}
+
+	evaluator, _ := newEvaluator(examplePolicies(t)...)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			violations := evaluate(t, evaluator, newApplication(test.spec))
+			if !reflect.DeepEqual(violations, test.violations) {
+				t.Errorf("unexpected violations:\n%s", diff.ObjectGoPrintSideBySide(test.violations, violations))
+			}
+		})
+	}
+}
+
+func TestEvaluateFailsClosed(t *testing.T) {
+	evaluator, _ := newEvaluator(&v1.ApplicationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "broken", UID: "broken", Generation: 1},
+		Spec: v1.ApplicationPolicySpec{Mode: v1.PolicyWarn, Rules: []v1.PolicyRule{
+			{Name: "missing-field", Expression: `object.spec.team == "a"`},
+			{Name: "syntax", Expression: `object.spec.imageName ==`},
+		}},
+	})
+
+	violations := evaluate(t, evaluator, newApplication(v1.ApplicationSpec{ImageName: "nginx"}))
+	if len(violations) != 2 {
+		t.Fatalf("expected both rules to be violated, got %v", violations)
+	}
+	if !strings.HasPrefix(violations[0].Message, "rule evaluation failed") {
+		t.Errorf("expected the evaluation of missing-field to fail, got %q", violations[0].Message)
+	}
+	if !strings.HasPrefix(violations[1].Message, "invalid rule") {
+		t.Errorf("expected syntax not to compile, got %q", violations[1].Message)
+	}
+	if len(violations.Enforced()) != 0 {
+		t.Errorf("expected the violations of a Warn policy not to be enforced, got %v", violations.Enforced())
+	}
+}
+
+func TestEvaluateRecompilesUpdatedPolicies(t *testing.T) {
+	applicationPolicy := &v1.ApplicationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicas", UID: "replicas", Generation: 1},
+		Spec: v1.ApplicationPolicySpec{Rules: []v1.PolicyRule{
+			{Name: "max", Expression: "object.spec.replicas <= 5"},
+		}},
+	}
+	evaluator, indexer := newEvaluator(applicationPolicy)
+	app := newApplication(v1.ApplicationSpec{ImageName: "nginx", Replicas: int32Ptr(4)})
+	if violations := evaluate(t, evaluator, app); len(violations) != 0 {
+		t.Fatalf("expected no violation, got %v", violations)
+	}
+
+	applicationPolicy = applicationPolicy.DeepCopy()
+	applicationPolicy.Generation = 2
+	applicationPolicy.Spec.Rules[0].Expression = "object.spec.replicas <= 3"
+	_ = indexer.Update(applicationPolicy)
+	violations := evaluate(t, evaluator, app)
+	if len(violations) != 1 || violations[0].Mode != v1.PolicyEnforce || violations[0].Message != "failed rule: object.spec.replicas <= 3" {
+		t.Errorf("expected the updated rule to be enforced, got %v", violations)
+	}
+
+	_ = indexer.Delete(applicationPolicy)
+	if violations := evaluate(t, evaluator, app); len(violations) != 0 {
+		t.Errorf("expected no violation once the policy is deleted, got %v", violations)
+	}
+}
+
+func TestValidateApplicationPolicy(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []v1.PolicyRule
+		err   string
+	}{
+		{name: "valid", rules: []v1.PolicyRule{{Name: "a", Expression: `object.spec.imageName.startsWith("registry.example.com/")`}}},
+		{name: "no rules", err: "spec.rules: Required value"},
+		{name: "syntax error", rules: []v1.PolicyRule{{Name: "a", Expression: "object.spec.replicas <"}}, err: "spec.rules[0].expression: Invalid value"},
+		{name: "not a bool", rules: []v1.PolicyRule{{Name: "a", Expression: `"true"`}}, err: "must evaluate to a bool"},
+		{
+			name:  "duplicate name",
+			rules: []v1.PolicyRule{{Name: "a", Expression: "true"}, {Name: "a", Expression: "true"}},
+			err:   `spec.rules[1].name: Duplicate value: "a"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := policy.ValidateApplicationPolicy(&v1.ApplicationPolicy{Spec: v1.ApplicationPolicySpec{Rules: test.rules}})
+			if test.err == "" {
+				if len(errs) > 0 {
+					t.Errorf("expected a valid policy, got %v", errs)
+				}
+				return
+			}
+			if !strings.Contains(errs.ToAggregate().Error(), test.err) {
+				t.Errorf("expected %q, got %v", test.err, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Package quota counts the usage of the Applications of a namespace against
+// its ApplicationQuotas.
+package quota
+
+import (
+	"fmt"
+	"github.com/artifakt-io/demo-controller/internal/template"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Exceeded is a limit of an ApplicationQuota exceeded by the usage of its
+// namespace.
+type Exceeded struct {
+	Quota    string
+	Resource string
+	Used     string
+	Hard     string
+}
+
+func (e Exceeded) String() string {
+	return fmt.Sprintf("%s: %s %s > %s", e.Quota, e.Resource, e.Used, e.Hard)
+}
+
+// ExceededList are the limits exceeded in a namespace.
+type ExceededList []Exceeded
+
+func (l ExceededList) String() string {
+	messages := make([]string, 0, len(l))
+	for _, e := range l {
+		messages = append(messages, e.String())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Checker checks the changes of the usage of the Applications of a namespace
+// against its ApplicationQuotas.
+type Checker struct {
+	Quotas       listers.ApplicationQuotaLister
+	Applications listers.ApplicationLister
+	// Workload renders the workload of an application, whose requests are
+	// counted.
+	Workload func(*v1.Application) *appsv1.Deployment
+	// Defaulting are the defaults applied to the spec of the applications
+	// before their workload is rendered.
+	Defaulting v1.DefaultingOptions
+	// Templates, when set, applies the templates of the applications to
+	// their spec before it is defaulted.
+	Templates *template.Resolver
+}
+
+// NewChecker returns a checker counting the workloads rendered by workload.
+func NewChecker(quotas listers.ApplicationQuotaLister, applications listers.ApplicationLister, workload func(*v1.Application) *appsv1.Deployment) *Checker {
+	return &Checker{Quotas: quotas, Applications: applications, Workload: workload}
+}
+
+// ApplicationUsage returns the usage of app, with its template applied and
+// its spec defaulted.
+func (c *Checker) ApplicationUsage(app *v1.Application) v1.ApplicationQuotaUsage {
+	defaulted := app.DeepCopy()
+	if c.Templates != nil {
+		// An application whose template is missing is counted without it,
+		// as its workload is not reconciled.
+		_ = c.Templates.Apply(defaulted)
+	}
+	v1.DefaultSpec(&defaulted.Spec, c.Defaulting)
+	return WorkloadUsage(c.Workload(defaulted))
+}
+
+// WorkloadUsage returns the usage of the workload of an application. The
+// requests of a container default to its limits, as they do for pods.
+func WorkloadUsage(workload *appsv1.Deployment) v1.ApplicationQuotaUsage {
+	usage := v1.ApplicationQuotaUsage{Applications: 1, Replicas: 1}
+	if workload.Spec.Replicas != nil {
+		usage.Replicas = *workload.Spec.Replicas
+	}
+	var cpu, memory int64
+	for _, container := range workload.Spec.Template.Spec.Containers {
+		cpu += request(container.Resources, corev1.ResourceCPU).MilliValue()
+		memory += request(container.Resources, corev1.ResourceMemory).Value()
+	}
+	usage.CPU = *resource.NewMilliQuantity(cpu*int64(usage.Replicas), resource.DecimalSI)
+	usage.Memory = *resource.NewQuantity(memory*int64(usage.Replicas), resource.BinarySI)
+	return usage
+}
+
+func request(resources corev1.ResourceRequirements, name corev1.ResourceName) *resource.Quantity {
+	if q, ok := resources.Requests[name]; ok {
+		return &q
+	}
+	if q, ok := resources.Limits[name]; ok {
+		return &q
+	}
+	return &resource.Quantity{}
+}
+
+// Add returns the sum of a and b.
+func Add(a, b v1.ApplicationQuotaUsage) v1.ApplicationQuotaUsage {
+	sum := v1.ApplicationQuotaUsage{
+		Applications: a.Applications + b.Applications,
+		Replicas:     a.Replicas + b.Replicas,
+		CPU:          a.CPU.DeepCopy(),
+		Memory:       a.Memory.DeepCopy(),
+	}
+	sum.CPU.Add(b.CPU)
+	sum.Memory.Add(b.Memory)
+	return sum
+}
+
+// Usage returns the usage of the Applications of namespace.
+func (c *Checker) Usage(namespace string) (v1.ApplicationQuotaUsage, error) {
+	return c.usage(namespace, "")
+}
+
+// usage returns the usage of the Applications of namespace but except.
+func (c *Checker) usage(namespace, except string) (v1.ApplicationQuotaUsage, error) {
+	apps, err := c.Applications.Applications(namespace).List(labels.Everything())
+	if err != nil {
+		return v1.ApplicationQuotaUsage{}, err
+	}
+	usage := v1.ApplicationQuotaUsage{CPU: *resource.NewMilliQuantity(0, resource.DecimalSI), Memory: *resource.NewQuantity(0, resource.BinarySI)}
+	for _, app := range apps {
+		if app.Name != except {
+			usage = Add(usage, c.ApplicationUsage(app))
+		}
+	}
+	return usage, nil
+}
+
+// Check returns the limits of the ApplicationQuotas of the namespace of app
+// which its new usage exceeds, when the usage of app changes from previous
+// to the usage of its spec. Limits already exceeded are only reported when
+// the change increases the usage further, so that the applications admitted
+// before a quota can still be updated or scaled down.
+func (c *Checker) Check(app *v1.Application, previous v1.ApplicationQuotaUsage) (ExceededList, error) {
+	quotas, err := c.Quotas.ApplicationQuotas(app.Namespace).List(labels.Everything())
+	if err != nil || len(quotas) == 0 {
+		return nil, err
+	}
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].Name < quotas[j].Name })
+
+	others, err := c.usage(app.Namespace, app.Name)
+	if err != nil {
+		return nil, err
+	}
+	after := Add(others, c.ApplicationUsage(app))
+	before := Add(others, previous)
+
+	var exceeded ExceededList
+	for _, quota := range quotas {
+		exceeded = append(exceeded, exceededLimits(quota, after, before)...)
+	}
+	return exceeded, nil
+}
+
+func exceededLimits(quota *v1.ApplicationQuota, after, before v1.ApplicationQuotaUsage) ExceededList {
+	var exceeded ExceededList
+	count := func(name string, hard *int32, after, before int32) {
+		if hard != nil && after > *hard && after > before {
+			exceeded = append(exceeded, Exceeded{Quota: quota.Name, Resource: name,
+				Used: strconv.Itoa(int(after)), Hard: strconv.Itoa(int(*hard))})
+		}
+	}
+	quantity := func(name string, hard *resource.Quantity, after, before resource.Quantity) {
+		if hard != nil && after.Cmp(*hard) > 0 && after.Cmp(before) > 0 {
+			exceeded = append(exceeded, Exceeded{Quota: quota.Name, Resource: name,
+				Used: after.String(), Hard: hard.String()})
+		}
+	}
+	count("applications", quota.Spec.Applications, after.Applications, before.Applications)
+	count("replicas", quota.Spec.Replicas, after.Replicas, before.Replicas)
+	quantity("cpu", quota.Spec.CPU, after.CPU, before.CPU)
+	quantity("memory", quota.Spec.Memory, after.Memory, before.Memory)
+	return exceeded
+}
@@ -0,0 +1,161 @@
+package quota_test
+
+import (
+	"github.com/artifakt-io/demo-controller/internal/controller"
+	"github.com/artifakt-io/demo-controller/internal/quota"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"testing"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func resourcePtr(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func newApplication(name string, replicas int32, cpu string) *v1.Application {
+	app := &v1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+		Spec:       v1.ApplicationSpec{ImageName: "nginx:1.21", Replicas: int32Ptr(replicas)},
+	}
+	if cpu != "" {
+		app.Spec.Resources = &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+		}
+	}
+	return app
+}
+
+func newChecker(quotas []*v1.ApplicationQuota, apps ...*v1.Application) *quota.Checker {
+	quotaIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, applicationQuota := range quotas {
+		_ = quotaIndexer.Add(applicationQuota)
+	}
+	appIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, app := range apps {
+		_ = appIndexer.Add(app)
+	}
+	return quota.NewChecker(listers.NewApplicationQuotaLister(quotaIndexer), listers.NewApplicationLister(appIndexer), controller.NewDeployment)
+}
+
+func TestWorkloadUsage(t *testing.T) {
+	app := newApplication("test", 3, "250m")
+	app.Spec.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")}
+
+	usage := quota.WorkloadUsage(controller.NewDeployment(app))
+	if usage.Applications != 1 || usage.Replicas != 3 {
+		t.Errorf("unexpected usage of %d applications and %d replicas, expected 1 and 3", usage.Applications, usage.Replicas)
+	}
+	if usage.CPU.String() != "750m" {
+		t.Errorf("unexpected cpu usage %s, expected 750m", usage.CPU.String())
+	}
+	if usage.Memory.String() != "384Mi" {
+		t.Errorf("unexpected memory usage %s, expected 384Mi, requests default to limits", usage.Memory.String())
+	}
+}
+
+func TestCheck(t *testing.T) {
+	quotas := []*v1.ApplicationQuota{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: metav1.NamespaceDefault},
+			Spec:       v1.ApplicationQuotaSpec{Applications: int32Ptr(2), Replicas: int32Ptr(4)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: metav1.NamespaceDefault},
+			Spec:       v1.ApplicationQuotaSpec{CPU: resourcePtr("1")},
+		},
+	}
+	existing := newApplication("existing", 2, "250m")
+
+	tests := []struct {
+		name     string
+		apps     []*v1.Application
+		app      *v1.Application
+		previous *v1.Application
+		expected string
+	}{
+		{
+			name:     "new application within limits",
+			apps:     []*v1.Application{existing},
+			app:      newApplication("test", 2, "100m"),
+			expected: "",
+		},
+		{
+			name:     "too many applications",
+			apps:     []*v1.Application{existing, newApplication("other", 1, "")},
+			app:      newApplication("test", 1, ""),
+			expected: "team: applications 3 > 2",
+		},
+		{
+			name:     "scale up above the replicas",
+			apps:     []*v1.Application{existing},
+			app:      newApplication("test", 3, ""),
+			previous: newApplication("test", 1, ""),
+			expected: "team: replicas 5 > 4",
+		},
+		{
+			name:     "requests above the cpu",
+			apps:     []*v1.Application{existing},
+			app:      newApplication("test", 2, "300m"),
+			expected: "compute: cpu 1100m > 1",
+		},
+		{
+			name:     "several limits exceeded",
+			apps:     []*v1.Application{existing},
+			app:      newApplication("test", 3, "200m"),
+			previous: newApplication("test", 1, ""),
+			expected: "compute: cpu 1100m > 1; team: replicas 5 > 4",
+		},
+		{
+			name:     "update over quota not increasing the usage",
+			apps:     []*v1.Application{existing},
+			app:      newApplication("test", 3, ""),
+			previous: newApplication("test", 3, ""),
+			expected: "",
+		},
+		{
+			name:     "scale down over quota",
+			apps:     []*v1.Application{existing},
+			app:      newApplication("test", 4, ""),
+			previous: newApplication("test", 5, ""),
+			expected: "",
+		},
+		{
+			name:     "applications of other namespaces are not counted",
+			apps:     []*v1.Application{existing, {ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"}, Spec: v1.ApplicationSpec{ImageName: "nginx", Replicas: int32Ptr(10)}}},
+			app:      newApplication("test", 2, ""),
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := newChecker(quotas, append(tt.apps, tt.app)...)
+			var previous v1.ApplicationQuotaUsage
+			if tt.previous != nil {
+				previous = checker.ApplicationUsage(tt.previous)
+			}
+			exceeded, err := checker.Check(tt.app, previous)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exceeded.String() != tt.expected {
+				t.Errorf("unexpected exceeded limits %q, expected %q", exceeded.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckWithoutQuota(t *testing.T) {
+	checker := newChecker(nil)
+	exceeded, err := checker.Check(newApplication("test", 100, "100"), v1.ApplicationQuotaUsage{})
+	if err != nil || len(exceeded) != 0 {
+		t.Errorf("unexpected exceeded limits %v and error %v without quota", exceeded, err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package template resolves the ApplicationTemplates and
+// ClusterApplicationTemplates the Applications are based on.
+package template
+
+import (
+	"fmt"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// IndexName is the name of the index of the Applications by the key of the
+// template they refer to. See IndexFunc.
+const IndexName = "templateRef"
+
+// Key returns the key indexing the Applications based on the template kind
+// name, in namespace for an ApplicationTemplate.
+func Key(kind, namespace, name string) string {
+	if kind == v1.ClusterApplicationTemplateKind {
+		return kind + "/" + name
+	}
+	return kind + "/" + namespace + "/" + name
+}
+
+// IndexFunc indexes the Applications by the key of the template they refer
+// to, so that the Applications based on a template are requeued when it
+// changes.
+func IndexFunc(obj interface{}) ([]string, error) {
+	app, ok := obj.(*v1.Application)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object %T in the %s index", obj, IndexName)
+	}
+	ref := app.Spec.TemplateRef
+	if ref == nil {
+		return nil, nil
+	}
+	return []string{Key(v1.TemplateKind(ref), app.Namespace, ref.Name)}, nil
+}
+
+// Resolver reads the templates from listers.
+type Resolver struct {
+	Templates listers.ApplicationTemplateLister
+	// ClusterTemplates, when nil, finds no ClusterApplicationTemplate.
+	ClusterTemplates listers.ClusterApplicationTemplateLister
+}
+
+// NewResolver returns a resolver reading the templates from templates and
+// clusterTemplates.
+func NewResolver(templates listers.ApplicationTemplateLister, clusterTemplates listers.ClusterApplicationTemplateLister) *Resolver {
+	return &Resolver{Templates: templates, ClusterTemplates: clusterTemplates}
+}
+
+// Get returns the spec of the template ref refers to from namespace. A
+// template missing from the listers is a NotFound error.
+func (r *Resolver) Get(namespace string, ref *v1.TemplateReference) (*v1.ApplicationTemplateSpec, error) {
+	switch kind := v1.TemplateKind(ref); kind {
+	case v1.ApplicationTemplateKind:
+		template, err := r.Templates.ApplicationTemplates(namespace).Get(ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		return &template.Spec, nil
+	case v1.ClusterApplicationTemplateKind:
+		if r.ClusterTemplates == nil {
+			return nil, errors.NewNotFound(v1.Resource("clusterapplicationtemplate"), ref.Name)
+		}
+		template, err := r.ClusterTemplates.Get(ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		return &template.Spec, nil
+	default:
+		return nil, errors.NewBadRequest(fmt.Sprintf("unsupported template kind %q", kind))
+	}
+}
+
+// Apply applies the template app refers to, if any, to its spec. See
+// v1.ApplyTemplate.
+func (r *Resolver) Apply(app *v1.Application) error {
+	if app.Spec.TemplateRef == nil {
+		return nil
+	}
+	spec, err := r.Get(app.Namespace, app.Spec.TemplateRef)
+	if err != nil {
+		return err
+	}
+	v1.ApplyTemplate(&app.Spec, spec)
+	return nil
+}
@@ -0,0 +1,199 @@
+package template_test
+
+import (
+	"github.com/artifakt-io/demo-controller/internal/template"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/client-go/tools/cache"
+	"reflect"
+	"testing"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+var webTemplate = v1.ApplicationTemplateSpec{
+	Replicas: int32Ptr(3),
+	Resources: &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+	},
+	ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+	Labels:           map[string]string{"team": "a", "tier": "web"},
+	LivenessProbe:    &corev1.Probe{PeriodSeconds: 30},
+	NodeSelector:     map[string]string{"pool": "apps"},
+	Tolerations:      []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+	Rollout:          &v1.RolloutSpec{Canary: &v1.CanaryStrategy{Steps: []v1.CanaryStep{{SetWeight: int32Ptr(50)}}}},
+}
+
+func newResolver(objects ...interface{}) *template.Resolver {
+	templates := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	clusterTemplates := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, obj := range objects {
+		switch obj.(type) {
+		case *v1.ApplicationTemplate:
+			_ = templates.Add(obj)
+		case *v1.ClusterApplicationTemplate:
+			_ = clusterTemplates.Add(obj)
+		}
+	}
+	return template.NewResolver(listers.NewApplicationTemplateLister(templates), listers.NewClusterApplicationTemplateLister(clusterTemplates))
+}
+
+func newApplication(ref *v1.TemplateReference, spec v1.ApplicationSpec) *v1.Application {
+	spec.ImageName = "nginx:1.21"
+	spec.TemplateRef = ref
+	return &v1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: metav1.NamespaceDefault},
+		Spec:       spec,
+	}
+}
+
+func TestApply(t *testing.T) {
+	resolver := newResolver(&v1.ApplicationTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: metav1.NamespaceDefault},
+		Spec:       webTemplate,
+	})
+	ref := &v1.TemplateReference{Name: "web"}
+
+	tests := []struct {
+		name     string
+		spec     v1.ApplicationSpec
+		expected func(*v1.ApplicationSpec)
+	}{
+		{
+			name: "unset fields",
+			expected: func(spec *v1.ApplicationSpec) {
+				spec.Replicas = webTemplate.Replicas
+				spec.Resources = webTemplate.Resources
+				spec.ImagePullSecrets = webTemplate.ImagePullSecrets
+				spec.Labels = webTemplate.Labels
+				spec.LivenessProbe = webTemplate.LivenessProbe
+				spec.NodeSelector = webTemplate.NodeSelector
+				spec.Tolerations = webTemplate.Tolerations
+				spec.Rollout = webTemplate.Rollout
+			},
+		},
+		{
+			name: "overrides",
+			spec: v1.ApplicationSpec{
+				Replicas: int32Ptr(1),
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+				},
+				Labels:        map[string]string{"tier": "api"},
+				LivenessProbe: &corev1.Probe{PeriodSeconds: 5},
+				Tolerations:   []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}},
+				Rollout:       &v1.RolloutSpec{BlueGreen: &v1.BlueGreenStrategy{}},
+			},
+			expected: func(spec *v1.ApplicationSpec) {
+				spec.Resources = &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				}
+				spec.ImagePullSecrets = webTemplate.ImagePullSecrets
+				spec.Labels = map[string]string{"team": "a", "tier": "api"}
+				spec.NodeSelector = webTemplate.NodeSelector
+			},
+		},
+		{
+			name: "promote keeps the strategy of the template",
+			spec: v1.ApplicationSpec{Replicas: int32Ptr(1), Rollout: &v1.RolloutSpec{Promote: true}},
+			expected: func(spec *v1.ApplicationSpec) {
+				spec.Resources = webTemplate.Resources
+				spec.ImagePullSecrets = webTemplate.ImagePullSecrets
+				spec.Labels = webTemplate.Labels
+				spec.LivenessProbe = webTemplate.LivenessProbe
+				spec.NodeSelector = webTemplate.NodeSelector
+				spec.Tolerations = webTemplate.Tolerations
+				spec.Rollout = &v1.RolloutSpec{Canary: webTemplate.Rollout.Canary, Promote: true}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newApplication(ref, test.spec)
+			expected := app.DeepCopy()
+			test.expected(&expected.Spec)
+			if err := resolver.Apply(app); err != nil {
+				t.Fatal(err)
+			}
+			if !equality.Semantic.DeepEqual(app.Spec, expected.Spec) {
+				t.Errorf("unexpected spec:\n%s", diff.ObjectGoPrintSideBySide(expected.Spec, app.Spec))
+			}
+		})
+	}
+}
+
+func TestApplyDoesNotShareTheTemplate(t *testing.T) {
+	applicationTemplate := &v1.ApplicationTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: metav1.NamespaceDefault},
+		Spec:       *webTemplate.DeepCopy(),
+	}
+	app := newApplication(&v1.TemplateReference{Name: "web"}, v1.ApplicationSpec{})
+	if err := newResolver(applicationTemplate).Apply(app); err != nil {
+		t.Fatal(err)
+	}
+	app.Spec.Labels["team"] = "b"
+	*app.Spec.Replicas = 10
+	if !reflect.DeepEqual(applicationTemplate.Spec, webTemplate) {
+		t.Errorf("the template was modified through the application")
+	}
+}
+
+func TestApplyClusterTemplate(t *testing.T) {
+	resolver := newResolver(
+		&v1.ApplicationTemplate{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: metav1.NamespaceDefault}, Spec: v1.ApplicationTemplateSpec{Replicas: int32Ptr(2)}},
+		&v1.ClusterApplicationTemplate{ObjectMeta: metav1.ObjectMeta{Name: "web"}, Spec: v1.ApplicationTemplateSpec{Replicas: int32Ptr(5)}},
+	)
+	app := newApplication(&v1.TemplateReference{Kind: v1.ClusterApplicationTemplateKind, Name: "web"}, v1.ApplicationSpec{})
+	if err := resolver.Apply(app); err != nil {
+		t.Fatal(err)
+	}
+	if *app.Spec.Replicas != 5 {
+		t.Errorf("expected the replicas of the cluster template, got %d", *app.Spec.Replicas)
+	}
+}
+
+func TestApplyMissingTemplate(t *testing.T) {
+	resolver := newResolver(&v1.ApplicationTemplate{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "other"}})
+	app := newApplication(&v1.TemplateReference{Name: "web"}, v1.ApplicationSpec{})
+	if err := resolver.Apply(app); !errors.IsNotFound(err) {
+		t.Errorf("expected a NotFound error for a template of another namespace, got %v", err)
+	}
+	if err := resolver.Apply(newApplication(nil, v1.ApplicationSpec{})); err != nil {
+		t.Errorf("unexpected error without template: %v", err)
+	}
+
+	resolver.ClusterTemplates = nil
+	app = newApplication(&v1.TemplateReference{Kind: v1.ClusterApplicationTemplateKind, Name: "web"}, v1.ApplicationSpec{})
+	if err := resolver.Apply(app); !errors.IsNotFound(err) {
+		t.Errorf("expected a NotFound error without cluster templates, got %v", err)
+	}
+}
+
+func TestIndexFunc(t *testing.T) {
+	tests := []struct {
+		ref      *v1.TemplateReference
+		expected []string
+	}{
+		{ref: nil, expected: nil},
+		{ref: &v1.TemplateReference{Name: "web"}, expected: []string{"ApplicationTemplate/default/web"}},
+		{ref: &v1.TemplateReference{Kind: v1.ClusterApplicationTemplateKind, Name: "web"}, expected: []string{"ClusterApplicationTemplate/web"}},
+	}
+	for _, test := range tests {
+		keys, err := template.IndexFunc(newApplication(test.ref, v1.ApplicationSpec{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(keys, test.expected) {
+			t.Errorf("expected keys %v for %+v, got %v", test.expected, test.ref, keys)
+		}
+	}
+}
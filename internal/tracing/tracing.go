@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"flag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"k8s.io/klog/v2"
+)
+
+// Config holds the settings of the OTLP trace exporter.
+type Config struct {
+	// Endpoint is the host:port of the OTLP/HTTP collector. Tracing is
+	// disabled when empty.
+	Endpoint string
+	// Insecure sends the spans over plain HTTP.
+	Insecure bool
+	// SampleRatio is the ratio of reconciles traced.
+	SampleRatio float64
+}
+
+// AddFlags registers the tracing flags on fs.
+func (c *Config) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Endpoint, "otlp-endpoint", "", "The host:port of the OTLP/HTTP collector receiving the traces of the reconciles. Tracing is disabled when empty.")
+	fs.BoolVar(&c.Insecure, "otlp-insecure", false, "Send traces to the OTLP collector over plain HTTP.")
+	fs.Float64Var(&c.SampleRatio, "trace-sample-ratio", 1, "The ratio of reconciles traced.")
+}
+
+// Setup installs the global tracer provider exporting spans over OTLP as
+// configured. It returns a function flushing the pending spans and stopping
+// the provider.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	if config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String("demo-controller"))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	klog.Infof("Exporting traces to %s", config.Endpoint)
+	return provider.Shutdown, nil
+}
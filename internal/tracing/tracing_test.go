@@ -0,0 +1,73 @@
+package tracing_test
+
+import (
+	"context"
+	"github.com/artifakt-io/demo-controller/internal/tracing"
+	"github.com/golang/protobuf/proto"
+	"go.opentelemetry.io/otel"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// collector is a local OTLP/HTTP collector recording the names of the spans
+// it receives.
+type collector struct {
+	mu    sync.Mutex
+	spans []string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || r.URL.Path != "/v1/traces" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	request := &coltracepb.ExportTraceServiceRequest{}
+	if err := proto.Unmarshal(body, request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, resourceSpans := range request.ResourceSpans {
+		for _, librarySpans := range resourceSpans.InstrumentationLibrarySpans {
+			for _, span := range librarySpans.Spans {
+				c.spans = append(c.spans, span.Name)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestSetupExportsSpans(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:    strings.TrimPrefix(server.URL, "http://"),
+		Insecure:    true,
+		SampleRatio: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "SyncHandler")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error shutting down: %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.spans) != 1 || c.spans[0] != "SyncHandler" {
+		t.Errorf("expected the collector to receive the SyncHandler span, got %v", c.spans)
+	}
+}
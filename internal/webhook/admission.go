@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"net/http"
+)
+
+// maxReviewSize bounds the size of the admission reviews read.
+const maxReviewSize = 3 * 1024 * 1024
+
+// reviewFunc answers an admission request.
+type reviewFunc func(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+// admissionHandler serves the admission.k8s.io/v1 AdmissionReviews sent to a
+// webhook with review.
+func admissionHandler(review reviewFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var in admissionv1.AdmissionReview
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReviewSize)).Decode(&in); err != nil {
+			http.Error(w, fmt.Sprintf("invalid admission review: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if in.Request == nil {
+			http.Error(w, "admission review without request", http.StatusBadRequest)
+			return
+		}
+
+		response := review(r.Context(), in.Request)
+		response.UID = in.Request.UID
+		klog.V(4).InfoS("Reviewed admission request", "path", r.URL.Path, "operation", in.Request.Operation,
+			"object", klog.KRef(in.Request.Namespace, in.Request.Name), "allowed", response.Allowed)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(admissionv1.AdmissionReview{TypeMeta: in.TypeMeta, Response: response}); err != nil {
+			klog.Errorf("Error writing admission review: %s", err.Error())
+		}
+	})
+}
+
+// allowed admits a request.
+func allowed() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+// jsonPatchOperation is an RFC 6902 JSON patch operation.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// patched admits a request, mutating its object with patch.
+func patched(patch []jsonPatchOperation) *admissionv1.AdmissionResponse {
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		return denied(err)
+	}
+	patchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{Allowed: true, Patch: raw, PatchType: &patchType}
+}
+
+// denied rejects a request with err, which keeps its status, such as the
+// field errors of an Invalid error, when it is an API error.
+func denied(err error) *admissionv1.AdmissionResponse {
+	if status, ok := err.(errors.APIStatus); ok {
+		result := status.Status()
+		return &admissionv1.AdmissionResponse{Result: &result}
+	}
+	return &admissionv1.AdmissionResponse{Result: &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusBadRequest,
+		Reason:  metav1.StatusReasonBadRequest,
+		Message: err.Error(),
+	}}
+}
@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/artifakt-io/demo-controller/internal/controller"
+	"github.com/artifakt-io/demo-controller/internal/policy"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	v2 "github.com/artifakt-io/demo-controller/pkg/apis/application/v2"
+	"github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/scheme"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// ValidateApplicationPath is the path of the validating webhook of
+	// Applications.
+	ValidateApplicationPath = "/validate-application"
+	// DefaultApplicationPath is the path of the defaulting webhook of
+	// Applications.
+	DefaultApplicationPath = "/default-application"
+	// ValidateApplicationPolicyPath is the path of the validating webhook
+	// of ApplicationPolicies.
+	ValidateApplicationPolicyPath = "/validate-applicationpolicy"
+)
+
+// defaultApplication patches the Applications created and updated with the
+// defaults of their spec, so that the stored object shows the effective
+// configuration.
+func (s *Server) defaultApplication(_ context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+	app := &v1.Application{}
+	if err := decode(req.Object, app); err != nil {
+		return denied(errors.NewBadRequest(err.Error()))
+	}
+
+	defaulted := app.DeepCopy()
+	v1.Default(defaulted, s.Defaulting)
+	var patch []jsonPatchOperation
+	if !equality.Semantic.DeepEqual(app.Labels, defaulted.Labels) {
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/metadata/labels", Value: defaulted.Labels})
+	}
+	if !equality.Semantic.DeepEqual(app.Spec, defaulted.Spec) {
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/spec", Value: defaulted.Spec})
+	}
+	if len(patch) == 0 {
+		return allowed()
+	}
+	return patched(patch)
+}
+
+// validateApplication denies the creation or update of an Application whose
+// spec is invalid, which violates an enforced ApplicationPolicy or which
+// would exceed an ApplicationQuota of its namespace. The violations of the
+// policies in Warn mode are returned as warnings.
+func (s *Server) validateApplication(_ context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+	app := &v1.Application{}
+	if err := decode(req.Object, app); err != nil {
+		return denied(errors.NewBadRequest(err.Error()))
+	}
+	errs := v1.ValidateApplication(app)
+	errs = append(errs, validateContainers(app)...)
+	if len(errs) > 0 {
+		return denied(errors.NewInvalid(v1.Kind("Application"), app.Name, errs))
+	}
+
+	response := allowed()
+	if s.Policies != nil {
+		// Policies see the application and its workload as the controller
+		// reconciles them. An application whose template is missing is
+		// evaluated without it, the controller does not reconcile it.
+		defaulted := app.DeepCopy()
+		if s.Templates != nil {
+			_ = s.Templates.Apply(defaulted)
+		}
+		v1.DefaultSpec(&defaulted.Spec, s.Defaulting)
+		violations, err := s.Policies.Evaluate(defaulted, controller.NewDeployment(defaulted))
+		if err != nil {
+			return denied(errors.NewInternalError(err))
+		}
+		if enforced := violations.Enforced(); len(enforced) > 0 {
+			return denied(errors.NewForbidden(v1.Resource("applications"), app.Name,
+				fmt.Errorf("violates enforced policies: %s", enforced.String())))
+		}
+		for _, violation := range violations {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("violates policy %s", violation.String()))
+		}
+	}
+
+	if s.Quotas != nil {
+		var previous v1.ApplicationQuotaUsage
+		if req.Operation == admissionv1.Update {
+			old := &v1.Application{}
+			if err := decode(req.OldObject, old); err != nil {
+				return denied(errors.NewBadRequest(err.Error()))
+			}
+			previous = s.Quotas.ApplicationUsage(old)
+		}
+		exceeded, err := s.Quotas.Check(app, previous)
+		if err != nil {
+			return denied(errors.NewInternalError(err))
+		}
+		if len(exceeded) > 0 {
+			return denied(errors.NewForbidden(v1.Resource("applications"), app.Name,
+				fmt.Errorf("exceeds ApplicationQuotas: %s", exceeded.String())))
+		}
+	}
+	return response
+}
+
+// validateContainers returns an error when the v2 Application app was
+// converted from records more than one container, as the controller only
+// deploys the first one. v2 requests reach the webhook converted to v1.
+func validateContainers(app *v1.Application) field.ErrorList {
+	recorded, ok := app.Annotations[v2.ContainersAnnotation]
+	if !ok {
+		return nil
+	}
+	var containers []v2.Container
+	if err := json.Unmarshal([]byte(recorded), &containers); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("metadata", "annotations").Key(v2.ContainersAnnotation), recorded, err.Error())}
+	}
+	if len(containers) > 1 {
+		return field.ErrorList{field.TooMany(field.NewPath("spec", "containers"), len(containers), 1)}
+	}
+	return nil
+}
+
+// validateApplicationPolicy denies the creation or update of an
+// ApplicationPolicy whose rules do not compile.
+func validateApplicationPolicy(_ context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+	applicationPolicy := &v1.ApplicationPolicy{}
+	if err := decode(req.Object, applicationPolicy); err != nil {
+		return denied(errors.NewBadRequest(err.Error()))
+	}
+	if errs := policy.ValidateApplicationPolicy(applicationPolicy); len(errs) > 0 {
+		return denied(errors.NewInvalid(v1.Kind("ApplicationPolicy"), applicationPolicy.Name, errs))
+	}
+	return allowed()
+}
+
+// decode decodes the object of an admission request into into.
+func decode(raw runtime.RawExtension, into runtime.Object) error {
+	if _, _, err := scheme.Codecs.UniversalDeserializer().Decode(raw.Raw, nil, into); err != nil {
+		return fmt.Errorf("unable to decode object: %w", err)
+	}
+	return nil
+}
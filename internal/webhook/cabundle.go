@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"time"
+)
+
+// PublishCABundle returns a CertManager.Publish func installing the CA
+// bundle in the webhooks of the ValidatingWebhookConfiguration validating
+// and of the MutatingWebhookConfiguration mutating. Empty names are skipped.
+func PublishCABundle(client kubernetes.Interface, validating, mutating string) func(ctx context.Context, caBundle []byte) error {
+	return func(ctx context.Context, caBundle []byte) error {
+		if validating != "" {
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				configuration, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, validating, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				for i := range configuration.Webhooks {
+					clientConfig := &configuration.Webhooks[i].ClientConfig
+					clientConfig.CABundle = mergeCABundle(caBundle, clientConfig.CABundle, time.Now())
+				}
+				_, err = client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(ctx, configuration, metav1.UpdateOptions{})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if mutating != "" {
+			return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				configuration, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, mutating, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				for i := range configuration.Webhooks {
+					clientConfig := &configuration.Webhooks[i].ClientConfig
+					clientConfig.CABundle = mergeCABundle(caBundle, clientConfig.CABundle, time.Now())
+				}
+				_, err = client.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(ctx, configuration, metav1.UpdateOptions{})
+				return err
+			})
+		}
+		return nil
+	}
+}
+
+// customResourceDefinitions is the resource of the CRDs, updated through the
+// dynamic client to avoid depending on the apiextensions-apiserver module.
+var customResourceDefinitions = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// PublishConversionCABundle returns a CertManager.Publish func installing
+// the CA bundle in the conversion webhook of the CustomResourceDefinition
+// crd. An empty name is skipped.
+func PublishConversionCABundle(client dynamic.Interface, crd string) func(ctx context.Context, caBundle []byte) error {
+	return func(ctx context.Context, caBundle []byte) error {
+		if crd == "" {
+			return nil
+		}
+		return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			definition, err := client.Resource(customResourceDefinitions).Get(ctx, crd, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			path := []string{"spec", "conversion", "webhook", "clientConfig", "caBundle"}
+			encoded, _, err := unstructured.NestedString(definition.Object, path...)
+			if err != nil {
+				return err
+			}
+			current, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				current = nil
+			}
+			merged := base64.StdEncoding.EncodeToString(mergeCABundle(caBundle, current, time.Now()))
+			if err := unstructured.SetNestedField(definition.Object, merged, path...); err != nil {
+				return err
+			}
+			_, err = client.Resource(customResourceDefinitions).Update(ctx, definition, metav1.UpdateOptions{})
+			return err
+		})
+	}
+}
+
+// PublishAll returns a CertManager.Publish func calling each of publish in
+// turn, stopping at the first error.
+func PublishAll(publish ...func(ctx context.Context, caBundle []byte) error) func(ctx context.Context, caBundle []byte) error {
+	return func(ctx context.Context, caBundle []byte) error {
+		for _, p := range publish {
+			if err := p(ctx, caBundle); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// mergeCABundle returns ca followed by the certificates of current which are
+// still valid at now. Keeping them lets the API server reach the webhook
+// replicas which did not rotate their certificate yet.
+func mergeCABundle(ca, current []byte, now time.Time) []byte {
+	merged := append([]byte(nil), ca...)
+	for rest := current; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || now.After(certificate.NotAfter) {
+			continue
+		}
+		encoded := pem.EncodeToMemory(block)
+		if bytes.Contains(merged, encoded) {
+			continue
+		}
+		merged = append(merged, encoded...)
+	}
+	return merged
+}
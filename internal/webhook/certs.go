@@ -0,0 +1,172 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"math/big"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCertificateValidity = 90 * 24 * time.Hour
+	defaultRenewBefore         = 30 * 24 * time.Hour
+	// rotationRetryPeriod is the delay before a failed rotation is retried.
+	rotationRetryPeriod = 10 * time.Second
+)
+
+// CertManager issues the serving certificate of the webhooks from a
+// self-signed CA, and issues a new CA and certificate RenewBefore they
+// expire. The CA is published with Publish before the certificate it signs
+// is served.
+type CertManager struct {
+	// DNSNames are the names of the webhook Service the certificate is
+	// valid for.
+	DNSNames    []string
+	Validity    time.Duration
+	RenewBefore time.Duration
+	// Publish installs the PEM encoded CA in the configurations of the
+	// webhooks.
+	Publish func(ctx context.Context, caBundle []byte) error
+	Clock   clock.Clock
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+}
+
+// NewCertManager returns a manager of the certificate of dnsNames publishing
+// its CA with publish.
+func NewCertManager(dnsNames []string, publish func(ctx context.Context, caBundle []byte) error) *CertManager {
+	return &CertManager{
+		DNSNames:    dnsNames,
+		Validity:    defaultCertificateValidity,
+		RenewBefore: defaultRenewBefore,
+		Publish:     publish,
+		Clock:       clock.RealClock{},
+	}
+}
+
+// GetCertificate returns the current certificate, for tls.Config.
+func (m *CertManager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.certificate == nil {
+		return nil, errors.New("no certificate issued yet")
+	}
+	return m.certificate, nil
+}
+
+// Ready returns an error until a certificate is served.
+func (m *CertManager) Ready() error {
+	_, err := m.GetCertificate(nil)
+	return err
+}
+
+// Run issues a certificate and renews it until ctx is done.
+func (m *CertManager) Run(ctx context.Context) {
+	for {
+		next := rotationRetryPeriod
+		if notAfter, err := m.Rotate(ctx); err != nil {
+			klog.Errorf("Error rotating the webhook certificate: %s", err.Error())
+		} else {
+			next = notAfter.Add(-m.RenewBefore).Sub(m.Clock.Now())
+			klog.Infof("Issued webhook certificate valid until %s, renewed in %s", notAfter.Format(time.RFC3339), next)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.Clock.After(next):
+		}
+	}
+}
+
+// Rotate issues a new CA and certificate, publishes the CA and serves the
+// certificate. It returns the expiry of the certificate.
+func (m *CertManager) Rotate(ctx context.Context) (time.Time, error) {
+	now := m.Clock.Now()
+	certificate, caPEM, err := newCertificate(m.DNSNames, now, now.Add(m.Validity))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := m.Publish(ctx, caPEM); err != nil {
+		return time.Time{}, fmt.Errorf("unable to publish the CA bundle: %w", err)
+	}
+
+	m.mu.Lock()
+	m.certificate = certificate
+	m.mu.Unlock()
+	return certificate.Leaf.NotAfter, nil
+}
+
+// newCertificate returns a certificate for dnsNames valid until notAfter,
+// signed by a new CA returned PEM encoded.
+func newCertificate(dnsNames []string, now, notAfter time.Time) (*tls.Certificate, []byte, error) {
+	// Certificates are valid an hour early to tolerate clock skew.
+	notBefore := now.Add(-time.Hour)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          serialNumber(now),
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("demo-controller-webhook-ca@%d", now.Unix())},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber(now),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certificate := &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+	return certificate, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), nil
+}
+
+func serialNumber(now time.Time) *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return big.NewInt(now.UnixNano())
+	}
+	return serial
+}
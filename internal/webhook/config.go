@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config configures the webhook server and its certificate.
+type Config struct {
+	Addr string
+	// ServiceName and ServiceNamespace locate the Service of the webhooks,
+	// which the serving certificate is issued for.
+	ServiceName      string
+	ServiceNamespace string
+	// ValidatingWebhookConfiguration is the name of the configuration whose
+	// CA bundle is kept up to date.
+	ValidatingWebhookConfiguration string
+	// MutatingWebhookConfiguration is the name of the configuration of the
+	// defaulting webhook whose CA bundle is kept up to date.
+	MutatingWebhookConfiguration string
+	// CustomResourceDefinition is the name of the CRD whose conversion
+	// webhook CA bundle is kept up to date.
+	CustomResourceDefinition string
+	// QualifyImageNames rewrites the image names to their fully qualified
+	// form.
+	QualifyImageNames bool
+	CertValidity      time.Duration
+	CertRenewBefore   time.Duration
+}
+
+// AddFlags registers the flags of c in fs.
+func (c *Config) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Addr, "webhook-addr", ":9443", "The address serving the admission webhooks over TLS.")
+	fs.StringVar(&c.ServiceName, "service-name", "demo-controller-webhook", "The name of the Service of the webhooks, which the serving certificate is issued for.")
+	fs.StringVar(&c.ServiceNamespace, "service-namespace", defaultNamespace(), "The namespace of the Service of the webhooks. Defaults to $POD_NAMESPACE.")
+	fs.StringVar(&c.ValidatingWebhookConfiguration, "validating-webhook-configuration", "demo-controller", "The ValidatingWebhookConfiguration whose CA bundle is kept up to date.")
+	fs.StringVar(&c.MutatingWebhookConfiguration, "mutating-webhook-configuration", "demo-controller", "The MutatingWebhookConfiguration whose CA bundle is kept up to date.")
+	fs.StringVar(&c.CustomResourceDefinition, "custom-resource-definition", "applications.cloudest.artifakt.io", "The CustomResourceDefinition whose conversion webhook CA bundle is kept up to date.")
+	fs.BoolVar(&c.QualifyImageNames, "qualify-image-names", false, "Rewrite the imageName of the applications to its fully qualified registry/repository:tag form.")
+	fs.DurationVar(&c.CertValidity, "cert-validity", defaultCertificateValidity, "The validity of the self-signed serving certificate.")
+	fs.DurationVar(&c.CertRenewBefore, "cert-renew-before", defaultRenewBefore, "How long before its expiry the serving certificate is renewed.")
+}
+
+// Validate returns an error when c cannot be used.
+func (c *Config) Validate() error {
+	if c.CertRenewBefore >= c.CertValidity {
+		return fmt.Errorf("--cert-renew-before (%s) must be less than --cert-validity (%s)", c.CertRenewBefore, c.CertValidity)
+	}
+	return nil
+}
+
+// DNSNames returns the names the Service of the webhooks is reached at.
+func (c *Config) DNSNames() []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", c.ServiceName, c.ServiceNamespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", c.ServiceName, c.ServiceNamespace),
+		fmt.Sprintf("%s.%s", c.ServiceName, c.ServiceNamespace),
+		c.ServiceName,
+	}
+}
+
+func defaultNamespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+	return "default"
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"net/http"
+)
+
+// ConvertPath is the path of the conversion webhook of the Application CRD.
+const ConvertPath = "/convert"
+
+// conversionReview is the apiextensions.k8s.io/v1 ConversionReview, declared
+// here to avoid depending on the apiextensions-apiserver module.
+type conversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *conversionRequest  `json:"request,omitempty"`
+	Response        *conversionResponse `json:"response,omitempty"`
+}
+
+type conversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+type conversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+// conversionHandler serves the ConversionReviews of the Application CRD,
+// converting the objects with the conversions of the scheme.
+func conversionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var in conversionReview
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReviewSize)).Decode(&in); err != nil {
+			http.Error(w, fmt.Sprintf("invalid conversion review: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if in.Request == nil {
+			http.Error(w, "conversion review without request", http.StatusBadRequest)
+			return
+		}
+
+		response := &conversionResponse{UID: in.Request.UID, Result: metav1.Status{Status: metav1.StatusSuccess}}
+		converted, err := convertObjects(in.Request.Objects, in.Request.DesiredAPIVersion)
+		if err != nil {
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+		} else {
+			response.ConvertedObjects = converted
+		}
+		klog.V(4).InfoS("Reviewed conversion request", "desiredAPIVersion", in.Request.DesiredAPIVersion,
+			"objects", len(in.Request.Objects), "result", response.Result.Status)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(conversionReview{TypeMeta: in.TypeMeta, Response: response}); err != nil {
+			klog.Errorf("Error writing conversion review: %s", err.Error())
+		}
+	})
+}
+
+// convertObjects converts objects to desiredAPIVersion.
+func convertObjects(objects []runtime.RawExtension, desiredAPIVersion string) ([]runtime.RawExtension, error) {
+	desired, err := schema.ParseGroupVersion(desiredAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]runtime.RawExtension, 0, len(objects))
+	for _, object := range objects {
+		in, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(object.Raw, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode object: %w", err)
+		}
+		if gvk.GroupVersion() == desired {
+			converted = append(converted, object)
+			continue
+		}
+		out, err := scheme.Scheme.ConvertToVersion(in, desired)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(out)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, runtime.RawExtension{Raw: raw})
+	}
+	return converted, nil
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/artifakt-io/demo-controller/internal/policy"
+	"github.com/artifakt-io/demo-controller/internal/quota"
+	"github.com/artifakt-io/demo-controller/internal/template"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"k8s.io/klog/v2"
+	"net/http"
+	"time"
+)
+
+// Server serves the admission webhooks over TLS with the certificate of
+// Certificates.
+type Server struct {
+	Addr         string
+	Certificates *CertManager
+	// Defaulting are the optional defaults set by the defaulting webhook.
+	Defaulting v1.DefaultingOptions
+	// Policies, when set, evaluates the ApplicationPolicies validated
+	// Applications must comply with.
+	Policies *policy.Evaluator
+	// Quotas, when set, checks the validated Applications against the
+	// ApplicationQuotas of their namespace.
+	Quotas *quota.Checker
+	// Templates, when set, applies the templates of the validated
+	// Applications before they are evaluated against the policies.
+	Templates *template.Resolver
+
+	mux *http.ServeMux
+}
+
+// NewServer returns a server listening on addr.
+func NewServer(addr string, certificates *CertManager) *Server {
+	s := &Server{Addr: addr, Certificates: certificates, mux: http.NewServeMux()}
+	s.mux.Handle(ValidateApplicationPath, admissionHandler(s.validateApplication))
+	s.mux.Handle(ValidateApplicationPolicyPath, admissionHandler(validateApplicationPolicy))
+	s.mux.Handle(DefaultApplicationPath, admissionHandler(s.defaultApplication))
+	s.mux.Handle(ConvertPath, conversionHandler())
+	return s
+}
+
+// Handle registers an additional handler.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Run serves until ctx is done.
+func (s *Server) Run(ctx context.Context) error {
+	server := &http.Server{
+		Addr:    s.Addr,
+		Handler: s.mux,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: s.Certificates.GetCertificate,
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Error shutting down webhook server: %s", err.Error())
+		}
+	}()
+
+	klog.Infof("Serving admission webhooks on %s", s.Addr)
+	if err := server.ListenAndServeTLS("", ""); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,559 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"github.com/artifakt-io/demo-controller/internal/controller"
+	"github.com/artifakt-io/demo-controller/internal/policy"
+	"github.com/artifakt-io/demo-controller/internal/quota"
+	"github.com/artifakt-io/demo-controller/internal/template"
+	"github.com/artifakt-io/demo-controller/internal/webhook"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	v2 "github.com/artifakt-io/demo-controller/pkg/apis/application/v2"
+	"github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/scheme"
+	listers "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	jsonpatch "github.com/evanphx/json-patch"
+	fuzz "github.com/google/gofuzz"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	testingclock "k8s.io/utils/clock/testing"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func newApplication(spec v1.ApplicationSpec) *v1.Application {
+	return &v1.Application{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: "Application"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: metav1.NamespaceDefault},
+		Spec:       spec,
+	}
+}
+
+func review(t *testing.T, handler http.Handler, path string, operation admissionv1.Operation, obj runtime.Object) *admissionv1.AdmissionResponse {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "42",
+			Operation: operation,
+			Name:      "test",
+			Namespace: metav1.NamespaceDefault,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var out admissionv1.AdmissionReview
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Response.UID != "42" {
+		t.Errorf("expected the response to carry the request UID, got %q", out.Response.UID)
+	}
+	return out.Response
+}
+
+func TestValidateApplication(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        v1.ApplicationSpec
+		annotations map[string]string
+		message     string
+	}{
+		{name: "valid", spec: v1.ApplicationSpec{ImageName: "nginx:1.21", Replicas: int32Ptr(2)}},
+		{name: "qualified", spec: v1.ApplicationSpec{ImageName: "registry.example.com:5000/team/app@sha256:" + strings.Repeat("a", 64)}},
+		{name: "empty image", spec: v1.ApplicationSpec{Replicas: int32Ptr(1)}, message: "spec.imageName: Required value"},
+		{name: "malformed image", spec: v1.ApplicationSpec{ImageName: "nginx:la test"}, message: `spec.imageName: Invalid value: "nginx:la test"`},
+		{name: "negative replicas", spec: v1.ApplicationSpec{ImageName: "nginx", Replicas: int32Ptr(-1)}, message: "spec.replicas: Invalid value: -1: must not be negative"},
+		{
+			name: "canary weight",
+			spec: v1.ApplicationSpec{ImageName: "nginx", Rollout: &v1.RolloutSpec{Canary: &v1.CanaryStrategy{
+				Steps: []v1.CanaryStep{{SetWeight: int32Ptr(150)}},
+			}}},
+			message: "spec.rollout.canary.steps[0].setWeight: Invalid value: 150: must be between 0 and 100",
+		},
+		{
+			name: "two strategies",
+			spec: v1.ApplicationSpec{ImageName: "nginx", Rollout: &v1.RolloutSpec{
+				Canary:    &v1.CanaryStrategy{Steps: []v1.CanaryStep{{SetWeight: int32Ptr(50)}}},
+				BlueGreen: &v1.BlueGreenStrategy{},
+			}},
+			message: "spec.rollout.blueGreen: Forbidden: may not be set together with canary",
+		},
+		{
+			name: "image update policy",
+			spec: v1.ApplicationSpec{ImageName: "nginx:1.21.0", ImageUpdatePolicy: &v1.ImageUpdatePolicy{Semver: ">=1.21.0 <1.22.0", Filter: `^[0-9.]+$`}},
+		},
+		{
+			name:    "invalid semver range",
+			spec:    v1.ApplicationSpec{ImageName: "nginx:1.21.0", ImageUpdatePolicy: &v1.ImageUpdatePolicy{Semver: "~1.21"}},
+			message: `spec.imageUpdatePolicy.semver: Invalid value: "~1.21"`,
+		},
+		{
+			name:    "image update policy on a digest",
+			spec:    v1.ApplicationSpec{ImageName: "nginx@sha256:" + strings.Repeat("a", 64), ImageUpdatePolicy: &v1.ImageUpdatePolicy{}},
+			message: "spec.imageUpdatePolicy: Forbidden: may not be set when imageName is pinned to a digest",
+		},
+		{
+			name:    "template without name",
+			spec:    v1.ApplicationSpec{ImageName: "nginx:1.21.0", TemplateRef: &v1.TemplateReference{}},
+			message: "spec.templateRef.name: Required value",
+		},
+		{
+			name:    "image verification without secret",
+			spec:    v1.ApplicationSpec{ImageName: "nginx:1.21.0", ImageVerification: &v1.ImageVerification{}},
+			message: "spec.imageVerification.publicKeysSecret.name: Required value",
+		},
+		{
+			name:        "renamed v2 container",
+			spec:        v1.ApplicationSpec{ImageName: "nginx:1.21.0"},
+			annotations: map[string]string{v2.ContainersAnnotation: `[{"name":"web","image":"nginx:1.21.0"}]`},
+		},
+		{
+			name:        "several v2 containers",
+			spec:        v1.ApplicationSpec{ImageName: "nginx:1.21.0"},
+			annotations: map[string]string{v2.ContainersAnnotation: `[{"name":"web","image":"nginx:1.21.0"},{"name":"sidecar","image":"envoy"}]`},
+			message:     "spec.containers: Too many: 2: must have at most 1 items",
+		},
+	}
+
+	s := webhook.NewServer(":0", nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, operation := range []admissionv1.Operation{admissionv1.Create, admissionv1.Update} {
+				app := newApplication(test.spec)
+				app.Annotations = test.annotations
+				response := review(t, s, webhook.ValidateApplicationPath, operation, app)
+				if test.message == "" {
+					if !response.Allowed {
+						t.Errorf("%s: expected the application to be allowed, got %+v", operation, response.Result)
+					}
+					continue
+				}
+				if response.Allowed || response.Result == nil || !strings.Contains(response.Result.Message, test.message) {
+					t.Errorf("%s: expected the application to be denied with %q, got %+v", operation, test.message, response.Result)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateApplicationAgainstPolicies(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = indexer.Add(&v1.ApplicationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "pinned", UID: "pinned", Generation: 1},
+		Spec: v1.ApplicationPolicySpec{Mode: v1.PolicyEnforce, Rules: []v1.PolicyRule{
+			{Name: "tag", Expression: `!object.spec.imageName.endsWith(":latest")`, Message: "latest is not allowed"},
+		}},
+	})
+	_ = indexer.Add(&v1.ApplicationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicas", UID: "replicas", Generation: 1},
+		Spec: v1.ApplicationPolicySpec{Mode: v1.PolicyWarn, Rules: []v1.PolicyRule{
+			{Name: "ha", Expression: "workload.spec.replicas >= 2", Message: "run at least 2 replicas"},
+		}},
+	})
+	s := webhook.NewServer(":0", nil)
+	s.Policies = policy.NewEvaluator(listers.NewApplicationPolicyLister(indexer))
+
+	response := review(t, s, webhook.ValidateApplicationPath, admissionv1.Create, newApplication(v1.ApplicationSpec{ImageName: "nginx:latest", Replicas: int32Ptr(2)}))
+	if response.Allowed || response.Result == nil || response.Result.Reason != metav1.StatusReasonForbidden ||
+		!strings.Contains(response.Result.Message, "pinned/tag: latest is not allowed") {
+		t.Errorf("expected the application to be denied by the enforced policy, got %+v", response.Result)
+	}
+
+	// replicas defaults to 1, which the Warn policy reports.
+	response = review(t, s, webhook.ValidateApplicationPath, admissionv1.Create, newApplication(v1.ApplicationSpec{ImageName: "nginx:1.21"}))
+	if !response.Allowed {
+		t.Fatalf("expected the application to be allowed, got %+v", response.Result)
+	}
+	if expected := []string{"violates policy replicas/ha: run at least 2 replicas"}; !reflect.DeepEqual(response.Warnings, expected) {
+		t.Errorf("expected warnings %q, got %q", expected, response.Warnings)
+	}
+}
+
+func TestValidateApplicationAgainstQuotas(t *testing.T) {
+	quotas := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = quotas.Add(&v1.ApplicationQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: metav1.NamespaceDefault},
+		Spec:       v1.ApplicationQuotaSpec{Replicas: int32Ptr(4)},
+	})
+	apps := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	existing := newApplication(v1.ApplicationSpec{ImageName: "nginx:1.21", Replicas: int32Ptr(3)})
+	existing.Name = "existing"
+	_ = apps.Add(existing)
+	s := webhook.NewServer(":0", nil)
+	s.Quotas = quota.NewChecker(listers.NewApplicationQuotaLister(quotas), listers.NewApplicationLister(apps), controller.NewDeployment)
+
+	// replicas defaults to 1.
+	if response := review(t, s, webhook.ValidateApplicationPath, admissionv1.Create, newApplication(v1.ApplicationSpec{ImageName: "nginx:1.21"})); !response.Allowed {
+		t.Errorf("expected the application to be allowed, got %+v", response.Result)
+	}
+	response := review(t, s, webhook.ValidateApplicationPath, admissionv1.Create, newApplication(v1.ApplicationSpec{ImageName: "nginx:1.21", Replicas: int32Ptr(2)}))
+	if response.Allowed || response.Result == nil || response.Result.Reason != metav1.StatusReasonForbidden ||
+		!strings.Contains(response.Result.Message, "exceeds ApplicationQuotas: team: replicas 5 > 4") {
+		t.Errorf("expected the application to be denied by the quota, got %+v", response.Result)
+	}
+}
+
+func TestValidateApplicationPolicy(t *testing.T) {
+	s := webhook.NewServer(":0", nil)
+	newPolicy := func(expression string) *v1.ApplicationPolicy {
+		return &v1.ApplicationPolicy{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: "ApplicationPolicy"},
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec:       v1.ApplicationPolicySpec{Rules: []v1.PolicyRule{{Name: "rule", Expression: expression}}},
+		}
+	}
+
+	if response := review(t, s, webhook.ValidateApplicationPolicyPath, admissionv1.Create, newPolicy("object.spec.replicas <= 10")); !response.Allowed {
+		t.Errorf("expected the policy to be allowed, got %+v", response.Result)
+	}
+	response := review(t, s, webhook.ValidateApplicationPolicyPath, admissionv1.Create, newPolicy("object.spec.replicas <="))
+	if response.Allowed || response.Result == nil || !strings.Contains(response.Result.Message, "spec.rules[0].expression: Invalid value") {
+		t.Errorf("expected the policy to be denied, got %+v", response.Result)
+	}
+}
+
+func TestDefaultApplication(t *testing.T) {
+	s := webhook.NewServer(":0", nil)
+	s.Defaulting.QualifyImageName = true
+	app := newApplication(v1.ApplicationSpec{ImageName: "nginx", Size: v1.SizeMedium})
+
+	response := review(t, s, webhook.DefaultApplicationPath, admissionv1.Create, app)
+	defaulted := applyPatch(t, app, response)
+
+	expected := app.DeepCopy()
+	expected.Labels = map[string]string{v1.NameLabel: "test", v1.ManagedByLabel: v1.ManagedBy}
+	expected.Spec.ImageName = "index.docker.io/library/nginx:latest"
+	expected.Spec.Replicas = int32Ptr(2)
+	expected.Spec.Resources = &corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("250m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}}
+	if !equality.Semantic.DeepEqual(defaulted, expected) {
+		t.Errorf("expected defaulted application\n%+v\ngot\n%+v", expected, defaulted)
+	}
+
+	// The defaults are stable: a defaulted application is not patched again.
+	response = review(t, s, webhook.DefaultApplicationPath, admissionv1.Update, defaulted)
+	if !response.Allowed || response.Patch != nil {
+		t.Errorf("expected a defaulted application to be allowed as is, got patch %s", response.Patch)
+	}
+}
+
+// applyPatch returns app patched by the JSON patch of response.
+func applyPatch(t *testing.T, app *v1.Application, response *admissionv1.AdmissionResponse) *v1.Application {
+	if !response.Allowed || response.PatchType == nil || *response.PatchType != admissionv1.PatchTypeJSONPatch {
+		t.Fatalf("expected the application to be allowed with a JSON patch, got %+v", response)
+	}
+	patch, err := jsonpatch.DecodePatch(response.Patch)
+	if err != nil {
+		t.Fatalf("invalid patch %s: %v", response.Patch, err)
+	}
+	raw, _ := json.Marshal(app)
+	patchedRaw, err := patch.Apply(raw)
+	if err != nil {
+		t.Fatalf("error applying patch %s: %v", response.Patch, err)
+	}
+	patched := &v1.Application{}
+	if err := json.Unmarshal(patchedRaw, patched); err != nil {
+		t.Fatal(err)
+	}
+	return patched
+}
+
+func TestDefaultApplicationWithTemplate(t *testing.T) {
+	s := webhook.NewServer(":0", nil)
+	s.Defaulting.QualifyImageName = true
+	app := newApplication(v1.ApplicationSpec{ImageName: "nginx", TemplateRef: &v1.TemplateReference{Name: "web"}})
+
+	defaulted := applyPatch(t, app, review(t, s, webhook.DefaultApplicationPath, admissionv1.Create, app))
+	// The replicas are left for the template to set.
+	expected := app.DeepCopy()
+	expected.Labels = map[string]string{v1.NameLabel: "test", v1.ManagedByLabel: v1.ManagedBy}
+	expected.Spec.ImageName = "index.docker.io/library/nginx:latest"
+	if !equality.Semantic.DeepEqual(defaulted, expected) {
+		t.Errorf("expected defaulted application\n%+v\ngot\n%+v", expected, defaulted)
+	}
+}
+
+func TestValidateApplicationWithTemplate(t *testing.T) {
+	policies := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = policies.Add(&v1.ApplicationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicas", UID: "replicas", Generation: 1},
+		Spec: v1.ApplicationPolicySpec{Mode: v1.PolicyEnforce, Rules: []v1.PolicyRule{
+			{Name: "ha", Expression: "workload.spec.replicas >= 2", Message: "run at least 2 replicas"},
+		}},
+	})
+	templates := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = templates.Add(&v1.ApplicationTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: metav1.NamespaceDefault},
+		Spec:       v1.ApplicationTemplateSpec{Replicas: int32Ptr(3)},
+	})
+	s := webhook.NewServer(":0", nil)
+	s.Policies = policy.NewEvaluator(listers.NewApplicationPolicyLister(policies))
+	s.Templates = template.NewResolver(listers.NewApplicationTemplateLister(templates),
+		listers.NewClusterApplicationTemplateLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})))
+
+	// The policies see the replicas of the template.
+	app := newApplication(v1.ApplicationSpec{ImageName: "nginx:1.21", TemplateRef: &v1.TemplateReference{Name: "web"}})
+	if response := review(t, s, webhook.ValidateApplicationPath, admissionv1.Create, app); !response.Allowed {
+		t.Errorf("expected the application to be allowed, got %+v", response.Result)
+	}
+	app.Spec.Replicas = int32Ptr(1)
+	if response := review(t, s, webhook.ValidateApplicationPath, admissionv1.Create, app); response.Allowed {
+		t.Error("expected the replicas of the application to override those of the template")
+	}
+}
+
+func newWebhookConfiguration(caBundle []byte) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-controller"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name:         "applications.cloudest.artifakt.io",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: caBundle},
+		}},
+	}
+}
+
+func caBundleOf(t *testing.T, client *fake.Clientset) []byte {
+	configuration, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), "demo-controller", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return configuration.Webhooks[0].ClientConfig.CABundle
+}
+
+func TestCertificateRotation(t *testing.T) {
+	dnsNames := (&webhook.Config{ServiceName: "demo-controller-webhook", ServiceNamespace: "system"}).DNSNames()
+
+	// A CA expired an hour ago is dropped from the bundle on rotation.
+	var expiredCA []byte
+	expired := webhook.NewCertManager(dnsNames, func(_ context.Context, caBundle []byte) error {
+		expiredCA = caBundle
+		return nil
+	})
+	expired.Clock = testingclock.NewFakeClock(time.Now().Add(-3 * time.Hour))
+	expired.Validity = 2 * time.Hour
+	if _, err := expired.Rotate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	client := fake.NewSimpleClientset(newWebhookConfiguration(expiredCA))
+	certificates := webhook.NewCertManager(dnsNames, webhook.PublishCABundle(client, "demo-controller", ""))
+	clock := testingclock.NewFakeClock(time.Now())
+	certificates.Clock = clock
+	if certificates.Ready() == nil {
+		t.Error("expected the certificate manager not to be ready before the first rotation")
+	}
+
+	if _, err := certificates.Rotate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	first := caBundleOf(t, client)
+	if n := bytes.Count(first, []byte("BEGIN CERTIFICATE")); n != 1 {
+		t.Fatalf("expected the expired CA to be replaced, got %d CAs", n)
+	}
+	if err := certificates.Ready(); err != nil {
+		t.Errorf("expected the certificate manager to be ready, got %v", err)
+	}
+
+	// The webhook is served over TLS, trusted by the published CA.
+	s := httptest.NewUnstartedServer(webhook.NewServer(":0", certificates))
+	s.TLS = &tls.Config{GetCertificate: certificates.GetCertificate}
+	s.StartTLS()
+	defer s.Close()
+
+	handshake := func(caBundle []byte) error {
+		roots := x509.NewCertPool()
+		roots.AppendCertsFromPEM(caBundle)
+		conn, err := tls.Dial("tcp", s.Listener.Addr().String(), &tls.Config{RootCAs: roots, ServerName: dnsNames[0], Time: clock.Now})
+		if err == nil {
+			conn.Close()
+		}
+		return err
+	}
+	if err := handshake(first); err != nil {
+		t.Errorf("expected the served certificate to be trusted by the CA bundle, got %v", err)
+	}
+
+	clock.Step(certificates.Validity - certificates.RenewBefore)
+	if _, err := certificates.Rotate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	second := caBundleOf(t, client)
+	if !bytes.HasSuffix(second, first) || bytes.Equal(second, first) {
+		t.Errorf("expected the new CA to be prepended to the previous one, got\n%s", second)
+	}
+	if err := handshake(second); err != nil {
+		t.Errorf("expected the renewed certificate to be trusted by the CA bundle, got %v", err)
+	}
+	if handshake(first) == nil {
+		t.Error("expected the renewed certificate to be signed by a new CA")
+	}
+}
+
+// fuzzerFuncs fill the quantities and durations with values which survive a
+// round-trip through their canonical form.
+func fuzzerFuncs(codecs runtimeserializer.CodecFactory) []interface{} {
+	return []interface{}{
+		func(q *resource.Quantity, c fuzz.Continue) {
+			*q = *resource.NewMilliQuantity(c.Int63n(1000000), resource.DecimalSI)
+		},
+		func(d *metav1.Duration, c fuzz.Continue) {
+			d.Duration = time.Duration(c.Int63n(int64(time.Hour)))
+		},
+	}
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	seed := time.Now().UnixNano()
+	t.Logf("fuzzer seed %d", seed)
+	f := fuzzer.FuzzerFor(fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, fuzzerFuncs), rand.NewSource(seed), scheme.Codecs).
+		NilChance(0.3).NumElements(0, 3)
+
+	for i := 0; i < 1000; i++ {
+		var original v1.Application
+		f.Fuzz(&original)
+		original.TypeMeta = metav1.TypeMeta{}
+		var hub v2.Application
+		var roundTripped v1.Application
+		if err := scheme.Scheme.Convert(original.DeepCopy(), &hub, nil); err != nil {
+			t.Fatalf("unable to convert v1 to v2: %v", err)
+		}
+		if err := scheme.Scheme.Convert(&hub, &roundTripped, nil); err != nil {
+			t.Fatalf("unable to convert v2 to v1: %v", err)
+		}
+		if !equality.Semantic.DeepEqual(&original, &roundTripped) {
+			t.Fatalf("v1 -> v2 -> v1 is lossy:\n%s", diff.ObjectReflectDiff(&original, &roundTripped))
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		var original v2.Application
+		f.Fuzz(&original)
+		original.TypeMeta = metav1.TypeMeta{}
+		var spoke v1.Application
+		var roundTripped v2.Application
+		if err := scheme.Scheme.Convert(original.DeepCopy(), &spoke, nil); err != nil {
+			t.Fatalf("unable to convert v2 to v1: %v", err)
+		}
+		if err := scheme.Scheme.Convert(&spoke, &roundTripped, nil); err != nil {
+			t.Fatalf("unable to convert v1 to v2: %v", err)
+		}
+		if !equality.Semantic.DeepEqual(&original, &roundTripped) {
+			t.Fatalf("v2 -> v1 -> v2 is lossy:\n%s", diff.ObjectReflectDiff(&original, &roundTripped))
+		}
+	}
+}
+
+func TestConvertApplication(t *testing.T) {
+	app := &v2.Application{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v2.SchemeGroupVersion.String(), Kind: "Application"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: metav1.NamespaceDefault},
+		Spec: v2.ApplicationSpec{
+			Containers: []v2.Container{{Name: "web", Image: "nginx:1.21"}, {Name: "sidecar", Image: "envoy"}},
+			Workload:   v2.WorkloadSpec{Replicas: int32Ptr(3)},
+		},
+	}
+	raw, err := json.Marshal(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	convert := func(desiredAPIVersion string, object []byte) map[string]interface{} {
+		body, err := json.Marshal(map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "ConversionReview",
+			"request": map[string]interface{}{
+				"uid":               "42",
+				"desiredAPIVersion": desiredAPIVersion,
+				"objects":           []json.RawMessage{object},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		webhook.NewServer(":0", nil).ServeHTTP(w, httptest.NewRequest(http.MethodPost, webhook.ConvertPath, bytes.NewReader(body)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var out struct {
+			Response struct {
+				UID              string            `json:"uid"`
+				ConvertedObjects []json.RawMessage `json:"convertedObjects"`
+				Result           metav1.Status     `json:"result"`
+			} `json:"response"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.Response.UID != "42" || out.Response.Result.Status != metav1.StatusSuccess {
+			t.Fatalf("expected a successful response to the request, got %+v", out.Response)
+		}
+		if len(out.Response.ConvertedObjects) != 1 {
+			t.Fatalf("expected one converted object, got %d", len(out.Response.ConvertedObjects))
+		}
+		var converted map[string]interface{}
+		if err := json.Unmarshal(out.Response.ConvertedObjects[0], &converted); err != nil {
+			t.Fatal(err)
+		}
+		return converted
+	}
+
+	converted := convert(v1.SchemeGroupVersion.String(), raw)
+	if converted["apiVersion"] != v1.SchemeGroupVersion.String() {
+		t.Errorf("expected a v1 object, got %v", converted["apiVersion"])
+	}
+	spec := converted["spec"].(map[string]interface{})
+	if spec["imageName"] != "nginx:1.21" || spec["replicas"] != float64(3) {
+		t.Errorf("expected the first container and the workload in the v1 spec, got %v", spec)
+	}
+
+	v1Raw, err := json.Marshal(converted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back v2.Application
+	backRaw, err := json.Marshal(convert(v2.SchemeGroupVersion.String(), v1Raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(backRaw, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !equality.Semantic.DeepEqual(back.Spec, app.Spec) || len(back.Annotations) != 0 {
+		t.Errorf("expected the v2 application back, got\n%+v", back)
+	}
+}
@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// NameLabel and ManagedByLabel are the default labels of an application.
+	NameLabel      = "app.kubernetes.io/name"
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	// ManagedBy is the value of the ManagedByLabel.
+	ManagedBy = "demo-controller"
+)
+
+// sizeProfile is the replicas and resources of a size.
+type sizeProfile struct {
+	replicas int32
+	cpu      string
+	memory   string
+}
+
+var sizeProfiles = map[ApplicationSize]sizeProfile{
+	SizeSmall:  {replicas: 1, cpu: "100m", memory: "128Mi"},
+	SizeMedium: {replicas: 2, cpu: "250m", memory: "256Mi"},
+	SizeLarge:  {replicas: 3, cpu: "500m", memory: "512Mi"},
+}
+
+// DefaultingOptions toggles the optional defaults of Default.
+// +k8s:deepcopy-gen=false
+type DefaultingOptions struct {
+	// QualifyImageName rewrites imageName to its fully qualified
+	// registry/repository:tag form, such as index.docker.io/library/nginx:latest
+	// for nginx.
+	QualifyImageName bool
+}
+
+// Default sets the default labels of app and the defaults of its spec. The
+// admission webhook stores its result, the controller applies DefaultSpec to
+// the applications admitted without it. The spec of an application based on
+// a template is only defaulted by the controller, once merged with the
+// template, so that the template sets the fields the application left unset.
+func Default(app *Application, options DefaultingOptions) {
+	if app.Labels == nil {
+		app.Labels = map[string]string{}
+	}
+	if _, ok := app.Labels[NameLabel]; !ok && app.Name != "" {
+		app.Labels[NameLabel] = app.Name
+	}
+	if _, ok := app.Labels[ManagedByLabel]; !ok {
+		app.Labels[ManagedByLabel] = ManagedBy
+	}
+	if app.Spec.TemplateRef != nil {
+		qualifyImageName(&app.Spec, options)
+		return
+	}
+	DefaultSpec(&app.Spec, options)
+}
+
+// DefaultSpec sets the fields of spec left unset to their effective value.
+// The size profile sets the replicas and resources, the replicas default to
+// 1 otherwise.
+func DefaultSpec(spec *ApplicationSpec, options DefaultingOptions) {
+	if profile, ok := sizeProfiles[spec.Size]; ok {
+		if spec.Replicas == nil {
+			replicas := profile.replicas
+			spec.Replicas = &replicas
+		}
+		if spec.Resources == nil {
+			spec.Resources = &corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(profile.cpu),
+				corev1.ResourceMemory: resource.MustParse(profile.memory),
+			}}
+		}
+	}
+	if spec.Replicas == nil {
+		replicas := int32(1)
+		spec.Replicas = &replicas
+	}
+	qualifyImageName(spec, options)
+}
+
+func qualifyImageName(spec *ApplicationSpec, options DefaultingOptions) {
+	if options.QualifyImageName {
+		// Invalid image names are left as is for validation to reject them.
+		if qualified, err := QualifyImageName(spec.ImageName); err == nil {
+			spec.ImageName = qualified
+		}
+	}
+}
+
+// QualifyImageName returns image in its fully qualified form, adding the
+// default registry, repository namespace and tag.
+func QualifyImageName(image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	return ref.Name(), nil
+}
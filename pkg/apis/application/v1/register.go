@@ -33,6 +33,16 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&Application{},
 		&ApplicationList{},
+		&ApplicationRevision{},
+		&ApplicationRevisionList{},
+		&ApplicationPolicy{},
+		&ApplicationPolicyList{},
+		&ApplicationQuota{},
+		&ApplicationQuotaList{},
+		&ApplicationTemplate{},
+		&ApplicationTemplateList{},
+		&ClusterApplicationTemplate{},
+		&ClusterApplicationTemplateList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,114 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TemplateKind returns the kind of the template ref refers to, defaulting to
+// ApplicationTemplate.
+func TemplateKind(ref *TemplateReference) string {
+	if ref.Kind == "" {
+		return ApplicationTemplateKind
+	}
+	return ref.Kind
+}
+
+// ApplyTemplate sets the fields of spec left unset to those of template, so
+// that the fields set on the application override its template. The labels
+// and nodeSelector are merged key by key, the requests and limits of the
+// resources resource by resource, and the strategy of the rollout apart from
+// its promote field, the application winning. Lists are not merged.
+// ApplyTemplate is applied before DefaultSpec, the size profile only sets the
+// replicas and resources set by neither.
+func ApplyTemplate(spec *ApplicationSpec, template *ApplicationTemplateSpec) {
+	template = template.DeepCopy()
+	if spec.Replicas == nil {
+		spec.Replicas = template.Replicas
+	}
+	if spec.Size == "" {
+		spec.Size = template.Size
+	}
+	spec.Resources = mergeResources(template.Resources, spec.Resources)
+	if len(spec.ImagePullSecrets) == 0 {
+		spec.ImagePullSecrets = template.ImagePullSecrets
+	}
+	if spec.ImageVerification == nil {
+		spec.ImageVerification = template.ImageVerification
+	}
+	spec.Labels = mergeMaps(template.Labels, spec.Labels)
+	if spec.LivenessProbe == nil {
+		spec.LivenessProbe = template.LivenessProbe
+	}
+	if spec.ReadinessProbe == nil {
+		spec.ReadinessProbe = template.ReadinessProbe
+	}
+	spec.NodeSelector = mergeMaps(template.NodeSelector, spec.NodeSelector)
+	if len(spec.Tolerations) == 0 {
+		spec.Tolerations = template.Tolerations
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = template.Affinity
+	}
+	spec.Rollout = mergeRollout(template.Rollout, spec.Rollout)
+}
+
+// mergeRollout returns the strategy of overrides, or of base when overrides
+// sets none, so that promote can be set on an application whose strategy is
+// set by its template.
+func mergeRollout(base, overrides *RolloutSpec) *RolloutSpec {
+	if base == nil {
+		return overrides
+	}
+	if overrides == nil {
+		return base
+	}
+	merged := overrides.DeepCopy()
+	if merged.Canary == nil && merged.BlueGreen == nil {
+		merged.Canary = base.Canary
+		merged.BlueGreen = base.BlueGreen
+	}
+	return merged
+}
+
+// mergeMaps returns the keys of base overridden by those of overrides.
+func mergeMaps(base, overrides map[string]string) map[string]string {
+	if len(base) == 0 {
+		return overrides
+	}
+	merged := make(map[string]string, len(base)+len(overrides))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
+
+// mergeResources returns the requests and limits of base overridden by those
+// of overrides.
+func mergeResources(base, overrides *corev1.ResourceRequirements) *corev1.ResourceRequirements {
+	if base == nil {
+		return overrides
+	}
+	if overrides == nil {
+		return base
+	}
+	merged := base.DeepCopy()
+	merged.Requests = mergeResourceLists(merged.Requests, overrides.Requests)
+	merged.Limits = mergeResourceLists(merged.Limits, overrides.Limits)
+	return merged
+}
+
+func mergeResourceLists(base, overrides corev1.ResourceList) corev1.ResourceList {
+	if len(overrides) == 0 {
+		return base
+	}
+	if base == nil {
+		base = corev1.ResourceList{}
+	}
+	for name, quantity := range overrides {
+		base[name] = quantity.DeepCopy()
+	}
+	return base
+}
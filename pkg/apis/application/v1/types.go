@@ -1,34 +1,343 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=app
 
 // Application is a specification for a application resource
 type Application struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   ApplicationSpec   `json:"spec"`
+	Spec ApplicationSpec `json:"spec"`
+	// +optional
 	Status ApplicationStatus `json:"status"`
 }
 
 // ApplicationSpec is the spec for a application resource
 type ApplicationSpec struct {
+	// ImageName is the image reference of the container of the workload,
+	// such as nginx, nginx:1.21 or registry.example.com/team/app@sha256:...
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9._:/@-]*$`
 	ImageName string `json:"imageName"`
-	Replicas  *int32 `json:"replicas"`
+	// Replicas is the number of pods of the workload. It defaults to the
+	// replicas of the size profile, or 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas"`
+
+	// Size is a size profile setting the replicas and resources left unset.
+	// +kubebuilder:validation:Enum=Small;Medium;Large
+	Size ApplicationSize `json:"size,omitempty"`
+	// Resources are the compute resources of the container of the workload.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// TemplateRef is the ApplicationTemplate or ClusterApplicationTemplate
+	// the application is based on. The spec of the template is applied
+	// first, the fields set on the application override it.
+	TemplateRef *TemplateReference `json:"templateRef,omitempty"`
+	// Labels are added to the labels of the pods of the workload.
+	Labels map[string]string `json:"labels,omitempty"`
+	// LivenessProbe is the liveness probe of the container of the workload.
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+	// ReadinessProbe is the readiness probe of the container of the
+	// workload.
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+	// NodeSelector restricts the pods of the workload to the nodes with
+	// these labels.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are the tolerations of the pods of the workload.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity are the scheduling constraints of the pods of the workload.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// ImagePullSecrets are the Secrets of type kubernetes.io/dockerconfigjson
+	// holding the credentials of the registries of the images, used to
+	// resolve their digest and to pull them.
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ImageUpdatePolicy updates the tag of the imageName to the newest tag
+	// of its repository matching the policy.
+	ImageUpdatePolicy *ImageUpdatePolicy `json:"imageUpdatePolicy,omitempty"`
+	// ImageVerification requires the image to be signed with cosign by one
+	// of the keys of a Secret before it is rolled out.
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
+
+	// Suspend stops the controller from reconciling the workload until it is
+	// set back to false. The PausedAnnotation has the same effect.
+	Suspend bool `json:"suspend,omitempty"`
+	// ScaleToZeroOnSuspend scales the workload to zero replicas while the
+	// application is suspended. The previous replica count is restored on resume.
+	ScaleToZeroOnSuspend bool `json:"scaleToZeroOnSuspend,omitempty"`
+
+	// RevisionHistoryLimit is the number of ApplicationRevisions kept for
+	// rollback. Defaults to 10.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=10
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// RollbackTo restores the spec recorded in the ApplicationRevision with
+	// this revision number. It is cleared by the controller once done.
+	// +kubebuilder:validation:Minimum=1
+	RollbackTo *int64 `json:"rollbackTo,omitempty"`
+
+	// Rollout configures how a new image is rolled out. The image replaces
+	// the previous one in a single rolling update when unset.
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+}
+
+// TemplateReference refers to an ApplicationTemplate of the namespace of the
+// application or to a ClusterApplicationTemplate
+type TemplateReference struct {
+	// Kind is the kind of the template. Defaults to ApplicationTemplate.
+	// +kubebuilder:validation:Enum=ApplicationTemplate;ClusterApplicationTemplate
+	// +kubebuilder:default=ApplicationTemplate
+	Kind string `json:"kind,omitempty"`
+	// Name is the name of the template.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+const (
+	ApplicationTemplateKind        = "ApplicationTemplate"
+	ClusterApplicationTemplateKind = "ClusterApplicationTemplate"
+)
+
+// ImageUpdatePolicy selects the tags an image is updated to. Tags are
+// compared as semantic versions, those which are not are ignored.
+type ImageUpdatePolicy struct {
+	// Semver is the range of the versions the image may be updated to, such
+	// as ">=1.21.0 <1.22.0". Any version is allowed when empty.
+	Semver string `json:"semver,omitempty"`
+	// Filter is a regular expression the tags must match, such as
+	// ^1\.21\.[0-9]+-alpine$.
+	Filter string `json:"filter,omitempty"`
+	// Interval is the period between two listings of the tags of the
+	// repository. Defaults to 5m.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// ImageVerification selects the keys the image of an application must be
+// signed with.
+type ImageVerification struct {
+	// PublicKeysSecret is the Secret holding the PEM encoded public keys, one
+	// per data entry, such as the cosign.pub written by cosign
+	// generate-key-pair. A signature by any of them is accepted.
+	PublicKeysSecret corev1.LocalObjectReference `json:"publicKeysSecret"`
 }
 
-// ApplicationStatus is the status for a Foo resource
+// ApplicationSize is a size profile of an application
+type ApplicationSize string
+
+const (
+	SizeSmall  ApplicationSize = "Small"
+	SizeMedium ApplicationSize = "Medium"
+	SizeLarge  ApplicationSize = "Large"
+)
+
+// RolloutSpec is the rollout strategy of an application
+// +kubebuilder:validation:XValidation:rule="!(has(self.canary) && has(self.blueGreen))",message="canary and blueGreen are mutually exclusive"
+type RolloutSpec struct {
+	// Canary rolls out a new image progressively through a canary deployment.
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+	// BlueGreen rolls out a new image in a parallel deployment, switched
+	// over once promoted.
+	BlueGreen *BlueGreenStrategy `json:"blueGreen,omitempty"`
+	// Promote resumes a rollout waiting on a manual pause, or switches a
+	// blue/green rollout over. It is cleared by the controller once done.
+	Promote bool `json:"promote,omitempty"`
+}
+
+// BlueGreenStrategy configures a blue/green rollout
+type BlueGreenStrategy struct {
+	// Port is the port exposed by the active and preview Services and the
+	// port of the containers they target. Defaults to 80.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=80
+	Port int32 `json:"port,omitempty"`
+	// AutoPromotionDelay promotes the preview deployment once it has been
+	// ready for this long. The promotion is manual when unset.
+	AutoPromotionDelay *metav1.Duration `json:"autoPromotionDelay,omitempty"`
+	// ScaleDownDelay is the time the previous deployment is kept running
+	// after a promotion. Defaults to 30s.
+	ScaleDownDelay *metav1.Duration `json:"scaleDownDelay,omitempty"`
+}
+
+// CanaryStrategy is the list of steps of a canary rollout
+type CanaryStrategy struct {
+	// +kubebuilder:validation:MinItems=1
+	Steps []CanaryStep `json:"steps"`
+}
+
+// CanaryStep is a step of a canary rollout, setting either a weight or a pause
+// +kubebuilder:validation:XValidation:rule="has(self.setWeight) != has(self.pause)",message="exactly one of setWeight or pause must be set"
+type CanaryStep struct {
+	// SetWeight is the percentage of replicas running the new image.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	SetWeight *int32 `json:"setWeight,omitempty"`
+	// Pause holds the rollout for Duration, or until it is promoted when
+	// Duration is unset.
+	Pause *RolloutPause `json:"pause,omitempty"`
+}
+
+// RolloutPause is a pause of a rollout
+type RolloutPause struct {
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// ApplicationStatus is the status for a application resource
 type ApplicationStatus struct {
 	DeploymentRefNamespace string `json:"deploymentRefNamespace,omitempty"`
 	DeploymentRefName      string `json:"deploymentRefName,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status reports on.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// UpdatedReplicas is the number of pods running the desired pod template.
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+	// ReadyReplicas is the number of ready pods of the workload.
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+	// CurrentRevision is the number of the ApplicationRevision matching the
+	// spec that was last rolled out successfully.
+	CurrentRevision int64 `json:"currentRevision,omitempty"`
+	// LastReadyImage is the last image whose rollout reached Ready.
+	LastReadyImage string `json:"lastReadyImage,omitempty"`
+	// Image is the digest the image of the spec was last resolved to.
+	Image *ImageStatus `json:"image,omitempty"`
+	// ImageUpdate is the state of the updates of the ImageUpdatePolicy.
+	ImageUpdate *ImageUpdateStatus `json:"imageUpdate,omitempty"`
+	// Canary is the state of the last canary rollout.
+	Canary *CanaryStatus `json:"canary,omitempty"`
+	// BlueGreen is the state of the blue/green deployments.
+	BlueGreen *BlueGreenStatus `json:"blueGreen,omitempty"`
+
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ImageStatus is the resolution of an image tag to the digest it points to
+type ImageStatus struct {
+	// Tag is the tag of the image, such as 1.21. It is empty when the image
+	// is pinned to a digest.
+	Tag string `json:"tag,omitempty"`
+	// Digest is the digest of the manifest the workload runs, such as
+	// sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac.
+	Digest string `json:"digest"`
+}
+
+// ImageUpdateStatus is the state of the automatic updates of an image
+type ImageUpdateStatus struct {
+	// LastCheckTime is the time the tags of the repository were last listed.
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// LastUpdateTime is the time of the last update.
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// From is the image the last update replaced.
+	From string `json:"from,omitempty"`
+	// To is the image the last update set.
+	To string `json:"to,omitempty"`
+}
+
+// CanaryPhase is the phase of a canary rollout
+type CanaryPhase string
+
+const (
+	CanaryProgressing CanaryPhase = "Progressing"
+	CanaryPaused      CanaryPhase = "Paused"
+	CanaryPromoted    CanaryPhase = "Promoted"
+	CanaryAborted     CanaryPhase = "Aborted"
+)
+
+// CanaryStatus is the state of a canary rollout
+type CanaryStatus struct {
+	Phase       CanaryPhase `json:"phase"`
+	StableImage string      `json:"stableImage"`
+	CanaryImage string      `json:"canaryImage"`
+	// CurrentStepIndex is the index of the step being run.
+	CurrentStepIndex int32 `json:"currentStepIndex"`
+	// CurrentStepStartTime is the time the current step started.
+	CurrentStepStartTime *metav1.Time `json:"currentStepStartTime,omitempty"`
+	// Weight is the percentage of replicas running the canary image.
+	Weight         int32 `json:"weight"`
+	StableReplicas int32 `json:"stableReplicas"`
+	CanaryReplicas int32 `json:"canaryReplicas"`
+}
+
+// BlueGreenPhase is the phase of a blue/green rollout
+type BlueGreenPhase string
+
+const (
+	// BlueGreenActive is the steady phase: only the active color runs.
+	BlueGreenActive BlueGreenPhase = "Active"
+	// BlueGreenPreviewing runs the new image in the preview color until it is promoted.
+	BlueGreenPreviewing BlueGreenPhase = "Previewing"
+	// BlueGreenScalingDown keeps the previous color running until the scale down delay expires.
+	BlueGreenScalingDown BlueGreenPhase = "ScalingDown"
+)
+
+// BlueGreenStatus is the state of a blue/green rollout
+type BlueGreenStatus struct {
+	Phase BlueGreenPhase `json:"phase"`
+	// ActiveColor is the color the active Service targets.
+	ActiveColor string `json:"activeColor"`
+	// PreviewColor is the color the preview Service targets while previewing.
+	PreviewColor string `json:"previewColor,omitempty"`
+	// PreviewReadyTime is the time the preview color became ready.
+	PreviewReadyTime *metav1.Time `json:"previewReadyTime,omitempty"`
+	// ScaleDownTime is the time the previous color gets scaled down.
+	ScaleDownTime *metav1.Time `json:"scaleDownTime,omitempty"`
+
+	Blue  *ColorStatus `json:"blue,omitempty"`
+	Green *ColorStatus `json:"green,omitempty"`
+}
+
+// ColorStatus is the state of the deployment of a blue/green color
+type ColorStatus struct {
+	Image         string `json:"image"`
+	Replicas      int32  `json:"replicas"`
+	ReadyReplicas int32  `json:"readyReplicas"`
 }
 
+const (
+	// ApplicationNameLabel is set on objects owned by an application to its name.
+	ApplicationNameLabel = "cloudest.artifakt.io/application"
+	// PausedAnnotation suspends the reconciliation of an application when set to "true".
+	PausedAnnotation = "demo-controller/paused"
+
+	// ApplicationSuspended is true while the controller does not reconcile the application.
+	ApplicationSuspended = "Suspended"
+	// ApplicationProgressing is true while the workload rolls out a new spec.
+	ApplicationProgressing = "Progressing"
+	// ApplicationReady is true once every replica of the workload runs the desired spec.
+	ApplicationReady = "Ready"
+	// ApplicationFailed is true when the rollout exceeded its progress deadline.
+	ApplicationFailed = "Failed"
+	// ApplicationRolledBack is true when the workload was reverted to the last
+	// image which reached Ready after the rollout of the spec image failed.
+	ApplicationRolledBack = "RolledBack"
+	// ApplicationPolicyViolation is true when the application breaks a rule
+	// of an ApplicationPolicy.
+	ApplicationPolicyViolation = "PolicyViolation"
+	// ApplicationSignatureVerificationFailed is true when the image is not
+	// signed by the keys of the ImageVerification.
+	ApplicationSignatureVerificationFailed = "SignatureVerificationFailed"
+	// ApplicationImageResolutionFailed is true when the tag of the image
+	// could not be resolved to a digest.
+	ApplicationImageResolutionFailed = "ImageResolutionFailed"
+	// ApplicationQuotaExceeded is true when the workload is not scaled up
+	// as it would exceed an ApplicationQuota of the namespace.
+	ApplicationQuotaExceeded = "QuotaExceeded"
+	// ApplicationTemplateNotFound is true when the template the application
+	// refers to does not exist.
+	ApplicationTemplateNotFound = "TemplateNotFound"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
 
 // ApplicationList is a list of Application resources
 type ApplicationList struct {
@@ -37,3 +346,259 @@ type ApplicationList struct {
 
 	Items []Application `json:"items"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=apprev
+// +kubebuilder:printcolumn:name=Revision,type=integer,JSONPath=`.spec.revision`
+// +kubebuilder:printcolumn:name=Image,type=string,JSONPath=`.spec.template.imageName`
+
+// ApplicationRevision is an immutable snapshot of the spec of an Application,
+// recorded each time one of its rollouts succeeds
+type ApplicationRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+	Spec ApplicationRevisionSpec `json:"spec"`
+}
+
+// ApplicationRevisionSpec is the spec for a application revision resource
+type ApplicationRevisionSpec struct {
+	// Revision is the sequence number of the revision within its application.
+	// +kubebuilder:validation:Minimum=1
+	Revision int64 `json:"revision"`
+	// Template is the recorded application spec.
+	Template ApplicationSpec `json:"template"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// ApplicationRevisionList is a list of ApplicationRevision resources
+type ApplicationRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ApplicationRevision `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=apppol
+// +kubebuilder:printcolumn:name=Mode,type=string,JSONPath=`.spec.mode`
+
+// ApplicationPolicy is a set of rules the Applications of the cluster must
+// follow, written as CEL expressions
+type ApplicationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApplicationPolicySpec `json:"spec"`
+}
+
+// ApplicationPolicySpec is the spec for a application policy resource
+type ApplicationPolicySpec struct {
+	// Mode is what happens to the applications breaking a rule: Enforce
+	// rejects them at admission and blocks their rollout, Warn only reports
+	// the violations. Defaults to Enforce.
+	// +kubebuilder:validation:Enum=Warn;Enforce
+	// +kubebuilder:default=Enforce
+	Mode PolicyMode `json:"mode,omitempty"`
+	// Rules are the rules of the policy.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyMode is what happens to the applications breaking a policy
+type PolicyMode string
+
+const (
+	PolicyWarn    PolicyMode = "Warn"
+	PolicyEnforce PolicyMode = "Enforce"
+)
+
+// PolicyRule is a rule of an application policy
+type PolicyRule struct {
+	// Name identifies the rule in the violations.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Expression is a CEL expression evaluating to true when the application
+	// follows the rule. It reads the Application as object and the
+	// Deployment rendered for it as workload, such as
+	// object.spec.imageName.startsWith("registry.example.com/").
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+	// Message describes the violations of the rule. Defaults to the
+	// expression.
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// ApplicationPolicyList is a list of ApplicationPolicy resources
+type ApplicationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ApplicationPolicy `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=appquota
+// +kubebuilder:printcolumn:name=Applications,type=integer,JSONPath=`.status.used.applications`
+// +kubebuilder:printcolumn:name=Replicas,type=integer,JSONPath=`.status.used.replicas`
+// +kubebuilder:printcolumn:name=CPU,type=string,JSONPath=`.status.used.cpu`
+// +kubebuilder:printcolumn:name=Memory,type=string,JSONPath=`.status.used.memory`
+
+// ApplicationQuota caps the Applications of its namespace
+type ApplicationQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationQuotaSpec   `json:"spec"`
+	Status ApplicationQuotaStatus `json:"status,omitempty"`
+}
+
+// ApplicationQuotaSpec is the spec for a application quota resource. Unset
+// limits are not enforced.
+type ApplicationQuotaSpec struct {
+	// Applications is the maximum number of Applications.
+	// +kubebuilder:validation:Minimum=0
+	Applications *int32 `json:"applications,omitempty"`
+	// Replicas is the maximum total of the replicas of the Applications.
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+	// CPU is the maximum total of the CPU requested by the replicas of the
+	// Applications.
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+	// Memory is the maximum total of the memory requested by the replicas
+	// of the Applications.
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
+// ApplicationQuotaStatus is the status for a application quota resource
+type ApplicationQuotaStatus struct {
+	// Used is the usage of the Applications of the namespace.
+	Used ApplicationQuotaUsage `json:"used,omitempty"`
+}
+
+// ApplicationQuotaUsage is the usage of the Applications of a namespace,
+// counted from their spec. The requests of a container default to its
+// limits.
+type ApplicationQuotaUsage struct {
+	Applications int32             `json:"applications"`
+	Replicas     int32             `json:"replicas"`
+	CPU          resource.Quantity `json:"cpu"`
+	Memory       resource.Quantity `json:"memory"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// ApplicationQuotaList is a list of ApplicationQuota resources
+type ApplicationQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ApplicationQuota `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=apptpl
+
+// ApplicationTemplate holds the settings shared by the Applications of its
+// namespace referring to it
+type ApplicationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApplicationTemplateSpec `json:"spec"`
+}
+
+// ApplicationTemplateSpec holds the fields of an ApplicationSpec shared by
+// several applications. Each field set on an application overrides the one
+// of its template, except for the maps labels and nodeSelector, merged key
+// by key, the requests and limits of resources, merged resource by resource,
+// and the rollout, whose strategy and promote field are merged apart.
+type ApplicationTemplateSpec struct {
+	// Replicas is the number of pods of the workload.
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Size is a size profile setting the replicas and resources left unset.
+	// +kubebuilder:validation:Enum=Small;Medium;Large
+	Size ApplicationSize `json:"size,omitempty"`
+	// Resources are the compute resources of the container of the workload.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// ImagePullSecrets are the Secrets of type kubernetes.io/dockerconfigjson
+	// holding the credentials of the registries of the images.
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ImageVerification requires the image to be signed with cosign by one
+	// of the keys of a Secret before it is rolled out.
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
+	// Labels are added to the labels of the pods of the workload.
+	Labels map[string]string `json:"labels,omitempty"`
+	// LivenessProbe is the liveness probe of the container of the workload.
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+	// ReadinessProbe is the readiness probe of the container of the
+	// workload.
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+	// NodeSelector restricts the pods of the workload to the nodes with
+	// these labels.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are the tolerations of the pods of the workload.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity are the scheduling constraints of the pods of the workload.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Rollout configures how a new image is rolled out.
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// ApplicationTemplateList is a list of ApplicationTemplate resources
+type ApplicationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ApplicationTemplate `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=clusterapptpl
+
+// ClusterApplicationTemplate holds the settings shared by the Applications of
+// every namespace referring to it
+type ClusterApplicationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApplicationTemplateSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// ClusterApplicationTemplateList is a list of ClusterApplicationTemplate
+// resources
+type ClusterApplicationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterApplicationTemplate `json:"items"`
+}
@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"github.com/blang/semver/v4"
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"regexp"
+	"strings"
+)
+
+// ValidateApplication returns the errors of the spec of app.
+func ValidateApplication(app *Application) field.ErrorList {
+	return ValidateApplicationSpec(&app.Spec, field.NewPath("spec"))
+}
+
+// ValidateApplicationSpec returns the errors of spec, rooted at fldPath.
+func ValidateApplicationSpec(spec *ApplicationSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, ValidateImageName(spec.ImageName, fldPath.Child("imageName"))...)
+	if spec.Replicas != nil && *spec.Replicas < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("replicas"), *spec.Replicas, "must not be negative"))
+	}
+	if spec.Size != "" {
+		if _, ok := sizeProfiles[spec.Size]; !ok {
+			errs = append(errs, field.NotSupported(fldPath.Child("size"), spec.Size, []string{string(SizeSmall), string(SizeMedium), string(SizeLarge)}))
+		}
+	}
+	if spec.RevisionHistoryLimit != nil && *spec.RevisionHistoryLimit < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("revisionHistoryLimit"), *spec.RevisionHistoryLimit, "must not be negative"))
+	}
+	if spec.RollbackTo != nil && *spec.RollbackTo < 1 {
+		errs = append(errs, field.Invalid(fldPath.Child("rollbackTo"), *spec.RollbackTo, "must be at least 1"))
+	}
+	if spec.Rollout != nil {
+		errs = append(errs, validateRollout(spec.Rollout, fldPath.Child("rollout"))...)
+	}
+	if spec.ImageUpdatePolicy != nil {
+		errs = append(errs, validateImageUpdatePolicy(spec.ImageUpdatePolicy, fldPath.Child("imageUpdatePolicy"))...)
+		if strings.Contains(spec.ImageName, "@") {
+			errs = append(errs, field.Forbidden(fldPath.Child("imageUpdatePolicy"), "may not be set when imageName is pinned to a digest"))
+		}
+	}
+	if spec.ImageVerification != nil && spec.ImageVerification.PublicKeysSecret.Name == "" {
+		errs = append(errs, field.Required(fldPath.Child("imageVerification", "publicKeysSecret", "name"), ""))
+	}
+	if spec.TemplateRef != nil {
+		errs = append(errs, validateTemplateRef(spec.TemplateRef, fldPath.Child("templateRef"))...)
+	}
+	return errs
+}
+
+func validateTemplateRef(ref *TemplateReference, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if ref.Name == "" {
+		errs = append(errs, field.Required(fldPath.Child("name"), ""))
+	}
+	if kind := TemplateKind(ref); kind != ApplicationTemplateKind && kind != ClusterApplicationTemplateKind {
+		errs = append(errs, field.NotSupported(fldPath.Child("kind"), ref.Kind, []string{ApplicationTemplateKind, ClusterApplicationTemplateKind}))
+	}
+	return errs
+}
+
+func validateImageUpdatePolicy(policy *ImageUpdatePolicy, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if policy.Semver != "" {
+		if _, err := semver.ParseRange(policy.Semver); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("semver"), policy.Semver, err.Error()))
+		}
+	}
+	if policy.Filter != "" {
+		if _, err := regexp.Compile(policy.Filter); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("filter"), policy.Filter, err.Error()))
+		}
+	}
+	if policy.Interval != nil && policy.Interval.Duration <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("interval"), policy.Interval.Duration.String(), "must be positive"))
+	}
+	return errs
+}
+
+// ValidateImageName returns an error unless image is a valid image
+// reference, such as nginx, nginx:1.21 or registry.example.com/team/app@sha256:...
+func ValidateImageName(image string, fldPath *field.Path) field.ErrorList {
+	if image == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	if _, err := name.ParseReference(image); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, image, err.Error())}
+	}
+	return nil
+}
+
+func validateRollout(rollout *RolloutSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if rollout.Canary != nil && rollout.BlueGreen != nil {
+		errs = append(errs, field.Forbidden(fldPath.Child("blueGreen"), "may not be set together with canary"))
+	}
+
+	if canary := rollout.Canary; canary != nil {
+		stepsPath := fldPath.Child("canary", "steps")
+		if len(canary.Steps) == 0 {
+			errs = append(errs, field.Required(stepsPath, "a canary rollout needs at least one step"))
+		}
+		for i, step := range canary.Steps {
+			stepPath := stepsPath.Index(i)
+			switch {
+			case step.SetWeight == nil && step.Pause == nil:
+				errs = append(errs, field.Required(stepPath, "one of setWeight or pause must be set"))
+			case step.SetWeight != nil && step.Pause != nil:
+				errs = append(errs, field.Forbidden(stepPath.Child("pause"), "may not be set together with setWeight"))
+			}
+			if step.SetWeight != nil && (*step.SetWeight < 0 || *step.SetWeight > 100) {
+				errs = append(errs, field.Invalid(stepPath.Child("setWeight"), *step.SetWeight, "must be between 0 and 100"))
+			}
+			if step.Pause != nil && step.Pause.Duration != nil && step.Pause.Duration.Duration < 0 {
+				errs = append(errs, field.Invalid(stepPath.Child("pause", "duration"), step.Pause.Duration.Duration.String(), "must not be negative"))
+			}
+		}
+	}
+
+	if blueGreen := rollout.BlueGreen; blueGreen != nil {
+		blueGreenPath := fldPath.Child("blueGreen")
+		if blueGreen.Port < 0 || blueGreen.Port > 65535 {
+			errs = append(errs, field.Invalid(blueGreenPath.Child("port"), blueGreen.Port, "must be between 1 and 65535"))
+		}
+		if blueGreen.AutoPromotionDelay != nil && blueGreen.AutoPromotionDelay.Duration < 0 {
+			errs = append(errs, field.Invalid(blueGreenPath.Child("autoPromotionDelay"), blueGreen.AutoPromotionDelay.Duration.String(), "must not be negative"))
+		}
+		if blueGreen.ScaleDownDelay != nil && blueGreen.ScaleDownDelay.Duration < 0 {
+			errs = append(errs, field.Invalid(blueGreenPath.Child("scaleDownDelay"), blueGreen.ScaleDownDelay.Duration.String(), "must not be negative"))
+		}
+	}
+	return errs
+}
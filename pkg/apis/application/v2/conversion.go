@@ -0,0 +1,105 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"k8s.io/apimachinery/pkg/conversion"
+)
+
+const (
+	// MainContainerName is the name of the container the imageName of a v1
+	// Application converts to.
+	MainContainerName = "main"
+	// ContainersAnnotation records on a v1 Application the containers of its
+	// v2 spec that v1 cannot represent, so that the conversion round-trips.
+	ContainersAnnotation = "cloudest.artifakt.io/v2-containers"
+)
+
+// Convert_v2_Application_To_v1_Application converts a v2 Application to v1,
+// recording its containers in the ContainersAnnotation unless they are a
+// single main container.
+func Convert_v2_Application_To_v1_Application(in *Application, out *v1.Application, s conversion.Scope) error {
+	if err := autoConvert_v2_Application_To_v1_Application(in, out, s); err != nil {
+		return err
+	}
+	if len(in.Spec.Containers) == 1 && in.Spec.Containers[0].Name == MainContainerName {
+		return nil
+	}
+
+	containers, err := json.Marshal(in.Spec.Containers)
+	if err != nil {
+		return err
+	}
+	annotations := make(map[string]string, len(in.Annotations)+1)
+	for key, value := range in.Annotations {
+		annotations[key] = value
+	}
+	annotations[ContainersAnnotation] = string(containers)
+	out.Annotations = annotations
+	return nil
+}
+
+// Convert_v1_Application_To_v2_Application converts a v1 Application to v2,
+// restoring the containers recorded in the ContainersAnnotation. The image
+// and resources of the v1 spec override those of the first container.
+func Convert_v1_Application_To_v2_Application(in *v1.Application, out *Application, s conversion.Scope) error {
+	if err := autoConvert_v1_Application_To_v2_Application(in, out, s); err != nil {
+		return err
+	}
+	recorded, ok := in.Annotations[ContainersAnnotation]
+	if !ok {
+		return nil
+	}
+
+	var containers []Container
+	if err := json.Unmarshal([]byte(recorded), &containers); err != nil {
+		return fmt.Errorf("invalid %s annotation: %w", ContainersAnnotation, err)
+	}
+	if len(containers) > 0 {
+		containers[0].Image = in.Spec.ImageName
+		containers[0].Resources = in.Spec.Resources
+	} else if in.Spec.ImageName != "" || in.Spec.Resources != nil {
+		containers = []Container{{Name: MainContainerName, Image: in.Spec.ImageName, Resources: in.Spec.Resources}}
+	}
+	out.Spec.Containers = containers
+
+	var annotations map[string]string
+	for key, value := range in.Annotations {
+		if key == ContainersAnnotation {
+			continue
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key] = value
+	}
+	out.Annotations = annotations
+	return nil
+}
+
+// Convert_v2_ApplicationSpec_To_v1_ApplicationSpec converts the first
+// container and the workload of a v2 spec to the v1 fields.
+func Convert_v2_ApplicationSpec_To_v1_ApplicationSpec(in *ApplicationSpec, out *v1.ApplicationSpec, s conversion.Scope) error {
+	if err := autoConvert_v2_ApplicationSpec_To_v1_ApplicationSpec(in, out, s); err != nil {
+		return err
+	}
+	out.Replicas = in.Workload.Replicas
+	out.Size = v1.ApplicationSize(in.Workload.Size)
+	if len(in.Containers) > 0 {
+		out.ImageName = in.Containers[0].Image
+		out.Resources = in.Containers[0].Resources
+	}
+	return nil
+}
+
+// Convert_v1_ApplicationSpec_To_v2_ApplicationSpec converts the image of a
+// v1 spec to a main container.
+func Convert_v1_ApplicationSpec_To_v2_ApplicationSpec(in *v1.ApplicationSpec, out *ApplicationSpec, s conversion.Scope) error {
+	if err := autoConvert_v1_ApplicationSpec_To_v2_ApplicationSpec(in, out, s); err != nil {
+		return err
+	}
+	out.Workload = WorkloadSpec{Replicas: in.Replicas, Size: ApplicationSize(in.Size)}
+	out.Containers = []Container{{Name: MainContainerName, Image: in.ImageName, Resources: in.Resources}}
+	return nil
+}
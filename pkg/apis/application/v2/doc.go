@@ -0,0 +1,11 @@
+// +k8s:deepcopy-gen=package
+// +k8s:conversion-gen=github.com/artifakt-io/demo-controller/pkg/apis/application/v1
+// +groupName=cloudest.artifakt.io
+
+// Package v2 is the v2 version of the API. It describes the workload of an
+// application as a list of containers instead of a single image.
+package v2
+
+const (
+	Version = "v2"
+)
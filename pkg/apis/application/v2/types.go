@@ -0,0 +1,312 @@
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=app
+// +kubebuilder:storageversion
+
+// Application is a specification for a application resource
+type Application struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApplicationSpec `json:"spec"`
+	// +optional
+	Status ApplicationStatus `json:"status"`
+}
+
+// ApplicationSpec is the spec for a application resource
+type ApplicationSpec struct {
+	// Containers are the containers of the pods of the workload. Only a
+	// single container is supported until the controller deploys the
+	// others.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=1
+	// +listType=map
+	// +listMapKey=name
+	Containers []Container `json:"containers"`
+	// Workload sizes the workload.
+	// +optional
+	Workload WorkloadSpec `json:"workload"`
+	// TemplateRef is the ApplicationTemplate or ClusterApplicationTemplate
+	// the application is based on. The spec of the template is applied
+	// first, the fields set on the application override it.
+	TemplateRef *TemplateReference `json:"templateRef,omitempty"`
+	// Labels are added to the labels of the pods of the workload.
+	Labels map[string]string `json:"labels,omitempty"`
+	// LivenessProbe is the liveness probe of the first container.
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+	// ReadinessProbe is the readiness probe of the first container.
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+	// NodeSelector restricts the pods of the workload to the nodes with
+	// these labels.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are the tolerations of the pods of the workload.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity are the scheduling constraints of the pods of the workload.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// ImagePullSecrets are the Secrets of type kubernetes.io/dockerconfigjson
+	// holding the credentials of the registries of the images, used to
+	// resolve their digest and to pull them.
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ImageUpdatePolicy updates the tag of the image of the first container to the newest tag
+	// of its repository matching the policy.
+	ImageUpdatePolicy *ImageUpdatePolicy `json:"imageUpdatePolicy,omitempty"`
+	// ImageVerification requires the image of the first container to be signed with cosign by one
+	// of the keys of a Secret before it is rolled out.
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
+
+	// Suspend stops the controller from reconciling the workload until it is
+	// set back to false. The PausedAnnotation has the same effect.
+	Suspend bool `json:"suspend,omitempty"`
+	// ScaleToZeroOnSuspend scales the workload to zero replicas while the
+	// application is suspended. The previous replica count is restored on resume.
+	ScaleToZeroOnSuspend bool `json:"scaleToZeroOnSuspend,omitempty"`
+
+	// RevisionHistoryLimit is the number of ApplicationRevisions kept for
+	// rollback. Defaults to 10.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=10
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// RollbackTo restores the spec recorded in the ApplicationRevision with
+	// this revision number. It is cleared by the controller once done.
+	// +kubebuilder:validation:Minimum=1
+	RollbackTo *int64 `json:"rollbackTo,omitempty"`
+
+	// Rollout configures how a new image is rolled out. The image replaces
+	// the previous one in a single rolling update when unset.
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+}
+
+// Container is a container of the workload
+type Container struct {
+	// Name is the name of the container, unique within the workload.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+	// Image is the image reference of the container, such as nginx,
+	// nginx:1.21 or registry.example.com/team/app@sha256:...
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9._:/@-]*$`
+	Image string `json:"image"`
+	// Resources are the compute resources of the container.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// WorkloadSpec sizes the workload of an application
+type WorkloadSpec struct {
+	// Replicas is the number of pods of the workload. It defaults to the
+	// replicas of the size profile, or 1.
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Size is a size profile setting the replicas and resources left unset.
+	// +kubebuilder:validation:Enum=Small;Medium;Large
+	Size ApplicationSize `json:"size,omitempty"`
+}
+
+// TemplateReference refers to an ApplicationTemplate of the namespace of the
+// application or to a ClusterApplicationTemplate
+type TemplateReference struct {
+	// Kind is the kind of the template. Defaults to ApplicationTemplate.
+	// +kubebuilder:validation:Enum=ApplicationTemplate;ClusterApplicationTemplate
+	// +kubebuilder:default=ApplicationTemplate
+	Kind string `json:"kind,omitempty"`
+	// Name is the name of the template.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ImageUpdatePolicy selects the tags an image is updated to. Tags are
+// compared as semantic versions, those which are not are ignored.
+type ImageUpdatePolicy struct {
+	// Semver is the range of the versions the image may be updated to, such
+	// as ">=1.21.0 <1.22.0". Any version is allowed when empty.
+	Semver string `json:"semver,omitempty"`
+	// Filter is a regular expression the tags must match, such as
+	// ^1\.21\.[0-9]+-alpine$.
+	Filter string `json:"filter,omitempty"`
+	// Interval is the period between two listings of the tags of the
+	// repository. Defaults to 5m.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// ImageVerification selects the keys the image of an application must be
+// signed with.
+type ImageVerification struct {
+	// PublicKeysSecret is the Secret holding the PEM encoded public keys, one
+	// per data entry, such as the cosign.pub written by cosign
+	// generate-key-pair. A signature by any of them is accepted.
+	PublicKeysSecret corev1.LocalObjectReference `json:"publicKeysSecret"`
+}
+
+// ApplicationSize is a size profile of an application
+type ApplicationSize string
+
+const (
+	SizeSmall  ApplicationSize = "Small"
+	SizeMedium ApplicationSize = "Medium"
+	SizeLarge  ApplicationSize = "Large"
+)
+
+// RolloutSpec is the rollout strategy of an application
+// +kubebuilder:validation:XValidation:rule="!(has(self.canary) && has(self.blueGreen))",message="canary and blueGreen are mutually exclusive"
+type RolloutSpec struct {
+	// Canary rolls out a new image progressively through a canary deployment.
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+	// BlueGreen rolls out a new image in a parallel deployment, switched
+	// over once promoted.
+	BlueGreen *BlueGreenStrategy `json:"blueGreen,omitempty"`
+	// Promote resumes a rollout waiting on a manual pause, or switches a
+	// blue/green rollout over. It is cleared by the controller once done.
+	Promote bool `json:"promote,omitempty"`
+}
+
+// BlueGreenStrategy configures a blue/green rollout
+type BlueGreenStrategy struct {
+	// Port is the port exposed by the active and preview Services and the
+	// port of the containers they target. Defaults to 80.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=80
+	Port int32 `json:"port,omitempty"`
+	// AutoPromotionDelay promotes the preview deployment once it has been
+	// ready for this long. The promotion is manual when unset.
+	AutoPromotionDelay *metav1.Duration `json:"autoPromotionDelay,omitempty"`
+	// ScaleDownDelay is the time the previous deployment is kept running
+	// after a promotion. Defaults to 30s.
+	ScaleDownDelay *metav1.Duration `json:"scaleDownDelay,omitempty"`
+}
+
+// CanaryStrategy is the list of steps of a canary rollout
+type CanaryStrategy struct {
+	// +kubebuilder:validation:MinItems=1
+	Steps []CanaryStep `json:"steps"`
+}
+
+// CanaryStep is a step of a canary rollout, setting either a weight or a pause
+// +kubebuilder:validation:XValidation:rule="has(self.setWeight) != has(self.pause)",message="exactly one of setWeight or pause must be set"
+type CanaryStep struct {
+	// SetWeight is the percentage of replicas running the new image.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	SetWeight *int32 `json:"setWeight,omitempty"`
+	// Pause holds the rollout for Duration, or until it is promoted when
+	// Duration is unset.
+	Pause *RolloutPause `json:"pause,omitempty"`
+}
+
+// RolloutPause is a pause of a rollout
+type RolloutPause struct {
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// ApplicationStatus is the status for a application resource
+type ApplicationStatus struct {
+	DeploymentRefNamespace string `json:"deploymentRefNamespace,omitempty"`
+	DeploymentRefName      string `json:"deploymentRefName,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status reports on.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// UpdatedReplicas is the number of pods running the desired pod template.
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+	// ReadyReplicas is the number of ready pods of the workload.
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+	// CurrentRevision is the number of the ApplicationRevision matching the
+	// spec that was last rolled out successfully.
+	CurrentRevision int64 `json:"currentRevision,omitempty"`
+	// LastReadyImage is the last image whose rollout reached Ready.
+	LastReadyImage string `json:"lastReadyImage,omitempty"`
+	// Image is the digest the image of the spec was last resolved to.
+	Image *ImageStatus `json:"image,omitempty"`
+	// ImageUpdate is the state of the updates of the ImageUpdatePolicy.
+	ImageUpdate *ImageUpdateStatus `json:"imageUpdate,omitempty"`
+	// Canary is the state of the last canary rollout.
+	Canary *CanaryStatus `json:"canary,omitempty"`
+	// BlueGreen is the state of the blue/green deployments.
+	BlueGreen *BlueGreenStatus `json:"blueGreen,omitempty"`
+
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ImageStatus is the resolution of an image tag to the digest it points to
+type ImageStatus struct {
+	// Tag is the tag of the image, such as 1.21. It is empty when the image
+	// is pinned to a digest.
+	Tag string `json:"tag,omitempty"`
+	// Digest is the digest of the manifest the workload runs, such as
+	// sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac.
+	Digest string `json:"digest"`
+}
+
+// ImageUpdateStatus is the state of the automatic updates of an image
+type ImageUpdateStatus struct {
+	// LastCheckTime is the time the tags of the repository were last listed.
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// LastUpdateTime is the time of the last update.
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// From is the image the last update replaced.
+	From string `json:"from,omitempty"`
+	// To is the image the last update set.
+	To string `json:"to,omitempty"`
+}
+
+// CanaryPhase is the phase of a canary rollout
+type CanaryPhase string
+
+// CanaryStatus is the state of a canary rollout
+type CanaryStatus struct {
+	Phase       CanaryPhase `json:"phase"`
+	StableImage string      `json:"stableImage"`
+	CanaryImage string      `json:"canaryImage"`
+	// CurrentStepIndex is the index of the step being run.
+	CurrentStepIndex int32 `json:"currentStepIndex"`
+	// CurrentStepStartTime is the time the current step started.
+	CurrentStepStartTime *metav1.Time `json:"currentStepStartTime,omitempty"`
+	// Weight is the percentage of replicas running the canary image.
+	Weight         int32 `json:"weight"`
+	StableReplicas int32 `json:"stableReplicas"`
+	CanaryReplicas int32 `json:"canaryReplicas"`
+}
+
+// BlueGreenPhase is the phase of a blue/green rollout
+type BlueGreenPhase string
+
+// BlueGreenStatus is the state of a blue/green rollout
+type BlueGreenStatus struct {
+	Phase BlueGreenPhase `json:"phase"`
+	// ActiveColor is the color the active Service targets.
+	ActiveColor string `json:"activeColor"`
+	// PreviewColor is the color the preview Service targets while previewing.
+	PreviewColor string `json:"previewColor,omitempty"`
+	// PreviewReadyTime is the time the preview color became ready.
+	PreviewReadyTime *metav1.Time `json:"previewReadyTime,omitempty"`
+	// ScaleDownTime is the time the previous color gets scaled down.
+	ScaleDownTime *metav1.Time `json:"scaleDownTime,omitempty"`
+
+	Blue  *ColorStatus `json:"blue,omitempty"`
+	Green *ColorStatus `json:"green,omitempty"`
+}
+
+// ColorStatus is the state of the deployment of a blue/green color
+type ColorStatus struct {
+	Image         string `json:"image"`
+	Replicas      int32  `json:"replicas"`
+	ReadyReplicas int32  `json:"readyReplicas"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// ApplicationList is a list of Application resources
+type ApplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Application `json:"items"`
+}
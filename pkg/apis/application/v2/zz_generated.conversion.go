@@ -0,0 +1,663 @@
+// +build !ignore_autogenerated
+
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	unsafe "unsafe"
+
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*ApplicationList)(nil), (*v1.ApplicationList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ApplicationList_To_v1_ApplicationList(a.(*ApplicationList), b.(*v1.ApplicationList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ApplicationList)(nil), (*ApplicationList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ApplicationList_To_v2_ApplicationList(a.(*v1.ApplicationList), b.(*ApplicationList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ApplicationStatus)(nil), (*v1.ApplicationStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ApplicationStatus_To_v1_ApplicationStatus(a.(*ApplicationStatus), b.(*v1.ApplicationStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ApplicationStatus)(nil), (*ApplicationStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ApplicationStatus_To_v2_ApplicationStatus(a.(*v1.ApplicationStatus), b.(*ApplicationStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BlueGreenStatus)(nil), (*v1.BlueGreenStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_BlueGreenStatus_To_v1_BlueGreenStatus(a.(*BlueGreenStatus), b.(*v1.BlueGreenStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.BlueGreenStatus)(nil), (*BlueGreenStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BlueGreenStatus_To_v2_BlueGreenStatus(a.(*v1.BlueGreenStatus), b.(*BlueGreenStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BlueGreenStrategy)(nil), (*v1.BlueGreenStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_BlueGreenStrategy_To_v1_BlueGreenStrategy(a.(*BlueGreenStrategy), b.(*v1.BlueGreenStrategy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.BlueGreenStrategy)(nil), (*BlueGreenStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BlueGreenStrategy_To_v2_BlueGreenStrategy(a.(*v1.BlueGreenStrategy), b.(*BlueGreenStrategy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CanaryStatus)(nil), (*v1.CanaryStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_CanaryStatus_To_v1_CanaryStatus(a.(*CanaryStatus), b.(*v1.CanaryStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.CanaryStatus)(nil), (*CanaryStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CanaryStatus_To_v2_CanaryStatus(a.(*v1.CanaryStatus), b.(*CanaryStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CanaryStep)(nil), (*v1.CanaryStep)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_CanaryStep_To_v1_CanaryStep(a.(*CanaryStep), b.(*v1.CanaryStep), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.CanaryStep)(nil), (*CanaryStep)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CanaryStep_To_v2_CanaryStep(a.(*v1.CanaryStep), b.(*CanaryStep), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CanaryStrategy)(nil), (*v1.CanaryStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_CanaryStrategy_To_v1_CanaryStrategy(a.(*CanaryStrategy), b.(*v1.CanaryStrategy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.CanaryStrategy)(nil), (*CanaryStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CanaryStrategy_To_v2_CanaryStrategy(a.(*v1.CanaryStrategy), b.(*CanaryStrategy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ColorStatus)(nil), (*v1.ColorStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ColorStatus_To_v1_ColorStatus(a.(*ColorStatus), b.(*v1.ColorStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ColorStatus)(nil), (*ColorStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ColorStatus_To_v2_ColorStatus(a.(*v1.ColorStatus), b.(*ColorStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImageStatus)(nil), (*v1.ImageStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ImageStatus_To_v1_ImageStatus(a.(*ImageStatus), b.(*v1.ImageStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ImageStatus)(nil), (*ImageStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ImageStatus_To_v2_ImageStatus(a.(*v1.ImageStatus), b.(*ImageStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImageUpdatePolicy)(nil), (*v1.ImageUpdatePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ImageUpdatePolicy_To_v1_ImageUpdatePolicy(a.(*ImageUpdatePolicy), b.(*v1.ImageUpdatePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ImageUpdatePolicy)(nil), (*ImageUpdatePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ImageUpdatePolicy_To_v2_ImageUpdatePolicy(a.(*v1.ImageUpdatePolicy), b.(*ImageUpdatePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImageUpdateStatus)(nil), (*v1.ImageUpdateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ImageUpdateStatus_To_v1_ImageUpdateStatus(a.(*ImageUpdateStatus), b.(*v1.ImageUpdateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ImageUpdateStatus)(nil), (*ImageUpdateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ImageUpdateStatus_To_v2_ImageUpdateStatus(a.(*v1.ImageUpdateStatus), b.(*ImageUpdateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImageVerification)(nil), (*v1.ImageVerification)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ImageVerification_To_v1_ImageVerification(a.(*ImageVerification), b.(*v1.ImageVerification), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.ImageVerification)(nil), (*ImageVerification)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ImageVerification_To_v2_ImageVerification(a.(*v1.ImageVerification), b.(*ImageVerification), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RolloutPause)(nil), (*v1.RolloutPause)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_RolloutPause_To_v1_RolloutPause(a.(*RolloutPause), b.(*v1.RolloutPause), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.RolloutPause)(nil), (*RolloutPause)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_RolloutPause_To_v2_RolloutPause(a.(*v1.RolloutPause), b.(*RolloutPause), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RolloutSpec)(nil), (*v1.RolloutSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_RolloutSpec_To_v1_RolloutSpec(a.(*RolloutSpec), b.(*v1.RolloutSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.RolloutSpec)(nil), (*RolloutSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_RolloutSpec_To_v2_RolloutSpec(a.(*v1.RolloutSpec), b.(*RolloutSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TemplateReference)(nil), (*v1.TemplateReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_TemplateReference_To_v1_TemplateReference(a.(*TemplateReference), b.(*v1.TemplateReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.TemplateReference)(nil), (*TemplateReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_TemplateReference_To_v2_TemplateReference(a.(*v1.TemplateReference), b.(*TemplateReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1.ApplicationSpec)(nil), (*ApplicationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ApplicationSpec_To_v2_ApplicationSpec(a.(*v1.ApplicationSpec), b.(*ApplicationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1.Application)(nil), (*Application)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_Application_To_v2_Application(a.(*v1.Application), b.(*Application), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*ApplicationSpec)(nil), (*v1.ApplicationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_ApplicationSpec_To_v1_ApplicationSpec(a.(*ApplicationSpec), b.(*v1.ApplicationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*Application)(nil), (*v1.Application)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v2_Application_To_v1_Application(a.(*Application), b.(*v1.Application), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v2_Application_To_v1_Application(in *Application, out *v1.Application, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v2_ApplicationSpec_To_v1_ApplicationSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v2_ApplicationStatus_To_v1_ApplicationStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1_Application_To_v2_Application(in *v1.Application, out *Application, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1_ApplicationSpec_To_v2_ApplicationSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1_ApplicationStatus_To_v2_ApplicationStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v2_ApplicationList_To_v1_ApplicationList(in *ApplicationList, out *v1.ApplicationList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1.Application, len(*in))
+		for i := range *in {
+			if err := Convert_v2_Application_To_v1_Application(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v2_ApplicationList_To_v1_ApplicationList is an autogenerated conversion function.
+func Convert_v2_ApplicationList_To_v1_ApplicationList(in *ApplicationList, out *v1.ApplicationList, s conversion.Scope) error {
+	return autoConvert_v2_ApplicationList_To_v1_ApplicationList(in, out, s)
+}
+
+func autoConvert_v1_ApplicationList_To_v2_ApplicationList(in *v1.ApplicationList, out *ApplicationList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Application, len(*in))
+		for i := range *in {
+			if err := Convert_v1_Application_To_v2_Application(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1_ApplicationList_To_v2_ApplicationList is an autogenerated conversion function.
+func Convert_v1_ApplicationList_To_v2_ApplicationList(in *v1.ApplicationList, out *ApplicationList, s conversion.Scope) error {
+	return autoConvert_v1_ApplicationList_To_v2_ApplicationList(in, out, s)
+}
+
+func autoConvert_v2_ApplicationSpec_To_v1_ApplicationSpec(in *ApplicationSpec, out *v1.ApplicationSpec, s conversion.Scope) error {
+	// WARNING: in.Containers requires manual conversion: does not exist in peer-type
+	// WARNING: in.Workload requires manual conversion: does not exist in peer-type
+	out.TemplateRef = (*v1.TemplateReference)(unsafe.Pointer(in.TemplateRef))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.LivenessProbe = (*corev1.Probe)(unsafe.Pointer(in.LivenessProbe))
+	out.ReadinessProbe = (*corev1.Probe)(unsafe.Pointer(in.ReadinessProbe))
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.Affinity = (*corev1.Affinity)(unsafe.Pointer(in.Affinity))
+	out.ImagePullSecrets = *(*[]corev1.LocalObjectReference)(unsafe.Pointer(&in.ImagePullSecrets))
+	out.ImageUpdatePolicy = (*v1.ImageUpdatePolicy)(unsafe.Pointer(in.ImageUpdatePolicy))
+	out.ImageVerification = (*v1.ImageVerification)(unsafe.Pointer(in.ImageVerification))
+	out.Suspend = in.Suspend
+	out.ScaleToZeroOnSuspend = in.ScaleToZeroOnSuspend
+	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.RollbackTo = (*int64)(unsafe.Pointer(in.RollbackTo))
+	out.Rollout = (*v1.RolloutSpec)(unsafe.Pointer(in.Rollout))
+	return nil
+}
+
+func autoConvert_v1_ApplicationSpec_To_v2_ApplicationSpec(in *v1.ApplicationSpec, out *ApplicationSpec, s conversion.Scope) error {
+	// WARNING: in.ImageName requires manual conversion: does not exist in peer-type
+	// WARNING: in.Replicas requires manual conversion: does not exist in peer-type
+	// WARNING: in.Size requires manual conversion: does not exist in peer-type
+	// WARNING: in.Resources requires manual conversion: does not exist in peer-type
+	out.TemplateRef = (*TemplateReference)(unsafe.Pointer(in.TemplateRef))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.LivenessProbe = (*corev1.Probe)(unsafe.Pointer(in.LivenessProbe))
+	out.ReadinessProbe = (*corev1.Probe)(unsafe.Pointer(in.ReadinessProbe))
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.Affinity = (*corev1.Affinity)(unsafe.Pointer(in.Affinity))
+	out.ImagePullSecrets = *(*[]corev1.LocalObjectReference)(unsafe.Pointer(&in.ImagePullSecrets))
+	out.ImageUpdatePolicy = (*ImageUpdatePolicy)(unsafe.Pointer(in.ImageUpdatePolicy))
+	out.ImageVerification = (*ImageVerification)(unsafe.Pointer(in.ImageVerification))
+	out.Suspend = in.Suspend
+	out.ScaleToZeroOnSuspend = in.ScaleToZeroOnSuspend
+	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.RollbackTo = (*int64)(unsafe.Pointer(in.RollbackTo))
+	out.Rollout = (*RolloutSpec)(unsafe.Pointer(in.Rollout))
+	return nil
+}
+
+func autoConvert_v2_ApplicationStatus_To_v1_ApplicationStatus(in *ApplicationStatus, out *v1.ApplicationStatus, s conversion.Scope) error {
+	out.DeploymentRefNamespace = in.DeploymentRefNamespace
+	out.DeploymentRefName = in.DeploymentRefName
+	out.ObservedGeneration = in.ObservedGeneration
+	out.UpdatedReplicas = in.UpdatedReplicas
+	out.ReadyReplicas = in.ReadyReplicas
+	out.CurrentRevision = in.CurrentRevision
+	out.LastReadyImage = in.LastReadyImage
+	out.Image = (*v1.ImageStatus)(unsafe.Pointer(in.Image))
+	out.ImageUpdate = (*v1.ImageUpdateStatus)(unsafe.Pointer(in.ImageUpdate))
+	out.Canary = (*v1.CanaryStatus)(unsafe.Pointer(in.Canary))
+	out.BlueGreen = (*v1.BlueGreenStatus)(unsafe.Pointer(in.BlueGreen))
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+// Convert_v2_ApplicationStatus_To_v1_ApplicationStatus is an autogenerated conversion function.
+func Convert_v2_ApplicationStatus_To_v1_ApplicationStatus(in *ApplicationStatus, out *v1.ApplicationStatus, s conversion.Scope) error {
+	return autoConvert_v2_ApplicationStatus_To_v1_ApplicationStatus(in, out, s)
+}
+
+func autoConvert_v1_ApplicationStatus_To_v2_ApplicationStatus(in *v1.ApplicationStatus, out *ApplicationStatus, s conversion.Scope) error {
+	out.DeploymentRefNamespace = in.DeploymentRefNamespace
+	out.DeploymentRefName = in.DeploymentRefName
+	out.ObservedGeneration = in.ObservedGeneration
+	out.UpdatedReplicas = in.UpdatedReplicas
+	out.ReadyReplicas = in.ReadyReplicas
+	out.CurrentRevision = in.CurrentRevision
+	out.LastReadyImage = in.LastReadyImage
+	out.Image = (*ImageStatus)(unsafe.Pointer(in.Image))
+	out.ImageUpdate = (*ImageUpdateStatus)(unsafe.Pointer(in.ImageUpdate))
+	out.Canary = (*CanaryStatus)(unsafe.Pointer(in.Canary))
+	out.BlueGreen = (*BlueGreenStatus)(unsafe.Pointer(in.BlueGreen))
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+// Convert_v1_ApplicationStatus_To_v2_ApplicationStatus is an autogenerated conversion function.
+func Convert_v1_ApplicationStatus_To_v2_ApplicationStatus(in *v1.ApplicationStatus, out *ApplicationStatus, s conversion.Scope) error {
+	return autoConvert_v1_ApplicationStatus_To_v2_ApplicationStatus(in, out, s)
+}
+
+func autoConvert_v2_BlueGreenStatus_To_v1_BlueGreenStatus(in *BlueGreenStatus, out *v1.BlueGreenStatus, s conversion.Scope) error {
+	out.Phase = v1.BlueGreenPhase(in.Phase)
+	out.ActiveColor = in.ActiveColor
+	out.PreviewColor = in.PreviewColor
+	out.PreviewReadyTime = (*metav1.Time)(unsafe.Pointer(in.PreviewReadyTime))
+	out.ScaleDownTime = (*metav1.Time)(unsafe.Pointer(in.ScaleDownTime))
+	out.Blue = (*v1.ColorStatus)(unsafe.Pointer(in.Blue))
+	out.Green = (*v1.ColorStatus)(unsafe.Pointer(in.Green))
+	return nil
+}
+
+// Convert_v2_BlueGreenStatus_To_v1_BlueGreenStatus is an autogenerated conversion function.
+func Convert_v2_BlueGreenStatus_To_v1_BlueGreenStatus(in *BlueGreenStatus, out *v1.BlueGreenStatus, s conversion.Scope) error {
+	return autoConvert_v2_BlueGreenStatus_To_v1_BlueGreenStatus(in, out, s)
+}
+
+func autoConvert_v1_BlueGreenStatus_To_v2_BlueGreenStatus(in *v1.BlueGreenStatus, out *BlueGreenStatus, s conversion.Scope) error {
+	out.Phase = BlueGreenPhase(in.Phase)
+	out.ActiveColor = in.ActiveColor
+	out.PreviewColor = in.PreviewColor
+	out.PreviewReadyTime = (*metav1.Time)(unsafe.Pointer(in.PreviewReadyTime))
+	out.ScaleDownTime = (*metav1.Time)(unsafe.Pointer(in.ScaleDownTime))
+	out.Blue = (*ColorStatus)(unsafe.Pointer(in.Blue))
+	out.Green = (*ColorStatus)(unsafe.Pointer(in.Green))
+	return nil
+}
+
+// Convert_v1_BlueGreenStatus_To_v2_BlueGreenStatus is an autogenerated conversion function.
+func Convert_v1_BlueGreenStatus_To_v2_BlueGreenStatus(in *v1.BlueGreenStatus, out *BlueGreenStatus, s conversion.Scope) error {
+	return autoConvert_v1_BlueGreenStatus_To_v2_BlueGreenStatus(in, out, s)
+}
+
+func autoConvert_v2_BlueGreenStrategy_To_v1_BlueGreenStrategy(in *BlueGreenStrategy, out *v1.BlueGreenStrategy, s conversion.Scope) error {
+	out.Port = in.Port
+	out.AutoPromotionDelay = (*metav1.Duration)(unsafe.Pointer(in.AutoPromotionDelay))
+	out.ScaleDownDelay = (*metav1.Duration)(unsafe.Pointer(in.ScaleDownDelay))
+	return nil
+}
+
+// Convert_v2_BlueGreenStrategy_To_v1_BlueGreenStrategy is an autogenerated conversion function.
+func Convert_v2_BlueGreenStrategy_To_v1_BlueGreenStrategy(in *BlueGreenStrategy, out *v1.BlueGreenStrategy, s conversion.Scope) error {
+	return autoConvert_v2_BlueGreenStrategy_To_v1_BlueGreenStrategy(in, out, s)
+}
+
+func autoConvert_v1_BlueGreenStrategy_To_v2_BlueGreenStrategy(in *v1.BlueGreenStrategy, out *BlueGreenStrategy, s conversion.Scope) error {
+	out.Port = in.Port
+	out.AutoPromotionDelay = (*metav1.Duration)(unsafe.Pointer(in.AutoPromotionDelay))
+	out.ScaleDownDelay = (*metav1.Duration)(unsafe.Pointer(in.ScaleDownDelay))
+	return nil
+}
+
+// Convert_v1_BlueGreenStrategy_To_v2_BlueGreenStrategy is an autogenerated conversion function.
+func Convert_v1_BlueGreenStrategy_To_v2_BlueGreenStrategy(in *v1.BlueGreenStrategy, out *BlueGreenStrategy, s conversion.Scope) error {
+	return autoConvert_v1_BlueGreenStrategy_To_v2_BlueGreenStrategy(in, out, s)
+}
+
+func autoConvert_v2_CanaryStatus_To_v1_CanaryStatus(in *CanaryStatus, out *v1.CanaryStatus, s conversion.Scope) error {
+	out.Phase = v1.CanaryPhase(in.Phase)
+	out.StableImage = in.StableImage
+	out.CanaryImage = in.CanaryImage
+	out.CurrentStepIndex = in.CurrentStepIndex
+	out.CurrentStepStartTime = (*metav1.Time)(unsafe.Pointer(in.CurrentStepStartTime))
+	out.Weight = in.Weight
+	out.StableReplicas = in.StableReplicas
+	out.CanaryReplicas = in.CanaryReplicas
+	return nil
+}
+
+// Convert_v2_CanaryStatus_To_v1_CanaryStatus is an autogenerated conversion function.
+func Convert_v2_CanaryStatus_To_v1_CanaryStatus(in *CanaryStatus, out *v1.CanaryStatus, s conversion.Scope) error {
+	return autoConvert_v2_CanaryStatus_To_v1_CanaryStatus(in, out, s)
+}
+
+func autoConvert_v1_CanaryStatus_To_v2_CanaryStatus(in *v1.CanaryStatus, out *CanaryStatus, s conversion.Scope) error {
+	out.Phase = CanaryPhase(in.Phase)
+	out.StableImage = in.StableImage
+	out.CanaryImage = in.CanaryImage
+	out.CurrentStepIndex = in.CurrentStepIndex
+	out.CurrentStepStartTime = (*metav1.Time)(unsafe.Pointer(in.CurrentStepStartTime))
+	out.Weight = in.Weight
+	out.StableReplicas = in.StableReplicas
+	out.CanaryReplicas = in.CanaryReplicas
+	return nil
+}
+
+// Convert_v1_CanaryStatus_To_v2_CanaryStatus is an autogenerated conversion function.
+func Convert_v1_CanaryStatus_To_v2_CanaryStatus(in *v1.CanaryStatus, out *CanaryStatus, s conversion.Scope) error {
+	return autoConvert_v1_CanaryStatus_To_v2_CanaryStatus(in, out, s)
+}
+
+func autoConvert_v2_CanaryStep_To_v1_CanaryStep(in *CanaryStep, out *v1.CanaryStep, s conversion.Scope) error {
+	out.SetWeight = (*int32)(unsafe.Pointer(in.SetWeight))
+	out.Pause = (*v1.RolloutPause)(unsafe.Pointer(in.Pause))
+	return nil
+}
+
+// Convert_v2_CanaryStep_To_v1_CanaryStep is an autogenerated conversion function.
+func Convert_v2_CanaryStep_To_v1_CanaryStep(in *CanaryStep, out *v1.CanaryStep, s conversion.Scope) error {
+	return autoConvert_v2_CanaryStep_To_v1_CanaryStep(in, out, s)
+}
+
+func autoConvert_v1_CanaryStep_To_v2_CanaryStep(in *v1.CanaryStep, out *CanaryStep, s conversion.Scope) error {
+	out.SetWeight = (*int32)(unsafe.Pointer(in.SetWeight))
+	out.Pause = (*RolloutPause)(unsafe.Pointer(in.Pause))
+	return nil
+}
+
+// Convert_v1_CanaryStep_To_v2_CanaryStep is an autogenerated conversion function.
+func Convert_v1_CanaryStep_To_v2_CanaryStep(in *v1.CanaryStep, out *CanaryStep, s conversion.Scope) error {
+	return autoConvert_v1_CanaryStep_To_v2_CanaryStep(in, out, s)
+}
+
+func autoConvert_v2_CanaryStrategy_To_v1_CanaryStrategy(in *CanaryStrategy, out *v1.CanaryStrategy, s conversion.Scope) error {
+	out.Steps = *(*[]v1.CanaryStep)(unsafe.Pointer(&in.Steps))
+	return nil
+}
+
+// Convert_v2_CanaryStrategy_To_v1_CanaryStrategy is an autogenerated conversion function.
+func Convert_v2_CanaryStrategy_To_v1_CanaryStrategy(in *CanaryStrategy, out *v1.CanaryStrategy, s conversion.Scope) error {
+	return autoConvert_v2_CanaryStrategy_To_v1_CanaryStrategy(in, out, s)
+}
+
+func autoConvert_v1_CanaryStrategy_To_v2_CanaryStrategy(in *v1.CanaryStrategy, out *CanaryStrategy, s conversion.Scope) error {
+	out.Steps = *(*[]CanaryStep)(unsafe.Pointer(&in.Steps))
+	return nil
+}
+
+// Convert_v1_CanaryStrategy_To_v2_CanaryStrategy is an autogenerated conversion function.
+func Convert_v1_CanaryStrategy_To_v2_CanaryStrategy(in *v1.CanaryStrategy, out *CanaryStrategy, s conversion.Scope) error {
+	return autoConvert_v1_CanaryStrategy_To_v2_CanaryStrategy(in, out, s)
+}
+
+func autoConvert_v2_ColorStatus_To_v1_ColorStatus(in *ColorStatus, out *v1.ColorStatus, s conversion.Scope) error {
+	out.Image = in.Image
+	out.Replicas = in.Replicas
+	out.ReadyReplicas = in.ReadyReplicas
+	return nil
+}
+
+// Convert_v2_ColorStatus_To_v1_ColorStatus is an autogenerated conversion function.
+func Convert_v2_ColorStatus_To_v1_ColorStatus(in *ColorStatus, out *v1.ColorStatus, s conversion.Scope) error {
+	return autoConvert_v2_ColorStatus_To_v1_ColorStatus(in, out, s)
+}
+
+func autoConvert_v1_ColorStatus_To_v2_ColorStatus(in *v1.ColorStatus, out *ColorStatus, s conversion.Scope) error {
+	out.Image = in.Image
+	out.Replicas = in.Replicas
+	out.ReadyReplicas = in.ReadyReplicas
+	return nil
+}
+
+// Convert_v1_ColorStatus_To_v2_ColorStatus is an autogenerated conversion function.
+func Convert_v1_ColorStatus_To_v2_ColorStatus(in *v1.ColorStatus, out *ColorStatus, s conversion.Scope) error {
+	return autoConvert_v1_ColorStatus_To_v2_ColorStatus(in, out, s)
+}
+
+func autoConvert_v2_ImageStatus_To_v1_ImageStatus(in *ImageStatus, out *v1.ImageStatus, s conversion.Scope) error {
+	out.Tag = in.Tag
+	out.Digest = in.Digest
+	return nil
+}
+
+// Convert_v2_ImageStatus_To_v1_ImageStatus is an autogenerated conversion function.
+func Convert_v2_ImageStatus_To_v1_ImageStatus(in *ImageStatus, out *v1.ImageStatus, s conversion.Scope) error {
+	return autoConvert_v2_ImageStatus_To_v1_ImageStatus(in, out, s)
+}
+
+func autoConvert_v1_ImageStatus_To_v2_ImageStatus(in *v1.ImageStatus, out *ImageStatus, s conversion.Scope) error {
+	out.Tag = in.Tag
+	out.Digest = in.Digest
+	return nil
+}
+
+// Convert_v1_ImageStatus_To_v2_ImageStatus is an autogenerated conversion function.
+func Convert_v1_ImageStatus_To_v2_ImageStatus(in *v1.ImageStatus, out *ImageStatus, s conversion.Scope) error {
+	return autoConvert_v1_ImageStatus_To_v2_ImageStatus(in, out, s)
+}
+
+func autoConvert_v2_ImageUpdatePolicy_To_v1_ImageUpdatePolicy(in *ImageUpdatePolicy, out *v1.ImageUpdatePolicy, s conversion.Scope) error {
+	out.Semver = in.Semver
+	out.Filter = in.Filter
+	out.Interval = (*metav1.Duration)(unsafe.Pointer(in.Interval))
+	return nil
+}
+
+// Convert_v2_ImageUpdatePolicy_To_v1_ImageUpdatePolicy is an autogenerated conversion function.
+func Convert_v2_ImageUpdatePolicy_To_v1_ImageUpdatePolicy(in *ImageUpdatePolicy, out *v1.ImageUpdatePolicy, s conversion.Scope) error {
+	return autoConvert_v2_ImageUpdatePolicy_To_v1_ImageUpdatePolicy(in, out, s)
+}
+
+func autoConvert_v1_ImageUpdatePolicy_To_v2_ImageUpdatePolicy(in *v1.ImageUpdatePolicy, out *ImageUpdatePolicy, s conversion.Scope) error {
+	out.Semver = in.Semver
+	out.Filter = in.Filter
+	out.Interval = (*metav1.Duration)(unsafe.Pointer(in.Interval))
+	return nil
+}
+
+// Convert_v1_ImageUpdatePolicy_To_v2_ImageUpdatePolicy is an autogenerated conversion function.
+func Convert_v1_ImageUpdatePolicy_To_v2_ImageUpdatePolicy(in *v1.ImageUpdatePolicy, out *ImageUpdatePolicy, s conversion.Scope) error {
+	return autoConvert_v1_ImageUpdatePolicy_To_v2_ImageUpdatePolicy(in, out, s)
+}
+
+func autoConvert_v2_ImageUpdateStatus_To_v1_ImageUpdateStatus(in *ImageUpdateStatus, out *v1.ImageUpdateStatus, s conversion.Scope) error {
+	out.LastCheckTime = (*metav1.Time)(unsafe.Pointer(in.LastCheckTime))
+	out.LastUpdateTime = (*metav1.Time)(unsafe.Pointer(in.LastUpdateTime))
+	out.From = in.From
+	out.To = in.To
+	return nil
+}
+
+// Convert_v2_ImageUpdateStatus_To_v1_ImageUpdateStatus is an autogenerated conversion function.
+func Convert_v2_ImageUpdateStatus_To_v1_ImageUpdateStatus(in *ImageUpdateStatus, out *v1.ImageUpdateStatus, s conversion.Scope) error {
+	return autoConvert_v2_ImageUpdateStatus_To_v1_ImageUpdateStatus(in, out, s)
+}
+
+func autoConvert_v1_ImageUpdateStatus_To_v2_ImageUpdateStatus(in *v1.ImageUpdateStatus, out *ImageUpdateStatus, s conversion.Scope) error {
+	out.LastCheckTime = (*metav1.Time)(unsafe.Pointer(in.LastCheckTime))
+	out.LastUpdateTime = (*metav1.Time)(unsafe.Pointer(in.LastUpdateTime))
+	out.From = in.From
+	out.To = in.To
+	return nil
+}
+
+// Convert_v1_ImageUpdateStatus_To_v2_ImageUpdateStatus is an autogenerated conversion function.
+func Convert_v1_ImageUpdateStatus_To_v2_ImageUpdateStatus(in *v1.ImageUpdateStatus, out *ImageUpdateStatus, s conversion.Scope) error {
+	return autoConvert_v1_ImageUpdateStatus_To_v2_ImageUpdateStatus(in, out, s)
+}
+
+func autoConvert_v2_ImageVerification_To_v1_ImageVerification(in *ImageVerification, out *v1.ImageVerification, s conversion.Scope) error {
+	out.PublicKeysSecret = in.PublicKeysSecret
+	return nil
+}
+
+// Convert_v2_ImageVerification_To_v1_ImageVerification is an autogenerated conversion function.
+func Convert_v2_ImageVerification_To_v1_ImageVerification(in *ImageVerification, out *v1.ImageVerification, s conversion.Scope) error {
+	return autoConvert_v2_ImageVerification_To_v1_ImageVerification(in, out, s)
+}
+
+func autoConvert_v1_ImageVerification_To_v2_ImageVerification(in *v1.ImageVerification, out *ImageVerification, s conversion.Scope) error {
+	out.PublicKeysSecret = in.PublicKeysSecret
+	return nil
+}
+
+// Convert_v1_ImageVerification_To_v2_ImageVerification is an autogenerated conversion function.
+func Convert_v1_ImageVerification_To_v2_ImageVerification(in *v1.ImageVerification, out *ImageVerification, s conversion.Scope) error {
+	return autoConvert_v1_ImageVerification_To_v2_ImageVerification(in, out, s)
+}
+
+func autoConvert_v2_RolloutPause_To_v1_RolloutPause(in *RolloutPause, out *v1.RolloutPause, s conversion.Scope) error {
+	out.Duration = (*metav1.Duration)(unsafe.Pointer(in.Duration))
+	return nil
+}
+
+// Convert_v2_RolloutPause_To_v1_RolloutPause is an autogenerated conversion function.
+func Convert_v2_RolloutPause_To_v1_RolloutPause(in *RolloutPause, out *v1.RolloutPause, s conversion.Scope) error {
+	return autoConvert_v2_RolloutPause_To_v1_RolloutPause(in, out, s)
+}
+
+func autoConvert_v1_RolloutPause_To_v2_RolloutPause(in *v1.RolloutPause, out *RolloutPause, s conversion.Scope) error {
+	out.Duration = (*metav1.Duration)(unsafe.Pointer(in.Duration))
+	return nil
+}
+
+// Convert_v1_RolloutPause_To_v2_RolloutPause is an autogenerated conversion function.
+func Convert_v1_RolloutPause_To_v2_RolloutPause(in *v1.RolloutPause, out *RolloutPause, s conversion.Scope) error {
+	return autoConvert_v1_RolloutPause_To_v2_RolloutPause(in, out, s)
+}
+
+func autoConvert_v2_RolloutSpec_To_v1_RolloutSpec(in *RolloutSpec, out *v1.RolloutSpec, s conversion.Scope) error {
+	out.Canary = (*v1.CanaryStrategy)(unsafe.Pointer(in.Canary))
+	out.BlueGreen = (*v1.BlueGreenStrategy)(unsafe.Pointer(in.BlueGreen))
+	out.Promote = in.Promote
+	return nil
+}
+
+// Convert_v2_RolloutSpec_To_v1_RolloutSpec is an autogenerated conversion function.
+func Convert_v2_RolloutSpec_To_v1_RolloutSpec(in *RolloutSpec, out *v1.RolloutSpec, s conversion.Scope) error {
+	return autoConvert_v2_RolloutSpec_To_v1_RolloutSpec(in, out, s)
+}
+
+func autoConvert_v1_RolloutSpec_To_v2_RolloutSpec(in *v1.RolloutSpec, out *RolloutSpec, s conversion.Scope) error {
+	out.Canary = (*CanaryStrategy)(unsafe.Pointer(in.Canary))
+	out.BlueGreen = (*BlueGreenStrategy)(unsafe.Pointer(in.BlueGreen))
+	out.Promote = in.Promote
+	return nil
+}
+
+// Convert_v1_RolloutSpec_To_v2_RolloutSpec is an autogenerated conversion function.
+func Convert_v1_RolloutSpec_To_v2_RolloutSpec(in *v1.RolloutSpec, out *RolloutSpec, s conversion.Scope) error {
+	return autoConvert_v1_RolloutSpec_To_v2_RolloutSpec(in, out, s)
+}
+
+func autoConvert_v2_TemplateReference_To_v1_TemplateReference(in *TemplateReference, out *v1.TemplateReference, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_v2_TemplateReference_To_v1_TemplateReference is an autogenerated conversion function.
+func Convert_v2_TemplateReference_To_v1_TemplateReference(in *TemplateReference, out *v1.TemplateReference, s conversion.Scope) error {
+	return autoConvert_v2_TemplateReference_To_v1_TemplateReference(in, out, s)
+}
+
+func autoConvert_v1_TemplateReference_To_v2_TemplateReference(in *v1.TemplateReference, out *TemplateReference, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_v1_TemplateReference_To_v2_TemplateReference is an autogenerated conversion function.
+func Convert_v1_TemplateReference_To_v2_TemplateReference(in *v1.TemplateReference, out *TemplateReference, s conversion.Scope) error {
+	return autoConvert_v1_TemplateReference_To_v2_TemplateReference(in, out, s)
+}
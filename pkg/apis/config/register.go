@@ -0,0 +1,5 @@
+package config
+
+const (
+	GroupName = "config.cloudest.artifakt.io"
+)
@@ -0,0 +1,48 @@
+package v1alpha1
+
+import (
+	"time"
+)
+
+// SetDefaults_ControllerConfiguration fills the unset fields of obj with
+// their default value.
+func SetDefaults_ControllerConfiguration(obj *ControllerConfiguration) {
+	if obj.Workers == 0 {
+		obj.Workers = 2
+	}
+	if obj.ResyncPeriod.Duration == 0 {
+		obj.ResyncPeriod.Duration = 30 * time.Second
+	}
+	if obj.ReconcileTimeout.Duration == 0 {
+		obj.ReconcileTimeout.Duration = time.Minute
+	}
+	if obj.ShutdownGracePeriod.Duration == 0 {
+		obj.ShutdownGracePeriod.Duration = 30 * time.Second
+	}
+	if obj.Backoff.BaseDelay.Duration == 0 {
+		obj.Backoff.BaseDelay.Duration = 5 * time.Millisecond
+	}
+	if obj.Backoff.MaxDelay.Duration == 0 {
+		obj.Backoff.MaxDelay.Duration = 1000 * time.Second
+	}
+	if obj.Backoff.QPS == 0 {
+		obj.Backoff.QPS = 10
+	}
+	if obj.Backoff.Burst == 0 {
+		obj.Backoff.Burst = 100
+	}
+	if obj.ClientConnection.QPS == 0 {
+		obj.ClientConnection.QPS = 20
+	}
+	if obj.ClientConnection.Burst == 0 {
+		obj.ClientConnection.Burst = 30
+	}
+	if obj.FeatureGates == nil {
+		obj.FeatureGates = map[string]bool{}
+	}
+	for feature, enabled := range DefaultFeatureGates {
+		if _, ok := obj.FeatureGates[feature]; !ok {
+			obj.FeatureGates[feature] = enabled
+		}
+	}
+}
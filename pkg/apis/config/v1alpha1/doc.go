@@ -0,0 +1,9 @@
+// +k8s:deepcopy-gen=package
+// +groupName=config.cloudest.artifakt.io
+
+// Package v1alpha1 is the v1alpha1 version of the controller configuration.
+package v1alpha1
+
+const (
+	Version = "v1alpha1"
+)
@@ -0,0 +1,72 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ControllerConfiguration is the configuration of the controller process,
+// loaded with --config.
+type ControllerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Workers is the number of applications reconciled concurrently.
+	// Defaults to 2.
+	Workers int32 `json:"workers,omitempty"`
+	// ResyncPeriod is the period after which the informers resync every
+	// watched object. Defaults to 30s.
+	ResyncPeriod metav1.Duration `json:"resyncPeriod,omitempty"`
+	// ReconcileTimeout bounds the duration of the reconcile of an
+	// application. Defaults to 1m.
+	ReconcileTimeout metav1.Duration `json:"reconcileTimeout,omitempty"`
+	// ShutdownGracePeriod is the time left to in-flight reconciles to finish
+	// on shutdown before they are cancelled. Defaults to 30s.
+	ShutdownGracePeriod metav1.Duration `json:"shutdownGracePeriod,omitempty"`
+	// Backoff is the rate limiting of the retries of failed reconciles.
+	Backoff BackoffConfiguration `json:"backoff,omitempty"`
+	// ClientConnection is the rate limiting of the requests to the API
+	// server.
+	ClientConnection ClientConnectionConfiguration `json:"clientConnection,omitempty"`
+	// FeatureGates enables or disables the features of the controller by
+	// name.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// BackoffConfiguration rate limits the workqueue: a failed application is
+// retried after an exponential delay, and all retries share a token bucket.
+type BackoffConfiguration struct {
+	// BaseDelay is the delay before the first retry. Defaults to 5ms.
+	BaseDelay metav1.Duration `json:"baseDelay,omitempty"`
+	// MaxDelay caps the exponential delay. Defaults to 1000s.
+	MaxDelay metav1.Duration `json:"maxDelay,omitempty"`
+	// QPS is the rate of the token bucket. Defaults to 10.
+	QPS float32 `json:"qps,omitempty"`
+	// Burst is the size of the token bucket. Defaults to 100.
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// ClientConnectionConfiguration rate limits the clients of the API server.
+type ClientConnectionConfiguration struct {
+	// QPS is the sustained rate of requests. Defaults to 20.
+	QPS float32 `json:"qps,omitempty"`
+	// Burst is the number of requests allowed above QPS. Defaults to 30.
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// Feature gates of the controller.
+const (
+	// AutoRollback reverts the workload to the last ready image when the
+	// rollout of a new image fails. Enabled by default.
+	AutoRollback = "AutoRollback"
+	// PinImageDigests resolves the tag of the image of each application to
+	// a digest through its registry, and rolls the digest out. Enabled by
+	// default.
+	PinImageDigests = "PinImageDigests"
+)
+
+// DefaultFeatureGates are the known feature gates with their default value.
+var DefaultFeatureGates = map[string]bool{
+	AutoRollback:    true,
+	PinImageDigests: true,
+}
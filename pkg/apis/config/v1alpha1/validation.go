@@ -0,0 +1,60 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateControllerConfiguration returns the errors of a defaulted
+// configuration.
+func ValidateControllerConfiguration(obj *ControllerConfiguration) field.ErrorList {
+	var errs field.ErrorList
+	if obj.Workers < 1 {
+		errs = append(errs, field.Invalid(field.NewPath("workers"), obj.Workers, "must be at least 1"))
+	}
+	if obj.ResyncPeriod.Duration < 0 {
+		errs = append(errs, field.Invalid(field.NewPath("resyncPeriod"), obj.ResyncPeriod.Duration.String(), "must not be negative"))
+	}
+	if obj.ReconcileTimeout.Duration <= 0 {
+		errs = append(errs, field.Invalid(field.NewPath("reconcileTimeout"), obj.ReconcileTimeout.Duration.String(), "must be positive"))
+	}
+	if obj.ShutdownGracePeriod.Duration < 0 {
+		errs = append(errs, field.Invalid(field.NewPath("shutdownGracePeriod"), obj.ShutdownGracePeriod.Duration.String(), "must not be negative"))
+	}
+
+	backoff := field.NewPath("backoff")
+	if obj.Backoff.BaseDelay.Duration <= 0 {
+		errs = append(errs, field.Invalid(backoff.Child("baseDelay"), obj.Backoff.BaseDelay.Duration.String(), "must be positive"))
+	}
+	if obj.Backoff.MaxDelay.Duration < obj.Backoff.BaseDelay.Duration {
+		errs = append(errs, field.Invalid(backoff.Child("maxDelay"), obj.Backoff.MaxDelay.Duration.String(), "must not be less than baseDelay"))
+	}
+	if obj.Backoff.QPS <= 0 {
+		errs = append(errs, field.Invalid(backoff.Child("qps"), obj.Backoff.QPS, "must be positive"))
+	}
+	if obj.Backoff.Burst < 1 {
+		errs = append(errs, field.Invalid(backoff.Child("burst"), obj.Backoff.Burst, "must be at least 1"))
+	}
+
+	clientConnection := field.NewPath("clientConnection")
+	if obj.ClientConnection.QPS <= 0 {
+		errs = append(errs, field.Invalid(clientConnection.Child("qps"), obj.ClientConnection.QPS, "must be positive"))
+	}
+	if obj.ClientConnection.Burst < 1 {
+		errs = append(errs, field.Invalid(clientConnection.Child("burst"), obj.ClientConnection.Burst, "must be at least 1"))
+	}
+
+	for feature := range obj.FeatureGates {
+		if _, ok := DefaultFeatureGates[feature]; !ok {
+			errs = append(errs, field.NotSupported(field.NewPath("featureGates").Key(feature), feature, knownFeatureGates()))
+		}
+	}
+	return errs
+}
+
+func knownFeatureGates() []string {
+	var features []string
+	for feature := range DefaultFeatureGates {
+		features = append(features, feature)
+	}
+	return features
+}
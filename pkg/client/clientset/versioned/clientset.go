@@ -8,8 +8,10 @@ package versioned
 
 import (
 	"fmt"
+	"net/http"
 
 	cloudestv1 "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/typed/application/v1"
+	cloudestv2 "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/typed/application/v2"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -18,6 +20,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	CloudestV1() cloudestv1.CloudestV1Interface
+	CloudestV2() cloudestv2.CloudestV2Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -25,6 +28,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	cloudestV1 *cloudestv1.CloudestV1Client
+	cloudestV2 *cloudestv2.CloudestV2Client
 }
 
 // CloudestV1 retrieves the CloudestV1Client
@@ -32,6 +36,11 @@ func (c *Clientset) CloudestV1() cloudestv1.CloudestV1Interface {
 	return c.cloudestV1
 }
 
+// CloudestV2 retrieves the CloudestV2Client
+func (c *Clientset) CloudestV2() cloudestv2.CloudestV2Interface {
+	return c.cloudestV2
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -43,22 +52,49 @@ func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 // NewForConfig creates a new Clientset for the given config.
 // If config's RateLimiter is not set and QPS and Burst are acceptable,
 // NewForConfig will generate a rate-limiter in configShallowCopy.
+// NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
+// where httpClient was generated with rest.HTTPClientFor(c).
 func NewForConfig(c *rest.Config) (*Clientset, error) {
 	configShallowCopy := *c
+
+	if configShallowCopy.UserAgent == "" {
+		configShallowCopy.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+
+	// share the transport between all clients
+	httpClient, err := rest.HTTPClientFor(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewForConfigAndClient(&configShallowCopy, httpClient)
+}
+
+// NewForConfigAndClient creates a new Clientset for the given config and http client.
+// Note the http client provided takes precedence over the configured transport values.
+// If config's RateLimiter is not set and QPS and Burst are acceptable,
+// NewForConfigAndClient will generate a rate-limiter in configShallowCopy.
+func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset, error) {
+	configShallowCopy := *c
 	if configShallowCopy.RateLimiter == nil && configShallowCopy.QPS > 0 {
 		if configShallowCopy.Burst <= 0 {
 			return nil, fmt.Errorf("burst is required to be greater than 0 when RateLimiter is not set and QPS is set to greater than 0")
 		}
 		configShallowCopy.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(configShallowCopy.QPS, configShallowCopy.Burst)
 	}
+
 	var cs Clientset
 	var err error
-	cs.cloudestV1, err = cloudestv1.NewForConfig(&configShallowCopy)
+	cs.cloudestV1, err = cloudestv1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	cs.cloudestV2, err = cloudestv2.NewForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
 		return nil, err
 	}
 
-	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
+	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
 		return nil, err
 	}
@@ -68,17 +104,18 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 // NewForConfigOrDie creates a new Clientset for the given config and
 // panics if there is an error in the config.
 func NewForConfigOrDie(c *rest.Config) *Clientset {
-	var cs Clientset
-	cs.cloudestV1 = cloudestv1.NewForConfigOrDie(c)
-
-	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
-	return &cs
+	cs, err := NewForConfig(c)
+	if err != nil {
+		panic(err)
+	}
+	return cs
 }
 
 // New creates a new Clientset for the given RESTClient.
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.cloudestV1 = cloudestv1.New(c)
+	cs.cloudestV2 = cloudestv2.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
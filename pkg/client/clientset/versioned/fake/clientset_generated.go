@@ -10,6 +10,8 @@ import (
 	clientset "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
 	cloudestv1 "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/typed/application/v1"
 	fakecloudestv1 "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/typed/application/v1/fake"
+	cloudestv2 "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/typed/application/v2"
+	fakecloudestv2 "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/typed/application/v2/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -71,3 +73,8 @@ var (
 func (c *Clientset) CloudestV1() cloudestv1.CloudestV1Interface {
 	return &fakecloudestv1.FakeCloudestV1{Fake: &c.Fake}
 }
+
+// CloudestV2 retrieves the CloudestV2Client
+func (c *Clientset) CloudestV2() cloudestv2.CloudestV2Interface {
+	return &fakecloudestv2.FakeCloudestV2{Fake: &c.Fake}
+}
@@ -7,6 +7,8 @@ Artifakt Platform generated code
 package v1
 
 import (
+	"net/http"
+
 	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
 	"github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/scheme"
 	rest "k8s.io/client-go/rest"
@@ -15,6 +17,11 @@ import (
 type CloudestV1Interface interface {
 	RESTClient() rest.Interface
 	ApplicationsGetter
+	ApplicationPoliciesGetter
+	ApplicationQuotasGetter
+	ApplicationRevisionsGetter
+	ApplicationTemplatesGetter
+	ClusterApplicationTemplatesGetter
 }
 
 // CloudestV1Client is used to interact with features provided by the cloudest.artifakt.io group.
@@ -26,13 +33,49 @@ func (c *CloudestV1Client) Applications(namespace string) ApplicationInterface {
 	return newApplications(c, namespace)
 }
 
+func (c *CloudestV1Client) ApplicationPolicies() ApplicationPolicyInterface {
+	return newApplicationPolicies(c)
+}
+
+func (c *CloudestV1Client) ApplicationQuotas(namespace string) ApplicationQuotaInterface {
+	return newApplicationQuotas(c, namespace)
+}
+
+func (c *CloudestV1Client) ApplicationRevisions(namespace string) ApplicationRevisionInterface {
+	return newApplicationRevisions(c, namespace)
+}
+
+func (c *CloudestV1Client) ApplicationTemplates(namespace string) ApplicationTemplateInterface {
+	return newApplicationTemplates(c, namespace)
+}
+
+func (c *CloudestV1Client) ClusterApplicationTemplates() ClusterApplicationTemplateInterface {
+	return newClusterApplicationTemplates(c)
+}
+
 // NewForConfig creates a new CloudestV1Client for the given config.
+// NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
+// where httpClient was generated with rest.HTTPClientFor(c).
 func NewForConfig(c *rest.Config) (*CloudestV1Client, error) {
 	config := *c
 	if err := setConfigDefaults(&config); err != nil {
 		return nil, err
 	}
-	client, err := rest.RESTClientFor(&config)
+	httpClient, err := rest.HTTPClientFor(&config)
+	if err != nil {
+		return nil, err
+	}
+	return NewForConfigAndClient(&config, httpClient)
+}
+
+// NewForConfigAndClient creates a new CloudestV1Client for the given config and http client.
+// Note the http client provided takes precedence over the configured transport values.
+func NewForConfigAndClient(c *rest.Config, h *http.Client) (*CloudestV1Client, error) {
+	config := *c
+	if err := setConfigDefaults(&config); err != nil {
+		return nil, err
+	}
+	client, err := rest.RESTClientForConfigAndClient(&config, h)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,156 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	scheme "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ApplicationPoliciesGetter has a method to return a ApplicationPolicyInterface.
+// A group's client should implement this interface.
+type ApplicationPoliciesGetter interface {
+	ApplicationPolicies() ApplicationPolicyInterface
+}
+
+// ApplicationPolicyInterface has methods to work with ApplicationPolicy resources.
+type ApplicationPolicyInterface interface {
+	Create(ctx context.Context, applicationPolicy *v1.ApplicationPolicy, opts metav1.CreateOptions) (*v1.ApplicationPolicy, error)
+	Update(ctx context.Context, applicationPolicy *v1.ApplicationPolicy, opts metav1.UpdateOptions) (*v1.ApplicationPolicy, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ApplicationPolicy, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.ApplicationPolicyList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ApplicationPolicy, err error)
+	ApplicationPolicyExpansion
+}
+
+// applicationPolicies implements ApplicationPolicyInterface
+type applicationPolicies struct {
+	client rest.Interface
+}
+
+// newApplicationPolicies returns a ApplicationPolicies
+func newApplicationPolicies(c *CloudestV1Client) *applicationPolicies {
+	return &applicationPolicies{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the applicationPolicy, and returns the corresponding applicationPolicy object, and an error if there is any.
+func (c *applicationPolicies) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ApplicationPolicy, err error) {
+	result = &v1.ApplicationPolicy{}
+	err = c.client.Get().
+		Resource("applicationpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ApplicationPolicies that match those selectors.
+func (c *applicationPolicies) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ApplicationPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ApplicationPolicyList{}
+	err = c.client.Get().
+		Resource("applicationpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested applicationPolicies.
+func (c *applicationPolicies) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("applicationpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a applicationPolicy and creates it.  Returns the server's representation of the applicationPolicy, and an error, if there is any.
+func (c *applicationPolicies) Create(ctx context.Context, applicationPolicy *v1.ApplicationPolicy, opts metav1.CreateOptions) (result *v1.ApplicationPolicy, err error) {
+	result = &v1.ApplicationPolicy{}
+	err = c.client.Post().
+		Resource("applicationpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(applicationPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a applicationPolicy and updates it. Returns the server's representation of the applicationPolicy, and an error, if there is any.
+func (c *applicationPolicies) Update(ctx context.Context, applicationPolicy *v1.ApplicationPolicy, opts metav1.UpdateOptions) (result *v1.ApplicationPolicy, err error) {
+	result = &v1.ApplicationPolicy{}
+	err = c.client.Put().
+		Resource("applicationpolicies").
+		Name(applicationPolicy.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(applicationPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the applicationPolicy and deletes it. Returns an error if one occurs.
+func (c *applicationPolicies) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("applicationpolicies").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *applicationPolicies) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("applicationpolicies").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched applicationPolicy.
+func (c *applicationPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ApplicationPolicy, err error) {
+	result = &v1.ApplicationPolicy{}
+	err = c.client.Patch(pt).
+		Resource("applicationpolicies").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
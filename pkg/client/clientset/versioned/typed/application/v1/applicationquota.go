@@ -0,0 +1,183 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	scheme "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ApplicationQuotasGetter has a method to return a ApplicationQuotaInterface.
+// A group's client should implement this interface.
+type ApplicationQuotasGetter interface {
+	ApplicationQuotas(namespace string) ApplicationQuotaInterface
+}
+
+// ApplicationQuotaInterface has methods to work with ApplicationQuota resources.
+type ApplicationQuotaInterface interface {
+	Create(ctx context.Context, applicationQuota *v1.ApplicationQuota, opts metav1.CreateOptions) (*v1.ApplicationQuota, error)
+	Update(ctx context.Context, applicationQuota *v1.ApplicationQuota, opts metav1.UpdateOptions) (*v1.ApplicationQuota, error)
+	UpdateStatus(ctx context.Context, applicationQuota *v1.ApplicationQuota, opts metav1.UpdateOptions) (*v1.ApplicationQuota, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ApplicationQuota, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.ApplicationQuotaList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ApplicationQuota, err error)
+	ApplicationQuotaExpansion
+}
+
+// applicationQuotas implements ApplicationQuotaInterface
+type applicationQuotas struct {
+	client rest.Interface
+	ns     string
+}
+
+// newApplicationQuotas returns a ApplicationQuotas
+func newApplicationQuotas(c *CloudestV1Client, namespace string) *applicationQuotas {
+	return &applicationQuotas{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the applicationQuota, and returns the corresponding applicationQuota object, and an error if there is any.
+func (c *applicationQuotas) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ApplicationQuota, err error) {
+	result = &v1.ApplicationQuota{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("applicationquotas").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ApplicationQuotas that match those selectors.
+func (c *applicationQuotas) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ApplicationQuotaList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ApplicationQuotaList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("applicationquotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested applicationQuotas.
+func (c *applicationQuotas) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("applicationquotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a applicationQuota and creates it.  Returns the server's representation of the applicationQuota, and an error, if there is any.
+func (c *applicationQuotas) Create(ctx context.Context, applicationQuota *v1.ApplicationQuota, opts metav1.CreateOptions) (result *v1.ApplicationQuota, err error) {
+	result = &v1.ApplicationQuota{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("applicationquotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(applicationQuota).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a applicationQuota and updates it. Returns the server's representation of the applicationQuota, and an error, if there is any.
+func (c *applicationQuotas) Update(ctx context.Context, applicationQuota *v1.ApplicationQuota, opts metav1.UpdateOptions) (result *v1.ApplicationQuota, err error) {
+	result = &v1.ApplicationQuota{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("applicationquotas").
+		Name(applicationQuota.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(applicationQuota).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *applicationQuotas) UpdateStatus(ctx context.Context, applicationQuota *v1.ApplicationQuota, opts metav1.UpdateOptions) (result *v1.ApplicationQuota, err error) {
+	result = &v1.ApplicationQuota{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("applicationquotas").
+		Name(applicationQuota.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(applicationQuota).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the applicationQuota and deletes it. Returns an error if one occurs.
+func (c *applicationQuotas) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("applicationquotas").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *applicationQuotas) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("applicationquotas").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched applicationQuota.
+func (c *applicationQuotas) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ApplicationQuota, err error) {
+	result = &v1.ApplicationQuota{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("applicationquotas").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,166 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	scheme "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ApplicationTemplatesGetter has a method to return a ApplicationTemplateInterface.
+// A group's client should implement this interface.
+type ApplicationTemplatesGetter interface {
+	ApplicationTemplates(namespace string) ApplicationTemplateInterface
+}
+
+// ApplicationTemplateInterface has methods to work with ApplicationTemplate resources.
+type ApplicationTemplateInterface interface {
+	Create(ctx context.Context, applicationTemplate *v1.ApplicationTemplate, opts metav1.CreateOptions) (*v1.ApplicationTemplate, error)
+	Update(ctx context.Context, applicationTemplate *v1.ApplicationTemplate, opts metav1.UpdateOptions) (*v1.ApplicationTemplate, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ApplicationTemplate, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.ApplicationTemplateList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ApplicationTemplate, err error)
+	ApplicationTemplateExpansion
+}
+
+// applicationTemplates implements ApplicationTemplateInterface
+type applicationTemplates struct {
+	client rest.Interface
+	ns     string
+}
+
+// newApplicationTemplates returns a ApplicationTemplates
+func newApplicationTemplates(c *CloudestV1Client, namespace string) *applicationTemplates {
+	return &applicationTemplates{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the applicationTemplate, and returns the corresponding applicationTemplate object, and an error if there is any.
+func (c *applicationTemplates) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ApplicationTemplate, err error) {
+	result = &v1.ApplicationTemplate{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("applicationtemplates").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ApplicationTemplates that match those selectors.
+func (c *applicationTemplates) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ApplicationTemplateList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ApplicationTemplateList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("applicationtemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested applicationTemplates.
+func (c *applicationTemplates) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("applicationtemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a applicationTemplate and creates it.  Returns the server's representation of the applicationTemplate, and an error, if there is any.
+func (c *applicationTemplates) Create(ctx context.Context, applicationTemplate *v1.ApplicationTemplate, opts metav1.CreateOptions) (result *v1.ApplicationTemplate, err error) {
+	result = &v1.ApplicationTemplate{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("applicationtemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(applicationTemplate).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a applicationTemplate and updates it. Returns the server's representation of the applicationTemplate, and an error, if there is any.
+func (c *applicationTemplates) Update(ctx context.Context, applicationTemplate *v1.ApplicationTemplate, opts metav1.UpdateOptions) (result *v1.ApplicationTemplate, err error) {
+	result = &v1.ApplicationTemplate{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("applicationtemplates").
+		Name(applicationTemplate.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(applicationTemplate).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the applicationTemplate and deletes it. Returns an error if one occurs.
+func (c *applicationTemplates) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("applicationtemplates").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *applicationTemplates) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("applicationtemplates").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched applicationTemplate.
+func (c *applicationTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ApplicationTemplate, err error) {
+	result = &v1.ApplicationTemplate{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("applicationtemplates").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
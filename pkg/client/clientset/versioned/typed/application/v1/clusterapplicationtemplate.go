@@ -0,0 +1,156 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	scheme "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ClusterApplicationTemplatesGetter has a method to return a ClusterApplicationTemplateInterface.
+// A group's client should implement this interface.
+type ClusterApplicationTemplatesGetter interface {
+	ClusterApplicationTemplates() ClusterApplicationTemplateInterface
+}
+
+// ClusterApplicationTemplateInterface has methods to work with ClusterApplicationTemplate resources.
+type ClusterApplicationTemplateInterface interface {
+	Create(ctx context.Context, clusterApplicationTemplate *v1.ClusterApplicationTemplate, opts metav1.CreateOptions) (*v1.ClusterApplicationTemplate, error)
+	Update(ctx context.Context, clusterApplicationTemplate *v1.ClusterApplicationTemplate, opts metav1.UpdateOptions) (*v1.ClusterApplicationTemplate, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ClusterApplicationTemplate, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.ClusterApplicationTemplateList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ClusterApplicationTemplate, err error)
+	ClusterApplicationTemplateExpansion
+}
+
+// clusterApplicationTemplates implements ClusterApplicationTemplateInterface
+type clusterApplicationTemplates struct {
+	client rest.Interface
+}
+
+// newClusterApplicationTemplates returns a ClusterApplicationTemplates
+func newClusterApplicationTemplates(c *CloudestV1Client) *clusterApplicationTemplates {
+	return &clusterApplicationTemplates{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the clusterApplicationTemplate, and returns the corresponding clusterApplicationTemplate object, and an error if there is any.
+func (c *clusterApplicationTemplates) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ClusterApplicationTemplate, err error) {
+	result = &v1.ClusterApplicationTemplate{}
+	err = c.client.Get().
+		Resource("clusterapplicationtemplates").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ClusterApplicationTemplates that match those selectors.
+func (c *clusterApplicationTemplates) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ClusterApplicationTemplateList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ClusterApplicationTemplateList{}
+	err = c.client.Get().
+		Resource("clusterapplicationtemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested clusterApplicationTemplates.
+func (c *clusterApplicationTemplates) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("clusterapplicationtemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a clusterApplicationTemplate and creates it.  Returns the server's representation of the clusterApplicationTemplate, and an error, if there is any.
+func (c *clusterApplicationTemplates) Create(ctx context.Context, clusterApplicationTemplate *v1.ClusterApplicationTemplate, opts metav1.CreateOptions) (result *v1.ClusterApplicationTemplate, err error) {
+	result = &v1.ClusterApplicationTemplate{}
+	err = c.client.Post().
+		Resource("clusterapplicationtemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterApplicationTemplate).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a clusterApplicationTemplate and updates it. Returns the server's representation of the clusterApplicationTemplate, and an error, if there is any.
+func (c *clusterApplicationTemplates) Update(ctx context.Context, clusterApplicationTemplate *v1.ClusterApplicationTemplate, opts metav1.UpdateOptions) (result *v1.ClusterApplicationTemplate, err error) {
+	result = &v1.ClusterApplicationTemplate{}
+	err = c.client.Put().
+		Resource("clusterapplicationtemplates").
+		Name(clusterApplicationTemplate.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterApplicationTemplate).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the clusterApplicationTemplate and deletes it. Returns an error if one occurs.
+func (c *clusterApplicationTemplates) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("clusterapplicationtemplates").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *clusterApplicationTemplates) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("clusterapplicationtemplates").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched clusterApplicationTemplate.
+func (c *clusterApplicationTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ClusterApplicationTemplate, err error) {
+	result = &v1.ClusterApplicationTemplate{}
+	err = c.client.Patch(pt).
+		Resource("clusterapplicationtemplates").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
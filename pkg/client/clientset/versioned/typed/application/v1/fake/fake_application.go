@@ -105,7 +105,7 @@ func (c *FakeApplications) UpdateStatus(ctx context.Context, application *applic
 // Delete takes name of the application and deletes it. Returns an error if one occurs.
 func (c *FakeApplications) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
-		Invokes(testing.NewDeleteAction(applicationsResource, c.ns, name), &applicationv1.Application{})
+		Invokes(testing.NewDeleteActionWithOptions(applicationsResource, c.ns, name, opts), &applicationv1.Application{})
 
 	return err
 }
@@ -20,6 +20,26 @@ func (c *FakeCloudestV1) Applications(namespace string) v1.ApplicationInterface
 	return &FakeApplications{c, namespace}
 }
 
+func (c *FakeCloudestV1) ApplicationPolicies() v1.ApplicationPolicyInterface {
+	return &FakeApplicationPolicies{c}
+}
+
+func (c *FakeCloudestV1) ApplicationQuotas(namespace string) v1.ApplicationQuotaInterface {
+	return &FakeApplicationQuotas{c, namespace}
+}
+
+func (c *FakeCloudestV1) ApplicationRevisions(namespace string) v1.ApplicationRevisionInterface {
+	return &FakeApplicationRevisions{c, namespace}
+}
+
+func (c *FakeCloudestV1) ApplicationTemplates(namespace string) v1.ApplicationTemplateInterface {
+	return &FakeApplicationTemplates{c, namespace}
+}
+
+func (c *FakeCloudestV1) ClusterApplicationTemplates() v1.ClusterApplicationTemplateInterface {
+	return &FakeClusterApplicationTemplates{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeCloudestV1) RESTClient() rest.Interface {
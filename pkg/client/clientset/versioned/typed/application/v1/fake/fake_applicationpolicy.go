@@ -0,0 +1,110 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	applicationv1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeApplicationPolicies implements ApplicationPolicyInterface
+type FakeApplicationPolicies struct {
+	Fake *FakeCloudestV1
+}
+
+var applicationpoliciesResource = schema.GroupVersionResource{Group: "cloudest.artifakt.io", Version: "v1", Resource: "applicationpolicies"}
+
+var applicationpoliciesKind = schema.GroupVersionKind{Group: "cloudest.artifakt.io", Version: "v1", Kind: "ApplicationPolicy"}
+
+// Get takes name of the applicationPolicy, and returns the corresponding applicationPolicy object, and an error if there is any.
+func (c *FakeApplicationPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *applicationv1.ApplicationPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(applicationpoliciesResource, name), &applicationv1.ApplicationPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of ApplicationPolicies that match those selectors.
+func (c *FakeApplicationPolicies) List(ctx context.Context, opts v1.ListOptions) (result *applicationv1.ApplicationPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(applicationpoliciesResource, applicationpoliciesKind, opts), &applicationv1.ApplicationPolicyList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &applicationv1.ApplicationPolicyList{ListMeta: obj.(*applicationv1.ApplicationPolicyList).ListMeta}
+	for _, item := range obj.(*applicationv1.ApplicationPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested applicationPolicies.
+func (c *FakeApplicationPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(applicationpoliciesResource, opts))
+}
+
+// Create takes the representation of a applicationPolicy and creates it.  Returns the server's representation of the applicationPolicy, and an error, if there is any.
+func (c *FakeApplicationPolicies) Create(ctx context.Context, applicationPolicy *applicationv1.ApplicationPolicy, opts v1.CreateOptions) (result *applicationv1.ApplicationPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(applicationpoliciesResource, applicationPolicy), &applicationv1.ApplicationPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationPolicy), err
+}
+
+// Update takes the representation of a applicationPolicy and updates it. Returns the server's representation of the applicationPolicy, and an error, if there is any.
+func (c *FakeApplicationPolicies) Update(ctx context.Context, applicationPolicy *applicationv1.ApplicationPolicy, opts v1.UpdateOptions) (result *applicationv1.ApplicationPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(applicationpoliciesResource, applicationPolicy), &applicationv1.ApplicationPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationPolicy), err
+}
+
+// Delete takes name of the applicationPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeApplicationPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(applicationpoliciesResource, name, opts), &applicationv1.ApplicationPolicy{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeApplicationPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(applicationpoliciesResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &applicationv1.ApplicationPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched applicationPolicy.
+func (c *FakeApplicationPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *applicationv1.ApplicationPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(applicationpoliciesResource, name, pt, data, subresources...), &applicationv1.ApplicationPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationPolicy), err
+}
@@ -0,0 +1,130 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	applicationv1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeApplicationQuotas implements ApplicationQuotaInterface
+type FakeApplicationQuotas struct {
+	Fake *FakeCloudestV1
+	ns   string
+}
+
+var applicationquotasResource = schema.GroupVersionResource{Group: "cloudest.artifakt.io", Version: "v1", Resource: "applicationquotas"}
+
+var applicationquotasKind = schema.GroupVersionKind{Group: "cloudest.artifakt.io", Version: "v1", Kind: "ApplicationQuota"}
+
+// Get takes name of the applicationQuota, and returns the corresponding applicationQuota object, and an error if there is any.
+func (c *FakeApplicationQuotas) Get(ctx context.Context, name string, options v1.GetOptions) (result *applicationv1.ApplicationQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(applicationquotasResource, c.ns, name), &applicationv1.ApplicationQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationQuota), err
+}
+
+// List takes label and field selectors, and returns the list of ApplicationQuotas that match those selectors.
+func (c *FakeApplicationQuotas) List(ctx context.Context, opts v1.ListOptions) (result *applicationv1.ApplicationQuotaList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(applicationquotasResource, applicationquotasKind, c.ns, opts), &applicationv1.ApplicationQuotaList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &applicationv1.ApplicationQuotaList{ListMeta: obj.(*applicationv1.ApplicationQuotaList).ListMeta}
+	for _, item := range obj.(*applicationv1.ApplicationQuotaList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested applicationQuotas.
+func (c *FakeApplicationQuotas) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(applicationquotasResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a applicationQuota and creates it.  Returns the server's representation of the applicationQuota, and an error, if there is any.
+func (c *FakeApplicationQuotas) Create(ctx context.Context, applicationQuota *applicationv1.ApplicationQuota, opts v1.CreateOptions) (result *applicationv1.ApplicationQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(applicationquotasResource, c.ns, applicationQuota), &applicationv1.ApplicationQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationQuota), err
+}
+
+// Update takes the representation of a applicationQuota and updates it. Returns the server's representation of the applicationQuota, and an error, if there is any.
+func (c *FakeApplicationQuotas) Update(ctx context.Context, applicationQuota *applicationv1.ApplicationQuota, opts v1.UpdateOptions) (result *applicationv1.ApplicationQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(applicationquotasResource, c.ns, applicationQuota), &applicationv1.ApplicationQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationQuota), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeApplicationQuotas) UpdateStatus(ctx context.Context, applicationQuota *applicationv1.ApplicationQuota, opts v1.UpdateOptions) (*applicationv1.ApplicationQuota, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(applicationquotasResource, "status", c.ns, applicationQuota), &applicationv1.ApplicationQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationQuota), err
+}
+
+// Delete takes name of the applicationQuota and deletes it. Returns an error if one occurs.
+func (c *FakeApplicationQuotas) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(applicationquotasResource, c.ns, name, opts), &applicationv1.ApplicationQuota{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeApplicationQuotas) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(applicationquotasResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &applicationv1.ApplicationQuotaList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched applicationQuota.
+func (c *FakeApplicationQuotas) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *applicationv1.ApplicationQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(applicationquotasResource, c.ns, name, pt, data, subresources...), &applicationv1.ApplicationQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationQuota), err
+}
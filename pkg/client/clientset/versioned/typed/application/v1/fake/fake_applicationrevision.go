@@ -0,0 +1,118 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	applicationv1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeApplicationRevisions implements ApplicationRevisionInterface
+type FakeApplicationRevisions struct {
+	Fake *FakeCloudestV1
+	ns   string
+}
+
+var applicationrevisionsResource = schema.GroupVersionResource{Group: "cloudest.artifakt.io", Version: "v1", Resource: "applicationrevisions"}
+
+var applicationrevisionsKind = schema.GroupVersionKind{Group: "cloudest.artifakt.io", Version: "v1", Kind: "ApplicationRevision"}
+
+// Get takes name of the applicationRevision, and returns the corresponding applicationRevision object, and an error if there is any.
+func (c *FakeApplicationRevisions) Get(ctx context.Context, name string, options v1.GetOptions) (result *applicationv1.ApplicationRevision, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(applicationrevisionsResource, c.ns, name), &applicationv1.ApplicationRevision{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationRevision), err
+}
+
+// List takes label and field selectors, and returns the list of ApplicationRevisions that match those selectors.
+func (c *FakeApplicationRevisions) List(ctx context.Context, opts v1.ListOptions) (result *applicationv1.ApplicationRevisionList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(applicationrevisionsResource, applicationrevisionsKind, c.ns, opts), &applicationv1.ApplicationRevisionList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &applicationv1.ApplicationRevisionList{ListMeta: obj.(*applicationv1.ApplicationRevisionList).ListMeta}
+	for _, item := range obj.(*applicationv1.ApplicationRevisionList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested applicationRevisions.
+func (c *FakeApplicationRevisions) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(applicationrevisionsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a applicationRevision and creates it.  Returns the server's representation of the applicationRevision, and an error, if there is any.
+func (c *FakeApplicationRevisions) Create(ctx context.Context, applicationRevision *applicationv1.ApplicationRevision, opts v1.CreateOptions) (result *applicationv1.ApplicationRevision, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(applicationrevisionsResource, c.ns, applicationRevision), &applicationv1.ApplicationRevision{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationRevision), err
+}
+
+// Update takes the representation of a applicationRevision and updates it. Returns the server's representation of the applicationRevision, and an error, if there is any.
+func (c *FakeApplicationRevisions) Update(ctx context.Context, applicationRevision *applicationv1.ApplicationRevision, opts v1.UpdateOptions) (result *applicationv1.ApplicationRevision, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(applicationrevisionsResource, c.ns, applicationRevision), &applicationv1.ApplicationRevision{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationRevision), err
+}
+
+// Delete takes name of the applicationRevision and deletes it. Returns an error if one occurs.
+func (c *FakeApplicationRevisions) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(applicationrevisionsResource, c.ns, name, opts), &applicationv1.ApplicationRevision{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeApplicationRevisions) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(applicationrevisionsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &applicationv1.ApplicationRevisionList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched applicationRevision.
+func (c *FakeApplicationRevisions) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *applicationv1.ApplicationRevision, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(applicationrevisionsResource, c.ns, name, pt, data, subresources...), &applicationv1.ApplicationRevision{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationRevision), err
+}
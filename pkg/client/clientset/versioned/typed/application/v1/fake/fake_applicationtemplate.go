@@ -0,0 +1,118 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	applicationv1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeApplicationTemplates implements ApplicationTemplateInterface
+type FakeApplicationTemplates struct {
+	Fake *FakeCloudestV1
+	ns   string
+}
+
+var applicationtemplatesResource = schema.GroupVersionResource{Group: "cloudest.artifakt.io", Version: "v1", Resource: "applicationtemplates"}
+
+var applicationtemplatesKind = schema.GroupVersionKind{Group: "cloudest.artifakt.io", Version: "v1", Kind: "ApplicationTemplate"}
+
+// Get takes name of the applicationTemplate, and returns the corresponding applicationTemplate object, and an error if there is any.
+func (c *FakeApplicationTemplates) Get(ctx context.Context, name string, options v1.GetOptions) (result *applicationv1.ApplicationTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(applicationtemplatesResource, c.ns, name), &applicationv1.ApplicationTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationTemplate), err
+}
+
+// List takes label and field selectors, and returns the list of ApplicationTemplates that match those selectors.
+func (c *FakeApplicationTemplates) List(ctx context.Context, opts v1.ListOptions) (result *applicationv1.ApplicationTemplateList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(applicationtemplatesResource, applicationtemplatesKind, c.ns, opts), &applicationv1.ApplicationTemplateList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &applicationv1.ApplicationTemplateList{ListMeta: obj.(*applicationv1.ApplicationTemplateList).ListMeta}
+	for _, item := range obj.(*applicationv1.ApplicationTemplateList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested applicationTemplates.
+func (c *FakeApplicationTemplates) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(applicationtemplatesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a applicationTemplate and creates it.  Returns the server's representation of the applicationTemplate, and an error, if there is any.
+func (c *FakeApplicationTemplates) Create(ctx context.Context, applicationTemplate *applicationv1.ApplicationTemplate, opts v1.CreateOptions) (result *applicationv1.ApplicationTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(applicationtemplatesResource, c.ns, applicationTemplate), &applicationv1.ApplicationTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationTemplate), err
+}
+
+// Update takes the representation of a applicationTemplate and updates it. Returns the server's representation of the applicationTemplate, and an error, if there is any.
+func (c *FakeApplicationTemplates) Update(ctx context.Context, applicationTemplate *applicationv1.ApplicationTemplate, opts v1.UpdateOptions) (result *applicationv1.ApplicationTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(applicationtemplatesResource, c.ns, applicationTemplate), &applicationv1.ApplicationTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationTemplate), err
+}
+
+// Delete takes name of the applicationTemplate and deletes it. Returns an error if one occurs.
+func (c *FakeApplicationTemplates) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(applicationtemplatesResource, c.ns, name, opts), &applicationv1.ApplicationTemplate{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeApplicationTemplates) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(applicationtemplatesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &applicationv1.ApplicationTemplateList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched applicationTemplate.
+func (c *FakeApplicationTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *applicationv1.ApplicationTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(applicationtemplatesResource, c.ns, name, pt, data, subresources...), &applicationv1.ApplicationTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ApplicationTemplate), err
+}
@@ -0,0 +1,110 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	applicationv1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeClusterApplicationTemplates implements ClusterApplicationTemplateInterface
+type FakeClusterApplicationTemplates struct {
+	Fake *FakeCloudestV1
+}
+
+var clusterapplicationtemplatesResource = schema.GroupVersionResource{Group: "cloudest.artifakt.io", Version: "v1", Resource: "clusterapplicationtemplates"}
+
+var clusterapplicationtemplatesKind = schema.GroupVersionKind{Group: "cloudest.artifakt.io", Version: "v1", Kind: "ClusterApplicationTemplate"}
+
+// Get takes name of the clusterApplicationTemplate, and returns the corresponding clusterApplicationTemplate object, and an error if there is any.
+func (c *FakeClusterApplicationTemplates) Get(ctx context.Context, name string, options v1.GetOptions) (result *applicationv1.ClusterApplicationTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(clusterapplicationtemplatesResource, name), &applicationv1.ClusterApplicationTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ClusterApplicationTemplate), err
+}
+
+// List takes label and field selectors, and returns the list of ClusterApplicationTemplates that match those selectors.
+func (c *FakeClusterApplicationTemplates) List(ctx context.Context, opts v1.ListOptions) (result *applicationv1.ClusterApplicationTemplateList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(clusterapplicationtemplatesResource, clusterapplicationtemplatesKind, opts), &applicationv1.ClusterApplicationTemplateList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &applicationv1.ClusterApplicationTemplateList{ListMeta: obj.(*applicationv1.ClusterApplicationTemplateList).ListMeta}
+	for _, item := range obj.(*applicationv1.ClusterApplicationTemplateList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested clusterApplicationTemplates.
+func (c *FakeClusterApplicationTemplates) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(clusterapplicationtemplatesResource, opts))
+}
+
+// Create takes the representation of a clusterApplicationTemplate and creates it.  Returns the server's representation of the clusterApplicationTemplate, and an error, if there is any.
+func (c *FakeClusterApplicationTemplates) Create(ctx context.Context, clusterApplicationTemplate *applicationv1.ClusterApplicationTemplate, opts v1.CreateOptions) (result *applicationv1.ClusterApplicationTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(clusterapplicationtemplatesResource, clusterApplicationTemplate), &applicationv1.ClusterApplicationTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ClusterApplicationTemplate), err
+}
+
+// Update takes the representation of a clusterApplicationTemplate and updates it. Returns the server's representation of the clusterApplicationTemplate, and an error, if there is any.
+func (c *FakeClusterApplicationTemplates) Update(ctx context.Context, clusterApplicationTemplate *applicationv1.ClusterApplicationTemplate, opts v1.UpdateOptions) (result *applicationv1.ClusterApplicationTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(clusterapplicationtemplatesResource, clusterApplicationTemplate), &applicationv1.ClusterApplicationTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ClusterApplicationTemplate), err
+}
+
+// Delete takes name of the clusterApplicationTemplate and deletes it. Returns an error if one occurs.
+func (c *FakeClusterApplicationTemplates) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(clusterapplicationtemplatesResource, name, opts), &applicationv1.ClusterApplicationTemplate{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeClusterApplicationTemplates) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(clusterapplicationtemplatesResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &applicationv1.ClusterApplicationTemplateList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched clusterApplicationTemplate.
+func (c *FakeClusterApplicationTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *applicationv1.ClusterApplicationTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clusterapplicationtemplatesResource, name, pt, data, subresources...), &applicationv1.ClusterApplicationTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*applicationv1.ClusterApplicationTemplate), err
+}
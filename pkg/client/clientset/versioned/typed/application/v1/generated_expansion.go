@@ -7,3 +7,13 @@ Artifakt Platform generated code
 package v1
 
 type ApplicationExpansion interface{}
+
+type ApplicationPolicyExpansion interface{}
+
+type ApplicationQuotaExpansion interface{}
+
+type ApplicationRevisionExpansion interface{}
+
+type ApplicationTemplateExpansion interface{}
+
+type ClusterApplicationTemplateExpansion interface{}
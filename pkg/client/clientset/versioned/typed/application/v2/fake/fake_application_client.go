@@ -0,0 +1,28 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v2 "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned/typed/application/v2"
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+)
+
+type FakeCloudestV2 struct {
+	*testing.Fake
+}
+
+func (c *FakeCloudestV2) Applications(namespace string) v2.ApplicationInterface {
+	return &FakeApplications{c, namespace}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeCloudestV2) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
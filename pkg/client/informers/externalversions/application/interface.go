@@ -8,6 +8,7 @@ package application
 
 import (
 	v1 "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/application/v1"
+	v2 "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/application/v2"
 	internalinterfaces "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/internalinterfaces"
 )
 
@@ -15,6 +16,8 @@ import (
 type Interface interface {
 	// V1 provides access to shared informers for resources in V1.
 	V1() v1.Interface
+	// V2 provides access to shared informers for resources in V2.
+	V2() v2.Interface
 }
 
 type group struct {
@@ -32,3 +35,8 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (g *group) V1() v1.Interface {
 	return v1.New(g.factory, g.namespace, g.tweakListOptions)
 }
+
+// V2 returns a new v2.Interface.
+func (g *group) V2() v2.Interface {
+	return v2.New(g.factory, g.namespace, g.tweakListOptions)
+}
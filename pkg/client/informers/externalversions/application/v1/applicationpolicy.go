@@ -0,0 +1,77 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	applicationv1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	versioned "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// ApplicationPolicyInformer provides access to a shared informer and lister for
+// ApplicationPolicies.
+type ApplicationPolicyInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.ApplicationPolicyLister
+}
+
+type applicationPolicyInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewApplicationPolicyInformer constructs a new informer for ApplicationPolicy type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewApplicationPolicyInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredApplicationPolicyInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredApplicationPolicyInformer constructs a new informer for ApplicationPolicy type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredApplicationPolicyInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CloudestV1().ApplicationPolicies().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CloudestV1().ApplicationPolicies().Watch(context.TODO(), options)
+			},
+		},
+		&applicationv1.ApplicationPolicy{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *applicationPolicyInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredApplicationPolicyInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *applicationPolicyInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&applicationv1.ApplicationPolicy{}, f.defaultInformer)
+}
+
+func (f *applicationPolicyInformer) Lister() v1.ApplicationPolicyLister {
+	return v1.NewApplicationPolicyLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,78 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	applicationv1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	versioned "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// ApplicationQuotaInformer provides access to a shared informer and lister for
+// ApplicationQuotas.
+type ApplicationQuotaInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.ApplicationQuotaLister
+}
+
+type applicationQuotaInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewApplicationQuotaInformer constructs a new informer for ApplicationQuota type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewApplicationQuotaInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredApplicationQuotaInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredApplicationQuotaInformer constructs a new informer for ApplicationQuota type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredApplicationQuotaInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CloudestV1().ApplicationQuotas(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CloudestV1().ApplicationQuotas(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&applicationv1.ApplicationQuota{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *applicationQuotaInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredApplicationQuotaInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *applicationQuotaInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&applicationv1.ApplicationQuota{}, f.defaultInformer)
+}
+
+func (f *applicationQuotaInformer) Lister() v1.ApplicationQuotaLister {
+	return v1.NewApplicationQuotaLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,78 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	applicationv1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	versioned "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// ApplicationRevisionInformer provides access to a shared informer and lister for
+// ApplicationRevisions.
+type ApplicationRevisionInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.ApplicationRevisionLister
+}
+
+type applicationRevisionInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewApplicationRevisionInformer constructs a new informer for ApplicationRevision type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewApplicationRevisionInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredApplicationRevisionInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredApplicationRevisionInformer constructs a new informer for ApplicationRevision type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredApplicationRevisionInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CloudestV1().ApplicationRevisions(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CloudestV1().ApplicationRevisions(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&applicationv1.ApplicationRevision{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *applicationRevisionInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredApplicationRevisionInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *applicationRevisionInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&applicationv1.ApplicationRevision{}, f.defaultInformer)
+}
+
+func (f *applicationRevisionInformer) Lister() v1.ApplicationRevisionLister {
+	return v1.NewApplicationRevisionLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,78 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	applicationv1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	versioned "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// ApplicationTemplateInformer provides access to a shared informer and lister for
+// ApplicationTemplates.
+type ApplicationTemplateInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.ApplicationTemplateLister
+}
+
+type applicationTemplateInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewApplicationTemplateInformer constructs a new informer for ApplicationTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewApplicationTemplateInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredApplicationTemplateInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredApplicationTemplateInformer constructs a new informer for ApplicationTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredApplicationTemplateInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CloudestV1().ApplicationTemplates(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CloudestV1().ApplicationTemplates(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&applicationv1.ApplicationTemplate{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *applicationTemplateInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredApplicationTemplateInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *applicationTemplateInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&applicationv1.ApplicationTemplate{}, f.defaultInformer)
+}
+
+func (f *applicationTemplateInformer) Lister() v1.ApplicationTemplateLister {
+	return v1.NewApplicationTemplateLister(f.Informer().GetIndexer())
+}
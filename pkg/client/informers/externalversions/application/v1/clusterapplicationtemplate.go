@@ -0,0 +1,77 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	applicationv1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	versioned "github.com/artifakt-io/demo-controller/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/artifakt-io/demo-controller/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/artifakt-io/demo-controller/pkg/client/listers/application/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// ClusterApplicationTemplateInformer provides access to a shared informer and lister for
+// ClusterApplicationTemplates.
+type ClusterApplicationTemplateInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.ClusterApplicationTemplateLister
+}
+
+type clusterApplicationTemplateInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewClusterApplicationTemplateInformer constructs a new informer for ClusterApplicationTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewClusterApplicationTemplateInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredClusterApplicationTemplateInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredClusterApplicationTemplateInformer constructs a new informer for ClusterApplicationTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredClusterApplicationTemplateInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CloudestV1().ClusterApplicationTemplates().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CloudestV1().ClusterApplicationTemplates().Watch(context.TODO(), options)
+			},
+		},
+		&applicationv1.ClusterApplicationTemplate{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *clusterApplicationTemplateInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredClusterApplicationTemplateInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *clusterApplicationTemplateInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&applicationv1.ClusterApplicationTemplate{}, f.defaultInformer)
+}
+
+func (f *clusterApplicationTemplateInformer) Lister() v1.ClusterApplicationTemplateLister {
+	return v1.NewClusterApplicationTemplateLister(f.Informer().GetIndexer())
+}
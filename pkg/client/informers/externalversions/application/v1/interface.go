@@ -14,6 +14,16 @@ import (
 type Interface interface {
 	// Applications returns a ApplicationInformer.
 	Applications() ApplicationInformer
+	// ApplicationPolicies returns a ApplicationPolicyInformer.
+	ApplicationPolicies() ApplicationPolicyInformer
+	// ApplicationQuotas returns a ApplicationQuotaInformer.
+	ApplicationQuotas() ApplicationQuotaInformer
+	// ApplicationRevisions returns a ApplicationRevisionInformer.
+	ApplicationRevisions() ApplicationRevisionInformer
+	// ApplicationTemplates returns a ApplicationTemplateInformer.
+	ApplicationTemplates() ApplicationTemplateInformer
+	// ClusterApplicationTemplates returns a ClusterApplicationTemplateInformer.
+	ClusterApplicationTemplates() ClusterApplicationTemplateInformer
 }
 
 type version struct {
@@ -31,3 +41,28 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) Applications() ApplicationInformer {
 	return &applicationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// ApplicationPolicies returns a ApplicationPolicyInformer.
+func (v *version) ApplicationPolicies() ApplicationPolicyInformer {
+	return &applicationPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ApplicationQuotas returns a ApplicationQuotaInformer.
+func (v *version) ApplicationQuotas() ApplicationQuotaInformer {
+	return &applicationQuotaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ApplicationRevisions returns a ApplicationRevisionInformer.
+func (v *version) ApplicationRevisions() ApplicationRevisionInformer {
+	return &applicationRevisionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ApplicationTemplates returns a ApplicationTemplateInformer.
+func (v *version) ApplicationTemplates() ApplicationTemplateInformer {
+	return &applicationTemplateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterApplicationTemplates returns a ClusterApplicationTemplateInformer.
+func (v *version) ClusterApplicationTemplates() ClusterApplicationTemplateInformer {
+	return &clusterApplicationTemplateInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	v2 "github.com/artifakt-io/demo-controller/pkg/apis/application/v2"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -43,6 +44,20 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=cloudest.artifakt.io, Version=v1
 	case v1.SchemeGroupVersion.WithResource("applications"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cloudest().V1().Applications().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("applicationpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cloudest().V1().ApplicationPolicies().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("applicationquotas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cloudest().V1().ApplicationQuotas().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("applicationrevisions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cloudest().V1().ApplicationRevisions().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("applicationtemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cloudest().V1().ApplicationTemplates().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("clusterapplicationtemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cloudest().V1().ClusterApplicationTemplates().Informer()}, nil
+
+		// Group=cloudest.artifakt.io, Version=v2
+	case v2.SchemeGroupVersion.WithResource("applications"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cloudest().V2().Applications().Informer()}, nil
 
 	}
 
@@ -0,0 +1,56 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ApplicationPolicyLister helps list ApplicationPolicies.
+// All objects returned here must be treated as read-only.
+type ApplicationPolicyLister interface {
+	// List lists all ApplicationPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ApplicationPolicy, err error)
+	// Get retrieves the ApplicationPolicy from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.ApplicationPolicy, error)
+	ApplicationPolicyListerExpansion
+}
+
+// applicationPolicyLister implements the ApplicationPolicyLister interface.
+type applicationPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewApplicationPolicyLister returns a new ApplicationPolicyLister.
+func NewApplicationPolicyLister(indexer cache.Indexer) ApplicationPolicyLister {
+	return &applicationPolicyLister{indexer: indexer}
+}
+
+// List lists all ApplicationPolicies in the indexer.
+func (s *applicationPolicyLister) List(selector labels.Selector) (ret []*v1.ApplicationPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ApplicationPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the ApplicationPolicy from the index for a given name.
+func (s *applicationPolicyLister) Get(name string) (*v1.ApplicationPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("applicationpolicy"), name)
+	}
+	return obj.(*v1.ApplicationPolicy), nil
+}
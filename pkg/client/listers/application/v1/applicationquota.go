@@ -0,0 +1,87 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ApplicationQuotaLister helps list ApplicationQuotas.
+// All objects returned here must be treated as read-only.
+type ApplicationQuotaLister interface {
+	// List lists all ApplicationQuotas in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ApplicationQuota, err error)
+	// ApplicationQuotas returns an object that can list and get ApplicationQuotas.
+	ApplicationQuotas(namespace string) ApplicationQuotaNamespaceLister
+	ApplicationQuotaListerExpansion
+}
+
+// applicationQuotaLister implements the ApplicationQuotaLister interface.
+type applicationQuotaLister struct {
+	indexer cache.Indexer
+}
+
+// NewApplicationQuotaLister returns a new ApplicationQuotaLister.
+func NewApplicationQuotaLister(indexer cache.Indexer) ApplicationQuotaLister {
+	return &applicationQuotaLister{indexer: indexer}
+}
+
+// List lists all ApplicationQuotas in the indexer.
+func (s *applicationQuotaLister) List(selector labels.Selector) (ret []*v1.ApplicationQuota, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ApplicationQuota))
+	})
+	return ret, err
+}
+
+// ApplicationQuotas returns an object that can list and get ApplicationQuotas.
+func (s *applicationQuotaLister) ApplicationQuotas(namespace string) ApplicationQuotaNamespaceLister {
+	return applicationQuotaNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ApplicationQuotaNamespaceLister helps list and get ApplicationQuotas.
+// All objects returned here must be treated as read-only.
+type ApplicationQuotaNamespaceLister interface {
+	// List lists all ApplicationQuotas in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ApplicationQuota, err error)
+	// Get retrieves the ApplicationQuota from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.ApplicationQuota, error)
+	ApplicationQuotaNamespaceListerExpansion
+}
+
+// applicationQuotaNamespaceLister implements the ApplicationQuotaNamespaceLister
+// interface.
+type applicationQuotaNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ApplicationQuotas in the indexer for a given namespace.
+func (s applicationQuotaNamespaceLister) List(selector labels.Selector) (ret []*v1.ApplicationQuota, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ApplicationQuota))
+	})
+	return ret, err
+}
+
+// Get retrieves the ApplicationQuota from the indexer for a given namespace and name.
+func (s applicationQuotaNamespaceLister) Get(name string) (*v1.ApplicationQuota, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("applicationquota"), name)
+	}
+	return obj.(*v1.ApplicationQuota), nil
+}
@@ -0,0 +1,87 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ApplicationRevisionLister helps list ApplicationRevisions.
+// All objects returned here must be treated as read-only.
+type ApplicationRevisionLister interface {
+	// List lists all ApplicationRevisions in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ApplicationRevision, err error)
+	// ApplicationRevisions returns an object that can list and get ApplicationRevisions.
+	ApplicationRevisions(namespace string) ApplicationRevisionNamespaceLister
+	ApplicationRevisionListerExpansion
+}
+
+// applicationRevisionLister implements the ApplicationRevisionLister interface.
+type applicationRevisionLister struct {
+	indexer cache.Indexer
+}
+
+// NewApplicationRevisionLister returns a new ApplicationRevisionLister.
+func NewApplicationRevisionLister(indexer cache.Indexer) ApplicationRevisionLister {
+	return &applicationRevisionLister{indexer: indexer}
+}
+
+// List lists all ApplicationRevisions in the indexer.
+func (s *applicationRevisionLister) List(selector labels.Selector) (ret []*v1.ApplicationRevision, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ApplicationRevision))
+	})
+	return ret, err
+}
+
+// ApplicationRevisions returns an object that can list and get ApplicationRevisions.
+func (s *applicationRevisionLister) ApplicationRevisions(namespace string) ApplicationRevisionNamespaceLister {
+	return applicationRevisionNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ApplicationRevisionNamespaceLister helps list and get ApplicationRevisions.
+// All objects returned here must be treated as read-only.
+type ApplicationRevisionNamespaceLister interface {
+	// List lists all ApplicationRevisions in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ApplicationRevision, err error)
+	// Get retrieves the ApplicationRevision from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.ApplicationRevision, error)
+	ApplicationRevisionNamespaceListerExpansion
+}
+
+// applicationRevisionNamespaceLister implements the ApplicationRevisionNamespaceLister
+// interface.
+type applicationRevisionNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ApplicationRevisions in the indexer for a given namespace.
+func (s applicationRevisionNamespaceLister) List(selector labels.Selector) (ret []*v1.ApplicationRevision, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ApplicationRevision))
+	})
+	return ret, err
+}
+
+// Get retrieves the ApplicationRevision from the indexer for a given namespace and name.
+func (s applicationRevisionNamespaceLister) Get(name string) (*v1.ApplicationRevision, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("applicationrevision"), name)
+	}
+	return obj.(*v1.ApplicationRevision), nil
+}
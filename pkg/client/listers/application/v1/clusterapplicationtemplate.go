@@ -0,0 +1,56 @@
+/*
+Artifakt Platform generated code
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/artifakt-io/demo-controller/pkg/apis/application/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ClusterApplicationTemplateLister helps list ClusterApplicationTemplates.
+// All objects returned here must be treated as read-only.
+type ClusterApplicationTemplateLister interface {
+	// List lists all ClusterApplicationTemplates in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ClusterApplicationTemplate, err error)
+	// Get retrieves the ClusterApplicationTemplate from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.ClusterApplicationTemplate, error)
+	ClusterApplicationTemplateListerExpansion
+}
+
+// clusterApplicationTemplateLister implements the ClusterApplicationTemplateLister interface.
+type clusterApplicationTemplateLister struct {
+	indexer cache.Indexer
+}
+
+// NewClusterApplicationTemplateLister returns a new ClusterApplicationTemplateLister.
+func NewClusterApplicationTemplateLister(indexer cache.Indexer) ClusterApplicationTemplateLister {
+	return &clusterApplicationTemplateLister{indexer: indexer}
+}
+
+// List lists all ClusterApplicationTemplates in the indexer.
+func (s *clusterApplicationTemplateLister) List(selector labels.Selector) (ret []*v1.ClusterApplicationTemplate, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ClusterApplicationTemplate))
+	})
+	return ret, err
+}
+
+// Get retrieves the ClusterApplicationTemplate from the index for a given name.
+func (s *clusterApplicationTemplateLister) Get(name string) (*v1.ClusterApplicationTemplate, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("clusterapplicationtemplate"), name)
+	}
+	return obj.(*v1.ClusterApplicationTemplate), nil
+}
@@ -13,3 +13,35 @@ type ApplicationListerExpansion interface{}
 // ApplicationNamespaceListerExpansion allows custom methods to be added to
 // ApplicationNamespaceLister.
 type ApplicationNamespaceListerExpansion interface{}
+
+// ApplicationPolicyListerExpansion allows custom methods to be added to
+// ApplicationPolicyLister.
+type ApplicationPolicyListerExpansion interface{}
+
+// ApplicationQuotaListerExpansion allows custom methods to be added to
+// ApplicationQuotaLister.
+type ApplicationQuotaListerExpansion interface{}
+
+// ApplicationQuotaNamespaceListerExpansion allows custom methods to be added to
+// ApplicationQuotaNamespaceLister.
+type ApplicationQuotaNamespaceListerExpansion interface{}
+
+// ApplicationRevisionListerExpansion allows custom methods to be added to
+// ApplicationRevisionLister.
+type ApplicationRevisionListerExpansion interface{}
+
+// ApplicationRevisionNamespaceListerExpansion allows custom methods to be added to
+// ApplicationRevisionNamespaceLister.
+type ApplicationRevisionNamespaceListerExpansion interface{}
+
+// ApplicationTemplateListerExpansion allows custom methods to be added to
+// ApplicationTemplateLister.
+type ApplicationTemplateListerExpansion interface{}
+
+// ApplicationTemplateNamespaceListerExpansion allows custom methods to be added to
+// ApplicationTemplateNamespaceLister.
+type ApplicationTemplateNamespaceListerExpansion interface{}
+
+// ClusterApplicationTemplateListerExpansion allows custom methods to be added to
+// ClusterApplicationTemplateLister.
+type ClusterApplicationTemplateListerExpansion interface{}